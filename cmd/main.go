@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path/filepath"
+	"time"
 
 	_ "github.com/arafat-hasan/mealsync/docs"
 	"github.com/arafat-hasan/mealsync/internal/api"
 	"github.com/arafat-hasan/mealsync/internal/config"
+	"github.com/arafat-hasan/mealsync/internal/idempotency"
+	"github.com/arafat-hasan/mealsync/internal/mailer"
 	"github.com/arafat-hasan/mealsync/internal/middleware"
 	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/scheduler"
 	"github.com/arafat-hasan/mealsync/internal/service"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -46,9 +51,14 @@ func main() {
 	}
 
 	// Initialize database connection
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	// Sessions run in UTC so that instants are stored and compared independent of the server's zone
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=UTC",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPass, cfg.DBName)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -62,18 +72,67 @@ func main() {
 	MenuItemCommentRepo := repository.NewMenuItemCommentRepository(db)
 	eventAddressRepo := repository.NewEventAddressRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	seriesRepo := repository.NewMealEventSeriesRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	templateRepo := repository.NewMealEventTemplateRepository(db)
+	mealTypeDefaultRepo := repository.NewMealTypeDefaultRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	standingOrderRepo := repository.NewStandingOrderRepository(db)
+	guestRequestRepo := repository.NewGuestRequestRepository(db)
+	noShowPolicyRepo := repository.NewNoShowPolicyRepository(db)
+	mealTransferRepo := repository.NewMealTransferRepository(db)
+
+	// Emails are only logged when no SMTP server is configured
+	var mail mailer.Mailer = mailer.NewLogMailer()
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+
+	// Idempotency-Key responses are shared through the database unless kept in memory
+	var idempotencyStore idempotency.Store = idempotency.NewPostgresStore(db)
+	if cfg.IdempotencyStore == "memory" {
+		idempotencyStore = idempotency.NewMemoryStore()
+	}
 
 	// Initialize services
 	authService := service.NewAuthService(db, cfg)
-	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, cfg.NotificationRetentionDays)
+	calendarService := service.NewCalendarService(
+		calendarFeedRepo,
+		mealEventRepo,
+		mealRequestRepo,
+		mail,
+		cfg.PublicURL,
+		cfg.MailFrom,
+		cfg.TimeZone,
+	)
+	standingOrderService := service.NewStandingOrderService(
+		standingOrderRepo,
+		mealEventRepo,
+		menuSetRepo,
+		menuItemRepo,
+		mealRequestRepo,
+		guestRequestRepo,
+		userRepo,
+		noShowPolicyRepo,
+		notificationService,
+		cfg.TimeZone,
+	)
 	mealEventService := service.NewMealEventService(
 		mealEventRepo,
 		userRepo,
 		menuSetRepo,
 		eventAddressRepo,
 		mealRequestRepo,
+		guestRequestRepo,
 		MenuItemCommentRepo,
+		mealTypeDefaultRepo,
+		holidayRepo,
 		notificationService,
+		calendarService,
+		standingOrderService,
+		service.ClosurePolicy(cfg.ClosurePolicy),
+		cfg.TimeZone,
 	)
 	menuSetService := service.NewMenuSetService(
 		menuSetRepo,
@@ -88,6 +147,65 @@ func main() {
 		mealRequestRepo,
 		mealEventRepo,
 		userRepo,
+		guestRequestRepo,
+		menuSetRepo,
+		noShowPolicyRepo,
+		notificationService,
+		calendarService,
+		cfg.TimeZone,
+	)
+	guestRequestService := service.NewGuestRequestService(
+		guestRequestRepo,
+		mealEventRepo,
+		mealRequestRepo,
+		menuSetRepo,
+		userRepo,
+		notificationService,
+		cfg.TimeZone,
+	)
+	mealPlanService := service.NewMealPlanService(
+		mealEventRepo,
+		mealRequestRepo,
+		guestRequestRepo,
+		menuSetRepo,
+		noShowPolicyRepo,
+		userRepo,
+		notificationService,
+		calendarService,
+		cfg.TimeZone,
+	)
+	pickupService := service.NewMealPickupService(
+		mealEventRepo,
+		mealRequestRepo,
+		userRepo,
+		cfg.PickupSigningKey,
+		cfg.PickupTokenTTL,
+		cfg.NoShowGrace,
+	)
+	noShowPolicyService := service.NewNoShowPolicyService(
+		noShowPolicyRepo,
+		mealRequestRepo,
+		userRepo,
+		notificationService,
+		cfg.TimeZone,
+	)
+	transferService := service.NewMealTransferService(
+		mealTransferRepo,
+		mealRequestRepo,
+		mealEventRepo,
+		userRepo,
+		notificationService,
+		calendarService,
+		cfg.TimeZone,
+	)
+	teamService := service.NewTeamService(
+		userRepo,
+		mealEventRepo,
+		mealRequestRepo,
+		mealRequestService,
+		notificationService,
+		calendarService,
+		cfg.TimeZone,
 	)
 	MenuItemCommentService := service.NewMenuItemCommentService(
 		MenuItemCommentRepo,
@@ -95,15 +213,90 @@ func main() {
 		userRepo,
 		menuItemRepo,
 	)
+	digestService := service.NewDigestService(
+		userRepo,
+		notificationRepo,
+		mealEventRepo,
+		mealRequestRepo,
+		MenuItemCommentRepo,
+		cfg.DigestHour,
+		cfg.DigestWeekday,
+		cfg.TimeZone,
+	)
+	seriesService := service.NewMealEventSeriesService(seriesRepo, mealEventRepo, holidayRepo, mealEventService, cfg.TimeZone)
+	holidayService := service.NewHolidayService(holidayRepo, mealEventRepo, mealEventService, cfg.TimeZone)
+	templateService := service.NewMealEventTemplateService(templateRepo, mealEventRepo, holidayRepo, service.ClosurePolicy(cfg.ClosurePolicy), cfg.TimeZone)
+	mealTypeDefaultService := service.NewMealTypeDefaultService(mealTypeDefaultRepo)
+	estimationService := service.NewEstimationService(mealEventRepo, mealRequestRepo, guestRequestRepo, menuSetRepo)
+	timeZoneService := service.NewTimeZoneService(userRepo, cfg.TimeZone)
+	dietaryService := service.NewDietaryService(userRepo)
 
 	// Initialize handlers
 	authHandler := api.NewAuthHandler(authService)
-	mealEventHandler := api.NewMealEventHandler(mealEventService)
+	mealEventHandler := api.NewMealEventHandler(mealEventService, timeZoneService)
 	menuSetHandler := api.NewMenuSetHandler(menuSetService)
 	menuItemHandler := api.NewMenuItemHandler(menuItemService)
 	mealRequestHandler := api.NewMealRequestHandler(mealRequestService)
 	MenuItemCommentHandler := api.NewMenuItemCommentHandler(MenuItemCommentService)
 	notificationHandler := api.NewNotificationHandler(notificationService)
+	digestHandler := api.NewDigestHandler(digestService)
+	seriesHandler := api.NewMealEventSeriesHandler(seriesService)
+	holidayHandler := api.NewHolidayHandler(holidayService)
+	templateHandler := api.NewMealEventTemplateHandler(templateService)
+	mealTypeDefaultHandler := api.NewMealTypeDefaultHandler(mealTypeDefaultService)
+	estimationHandler := api.NewEstimationHandler(estimationService, timeZoneService)
+	timeZoneHandler := api.NewTimeZoneHandler(timeZoneService)
+	dietaryHandler := api.NewDietaryHandler(dietaryService)
+	calendarHandler := api.NewCalendarHandler(calendarService)
+	standingOrderHandler := api.NewStandingOrderHandler(standingOrderService)
+	guestRequestHandler := api.NewGuestRequestHandler(guestRequestService)
+	teamHandler := api.NewTeamHandler(teamService, timeZoneService)
+	mealPlanHandler := api.NewMealPlanHandler(mealPlanService, timeZoneService)
+	pickupHandler := api.NewMealPickupHandler(pickupService, timeZoneService)
+	noShowPolicyHandler := api.NewNoShowPolicyHandler(noShowPolicyService)
+	transferHandler := api.NewMealTransferHandler(transferService)
+
+	// Initialize background jobs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := scheduler.New()
+	jobs.Register(scheduler.Job{
+		Name:     "notification-digests",
+		Interval: time.Hour,
+		Run:      digestService.SendDueDigests,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "notification-retention",
+		Interval: 24 * time.Hour,
+		Run:      notificationService.PurgeReadNotifications,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "meal-event-series",
+		Interval: time.Hour,
+		Run:      seriesService.GenerateEvents,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "close-meal-events",
+		Interval: time.Minute,
+		Run:      mealEventService.CloseDueEvents,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "meal-no-shows",
+		Interval: 15 * time.Minute,
+		Run:      pickupService.MarkNoShows,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "no-show-policies",
+		Interval: 15 * time.Minute,
+		Run:      noShowPolicyService.EvaluatePolicies,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "idempotency-keys",
+		Interval: time.Hour,
+		Run:      idempotencyStore.DeleteExpired,
+	})
+	jobs.Start(ctx)
 
 	// Initialize router with custom middleware
 	router := gin.Default()
@@ -118,7 +311,7 @@ func main() {
 	router.LoadHTMLGlob(filepath.Join("docs", "*.html"))
 
 	// API routes
-	api.SetupRoutes(router, cfg, authHandler, mealEventHandler, menuSetHandler, MenuItemCommentHandler, menuItemHandler, mealRequestHandler, notificationHandler)
+	api.SetupRoutes(router, cfg, idempotencyStore, authHandler, mealEventHandler, menuSetHandler, MenuItemCommentHandler, menuItemHandler, mealRequestHandler, notificationHandler, digestHandler, seriesHandler, holidayHandler, templateHandler, mealTypeDefaultHandler, estimationHandler, timeZoneHandler, calendarHandler, standingOrderHandler, guestRequestHandler, teamHandler, mealPlanHandler, pickupHandler, noShowPolicyHandler, transferHandler, dietaryHandler)
 
 	// Documentation routes with custom configuration
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler,
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarHandler handles iCalendar feed requests
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+// NewCalendarHandler creates a new instance of CalendarHandler
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// CalendarFeedRequest represents the request body for creating a calendar feed
+type CalendarFeedRequest struct {
+	Kind model.CalendarFeedKind `json:"kind" binding:"required" enums:"events,personal" example:"personal"`
+}
+
+// GetFeed godoc
+// @Summary Fetch calendar feed
+// @Description Serves an iCalendar feed for calendar clients. The secret token in the URL authorizes the request.
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally followed by .ics"
+// @Success 200 {string} string "iCalendar document"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /calendar/{token} [get]
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	data, err := h.calendarService.RenderFeed(c.Request.Context(), token)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+// GetFeeds godoc
+// @Summary List calendar feeds
+// @Description Retrieves the current user's calendar feed URLs
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.CalendarFeed
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /calendar-feeds [get]
+func (h *CalendarHandler) GetFeeds(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	feeds, err := h.calendarService.ListFeeds(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feeds)
+}
+
+// CreateFeed godoc
+// @Summary Create calendar feed
+// @Description Issues a feed URL with all upcoming meal events (events) or the user's own requested meals (personal). A previous URL of the same kind stops working.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body CalendarFeedRequest true "Feed kind"
+// @Success 201 {object} model.CalendarFeed
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /calendar-feeds [post]
+func (h *CalendarHandler) CreateFeed(c *gin.Context) {
+	var req CalendarFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	feed, err := h.calendarService.CreateFeed(c.Request.Context(), userID, req.Kind)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, feed)
+}
+
+// RevokeFeed godoc
+// @Summary Revoke calendar feed
+// @Description Stops a calendar feed URL from working
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param feed_id path int true "Feed ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /calendar-feeds/{feed_id} [delete]
+func (h *CalendarHandler) RevokeFeed(c *gin.Context) {
+	feedID, err := strconv.ParseUint(c.Param("feed_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.calendarService.RevokeFeed(c.Request.Context(), uint(feedID), userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked"})
+}
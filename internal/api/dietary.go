@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// DietaryHandler handles dietary profile requests
+type DietaryHandler struct {
+	dietaryService service.DietaryService
+}
+
+// NewDietaryHandler creates a new instance of DietaryHandler
+func NewDietaryHandler(dietaryService service.DietaryService) *DietaryHandler {
+	return &DietaryHandler{
+		dietaryService: dietaryService,
+	}
+}
+
+// GetDietaryProfile godoc
+// @Summary Get dietary profile
+// @Description Retrieves the allergens the current user avoids and the dietary tags their meals should carry
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} service.DietaryProfile
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /profile/dietary [get]
+func (h *DietaryHandler) GetDietaryProfile(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	profile, err := h.dietaryService.GetDietaryProfile(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateDietaryProfile godoc
+// @Summary Update dietary profile
+// @Description Replaces the allergens the current user avoids and the dietary tags their meals should carry. Meal requests for items that do not fit the profile are still accepted, but come back with warnings.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body service.DietaryProfile true "Dietary profile"
+// @Success 200 {object} service.DietaryProfile
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /profile/dietary [put]
+func (h *DietaryHandler) UpdateDietaryProfile(c *gin.Context) {
+	var req service.DietaryProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	profile, err := h.dietaryService.UpdateDietaryProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// DigestHandler handles notification digest-related API requests
+type DigestHandler struct {
+	digestService service.DigestService
+}
+
+// NewDigestHandler creates a new instance of DigestHandler
+func NewDigestHandler(digestService service.DigestService) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// DigestPreferenceRequest represents the request body for updating digest preferences
+type DigestPreferenceRequest struct {
+	Frequency model.DigestFrequency `json:"frequency" binding:"required" enums:"none,daily,weekly"`
+}
+
+// UpdateDigestPreference godoc
+// @Summary Update digest preference
+// @Description Opts the authenticated user in or out of daily or weekly notification digests
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body DigestPreferenceRequest true "Digest preference"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/digest [put]
+func (h *DigestHandler) UpdateDigestPreference(c *gin.Context) {
+	var req DigestPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.digestService.UpdateDigestPreference(c.Request.Context(), userID, req.Frequency); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Digest preference updated"})
+}
+
+// PreviewDigest godoc
+// @Summary Preview digest
+// @Description Builds the digest the authenticated user would receive now without sending it
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} service.Digest
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/digest/preview [get]
+func (h *DigestHandler) PreviewDigest(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	digest, err := h.digestService.BuildDigest(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, digest)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// EstimationHandler handles meal estimation requests
+type EstimationHandler struct {
+	estimationService service.EstimationService
+	timeZoneService   service.TimeZoneService
+}
+
+// NewEstimationHandler creates a new instance of EstimationHandler
+func NewEstimationHandler(estimationService service.EstimationService, timeZoneService service.TimeZoneService) *EstimationHandler {
+	return &EstimationHandler{
+		estimationService: estimationService,
+		timeZoneService:   timeZoneService,
+	}
+}
+
+// GetEstimates godoc
+// @Summary Get meal estimates
+// @Description Counts the requested meals per menu set for the events within a date range, grouped per event or per meal type. Dates are calendar days in the organization time zone. Each estimate also counts the meals containing each allergen, most common first, so the kitchen can plan substitutions. Defaults to the next 7 days grouped per event.
+// @Tags estimations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param group_by query string false "Grouping" Enums(event, meal_type)
+// @Success 200 {array} service.MealEstimate
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /estimations [get]
+func (h *EstimationHandler) GetEstimates(c *gin.Context) {
+	// Kitchen planning follows the organization's calendar days
+	loc := h.timeZoneService.OrganizationLocation()
+	startDate := utils.StartOfDay(time.Now(), loc)
+	endDate := utils.EndOfDay(startDate.AddDate(0, 0, 6), loc)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := utils.ParseDate(value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			return
+		}
+		startDate = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := utils.ParseDate(value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			return
+		}
+		endDate = utils.EndOfDay(parsed, loc)
+	}
+
+	groupBy := service.EstimateGrouping(c.DefaultQuery("group_by", string(service.EstimateByEvent)))
+	estimates, err := h.estimationService.GetEstimates(c.Request.Context(), startDate, endDate, groupBy)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimates)
+}
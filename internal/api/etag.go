@@ -0,0 +1,72 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag derives the ETag of a versioned resource. It starts with the version so that If-Match
+// can be checked against the stored version, and ends with a hash of the representation so that
+// If-None-Match also notices changes to nested records that do not raise the version.
+func versionETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%d-%s"`, version, hex.EncodeToString(sum[:8]))
+}
+
+// writeVersioned writes a versioned resource as JSON with its ETag
+func writeVersioned(c *gin.Context, status int, version int, resource interface{}) {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		handleError(c, errors.NewInternalError("failed to encode response", err))
+		return
+	}
+	c.Header("ETag", versionETag(version, body))
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// ifMatchVersion reads the version a PUT or DELETE request expects from its If-Match header and
+// answers 428 Precondition Required when there is none. Weak tags and tags that were not issued
+// by this API yield version 0, which no resource has, so the request fails with 412.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		handleError(c, errors.NewPreconditionRequiredError("If-Match header with the ETag of the resource is required", nil))
+		return 0, false
+	}
+
+	tag := strings.TrimSpace(strings.Split(header, ",")[0])
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, true
+	}
+	version, err := strconv.Atoi(strings.SplitN(tag[1:len(tag)-1], "-", 2)[0])
+	if err != nil || version < 1 {
+		return 0, true
+	}
+	return version, true
+}
+
+// handleVersionError answers a stale If-Match with 412 Precondition Failed and the current
+// representation, so the client can reapply its change without fetching it again. Other errors,
+// and resources that are gone by now, are handled as usual.
+func handleVersionError(c *gin.Context, err error, current func() (interface{}, int, error)) {
+	appErr, ok := err.(*errors.AppError)
+	if !ok || appErr.Type != errors.ErrorTypePreconditionFailed {
+		handleError(c, err)
+		return
+	}
+
+	resource, version, loadErr := current()
+	if loadErr != nil {
+		handleError(c, err)
+		return
+	}
+	writeVersioned(c, http.StatusPreconditionFailed, version, resource)
+}
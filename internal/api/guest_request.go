@@ -0,0 +1,510 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GuestRequestHandler handles guest meal requests
+type GuestRequestHandler struct {
+	guestRequestService service.GuestRequestService
+}
+
+// NewGuestRequestHandler creates a new instance of GuestRequestHandler
+func NewGuestRequestHandler(guestRequestService service.GuestRequestService) *GuestRequestHandler {
+	return &GuestRequestHandler{
+		guestRequestService: guestRequestService,
+	}
+}
+
+// GuestRequestItemRequest represents a menu item chosen for a guest
+type GuestRequestItemRequest struct {
+	MenuItemID uint   `json:"menu_item_id" binding:"required" example:"7"`
+	IsSelected *bool  `json:"is_selected"` // defaults to true
+	Quantity   int    `json:"quantity" example:"1"`
+	Notes      string `json:"notes"`
+}
+
+// GuestRequestRequest represents the request body for creating or changing a guest request
+type GuestRequestRequest struct {
+	MealEventID    uint                      `json:"meal_event_id" example:"12"` // ignored when changing a request
+	HostUserID     uint                      `json:"host_user_id" example:"3"`   // admins only; defaults to the caller
+	GuestName      string                    `json:"guest_name" binding:"required" example:"Jane Doe"`
+	Organization   string                    `json:"organization" example:"Acme Ltd."`
+	MenuSetID      uint                      `json:"menu_set_id" binding:"required" example:"2"`
+	EventAddressID uint                      `json:"event_address_id" binding:"required" example:"1"`
+	DietaryNotes   string                    `json:"dietary_notes" example:"Vegetarian, no nuts"`
+	Items          []GuestRequestItemRequest `json:"items"` // defaults to every item of the menu set
+}
+
+// toModel converts the request into a guest request
+func (r *GuestRequestRequest) toModel() *model.GuestRequest {
+	guest := &model.GuestRequest{
+		MealEventID:    r.MealEventID,
+		HostUserID:     r.HostUserID,
+		GuestName:      r.GuestName,
+		Organization:   r.Organization,
+		MenuSetID:      r.MenuSetID,
+		EventAddressID: r.EventAddressID,
+		DietaryNotes:   r.DietaryNotes,
+	}
+	for _, item := range r.Items {
+		guest.Items = append(guest.Items, model.GuestRequestItem{
+			MenuItemID: item.MenuItemID,
+			IsSelected: item.IsSelected == nil || *item.IsSelected,
+			Quantity:   item.Quantity,
+			Notes:      item.Notes,
+		})
+	}
+	return guest
+}
+
+// GuestReviewRequest represents the request body for approving or rejecting a guest
+type GuestReviewRequest struct {
+	Approve bool   `json:"approve" example:"true"`
+	Note    string `json:"note" example:"Client visit approved by management"`
+}
+
+// GuestQuotaRequest represents the request body for setting a department's guest quota
+type GuestQuotaRequest struct {
+	MonthlyLimit int `json:"monthly_limit" example:"20"`
+}
+
+// GetGuestRequests godoc
+// @Summary List my guests
+// @Description Retrieves the guests the current user invited
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.GuestRequest
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /guest-requests [get]
+func (h *GuestRequestHandler) GetGuestRequests(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	guests, err := h.guestRequestService.GetGuestRequests(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guests)
+}
+
+// GetPendingGuestRequests godoc
+// @Summary List guests awaiting approval
+// @Description Retrieves the guests that exceed their host department's quota and wait for an admin's approval
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.GuestRequest
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /guest-requests/pending [get]
+func (h *GuestRequestHandler) GetPendingGuestRequests(c *gin.Context) {
+	guests, err := h.guestRequestService.GetPendingGuestRequests(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guests)
+}
+
+// GetGuestRequest godoc
+// @Summary Get guest request
+// @Description Retrieves a guest request of the current user, or any for admins. The ETag header is sent back in If-Match to change or cancel it, or in If-None-Match to get 304 Not Modified while it is unchanged.
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guest_id path int true "Guest request ID"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {object} model.GuestRequest
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /guest-requests/{guest_id} [get]
+func (h *GuestRequestHandler) GetGuestRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("guest_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest request ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	guest, err := h.guestRequestService.GetGuestRequest(c.Request.Context(), uint(id), userID, utils.IsAdminFromContext(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, guest.Version, guest)
+}
+
+// CreateGuestRequest godoc
+// @Summary Invite a guest
+// @Description Requests a meal for a visitor without an account, hosted by the current user. Guests count towards estimates and location capacity. Guests beyond the host department's monthly quota wait for an admin's approval.
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guest body GuestRequestRequest true "Guest request"
+// @Success 201 {object} model.GuestRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /guest-requests [post]
+func (h *GuestRequestHandler) CreateGuestRequest(c *gin.Context) {
+	var req GuestRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	guest := req.toModel()
+	if err := h.guestRequestService.CreateGuestRequest(c.Request.Context(), guest, userID, utils.IsAdminFromContext(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, guest)
+}
+
+// UpdateGuestRequest godoc
+// @Summary Change guest request
+// @Description Changes a guest's details or meal. Hosts may do so until the cutoff. If-Match must carry the ETag the guest request was read with; when someone else changed it since, the request fails with 412 and the current guest request.
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guest_id path int true "Guest request ID"
+// @Param If-Match header string true "ETag of the guest request"
+// @Param guest body GuestRequestRequest true "Guest request"
+// @Success 200 {object} model.GuestRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 412 {object} model.GuestRequest "Current guest request"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /guest-requests/{guest_id} [put]
+func (h *GuestRequestHandler) UpdateGuestRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("guest_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest request ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req GuestRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	guest := req.toModel()
+	if err := h.guestRequestService.UpdateGuestRequest(c.Request.Context(), uint(id), version, guest, userID, utils.IsAdminFromContext(c)); err != nil {
+		handleVersionError(c, err, h.currentGuestRequest(c, uint(id), userID))
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, guest.Version, guest)
+}
+
+// CancelGuestRequest godoc
+// @Summary Cancel guest request
+// @Description Cancels a guest's meal. Hosts may do so until the cutoff. If-Match must carry the ETag the guest request was read with; when someone else changed it since, the request fails with 412 and the current guest request.
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guest_id path int true "Guest request ID"
+// @Param If-Match header string true "ETag of the guest request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 412 {object} model.GuestRequest "Current guest request"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Router /guest-requests/{guest_id} [delete]
+func (h *GuestRequestHandler) CancelGuestRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("guest_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest request ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.guestRequestService.CancelGuestRequest(c.Request.Context(), uint(id), version, userID, utils.IsAdminFromContext(c)); err != nil {
+		handleVersionError(c, err, h.currentGuestRequest(c, uint(id), userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Guest request cancelled"})
+}
+
+// currentGuestRequest loads the stored guest request for a 412 response
+func (h *GuestRequestHandler) currentGuestRequest(c *gin.Context, id uint, userID uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		guest, err := h.guestRequestService.GetGuestRequest(c.Request.Context(), id, userID, utils.IsAdminFromContext(c))
+		if err != nil {
+			return nil, 0, err
+		}
+		return guest, guest.Version, nil
+	}
+}
+
+// ReviewGuestRequest godoc
+// @Summary Approve or reject a guest
+// @Description Approves or rejects a guest that exceeds the host department's quota. The host is notified.
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param guest_id path int true "Guest request ID"
+// @Param review body GuestReviewRequest true "Decision"
+// @Success 200 {object} model.GuestRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Router /guest-requests/{guest_id}/review [post]
+func (h *GuestRequestHandler) ReviewGuestRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("guest_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guest request ID"})
+		return
+	}
+
+	var req GuestReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	guest, err := h.guestRequestService.ReviewGuestRequest(c.Request.Context(), uint(id), req.Approve, req.Note, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guest)
+}
+
+// GetEventGuests godoc
+// @Summary List guests of a meal event
+// @Description Retrieves every guest request of a meal event
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param meal_id path int true "Meal Event ID"
+// @Success 200 {array} model.GuestRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /meals/{meal_id}/guests [get]
+func (h *GuestRequestHandler) GetEventGuests(c *gin.Context) {
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal event ID"})
+		return
+	}
+
+	guests, err := h.guestRequestService.GetEventGuests(c.Request.Context(), uint(mealID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guests)
+}
+
+// ExportAttendees godoc
+// @Summary Export attendees of a meal event
+// @Description Lists the employees and guests expected at a meal event, each in their own section. With format=csv a CSV file is returned in which a type column tells employees and guests apart.
+// @Tags guests
+// @Accept json
+// @Produce json,text/csv
+// @Security ApiKeyAuth
+// @Param meal_id path int true "Meal Event ID"
+// @Param format query string false "Output format" Enums(json, csv)
+// @Success 200 {object} service.EventAttendees
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /meals/{meal_id}/attendees [get]
+func (h *GuestRequestHandler) ExportAttendees(c *gin.Context) {
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal event ID"})
+		return
+	}
+
+	attendees, err := h.guestRequestService.GetAttendees(c.Request.Context(), uint(mealID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, attendees)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"meal-%d-attendees.csv\"", attendees.MealEventID))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"type", "name", "employee_id", "department", "host", "organization", "menu_set", "address", "status", "notes"})
+	for _, rows := range [][]service.Attendee{attendees.Employees, attendees.Guests} {
+		for _, a := range rows {
+			_ = w.Write([]string{a.Type, a.Name, a.EmployeeID, a.Department, a.Host, a.Organization, a.MenuSet, a.Address, a.Status, a.Notes})
+		}
+	}
+	w.Flush()
+}
+
+// GetGuestQuotas godoc
+// @Summary List department guest quotas
+// @Description Retrieves the monthly guest quota of every department that has one
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.DepartmentGuestQuota
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /guest-quotas [get]
+func (h *GuestRequestHandler) GetGuestQuotas(c *gin.Context) {
+	quotas, err := h.guestRequestService.ListGuestQuotas(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotas)
+}
+
+// SetGuestQuota godoc
+// @Summary Set department guest quota
+// @Description Sets how many approved guests a department may host per calendar month before further guests need an admin's approval
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param department path string true "Department"
+// @Param quota body GuestQuotaRequest true "Quota"
+// @Success 200 {object} model.DepartmentGuestQuota
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /guest-quotas/{department} [put]
+func (h *GuestRequestHandler) SetGuestQuota(c *gin.Context) {
+	var req GuestQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	quota, err := h.guestRequestService.SetGuestQuota(c.Request.Context(), c.Param("department"), req.MonthlyLimit, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// DeleteGuestQuota godoc
+// @Summary Delete department guest quota
+// @Description Removes a department's guest quota, so that its guests no longer need approval
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param department path string true "Department"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /guest-quotas/{department} [delete]
+func (h *GuestRequestHandler) DeleteGuestQuota(c *gin.Context) {
+	if err := h.guestRequestService.DeleteGuestQuota(c.Request.Context(), c.Param("department")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Guest quota deleted"})
+}
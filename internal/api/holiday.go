@@ -0,0 +1,293 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// HolidayHandler handles holiday calendar requests
+type HolidayHandler struct {
+	holidayService service.HolidayService
+}
+
+// NewHolidayHandler creates a new instance of HolidayHandler
+func NewHolidayHandler(holidayService service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService: holidayService,
+	}
+}
+
+// maxHolidayImportSize limits the size of uploaded iCalendar files
+const maxHolidayImportSize = 1 << 20
+
+// HolidayRequest represents the request body for adding a holiday or office closure
+type HolidayRequest struct {
+	Date           string `json:"date" binding:"required" example:"2025-12-16"`
+	Name           string `json:"name" binding:"required" example:"Victory Day"`
+	EventAddressID *uint  `json:"event_address_id" example:"2"` // omit for an organization-wide holiday
+}
+
+// CancelAffectedEventsRequest represents the request body for cancelling the meal events on a closure
+type CancelAffectedEventsRequest struct {
+	MealEventIDs []uint `json:"meal_event_ids" example:"12,13"` // omit to cancel every event closed at all of its addresses
+	Reason       string `json:"reason" example:"Office closed for maintenance"`
+}
+
+// GetHolidays godoc
+// @Summary List holidays
+// @Description Retrieves the holidays within a date range. Defaults to the next 30 days.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Success 200 {array} model.Holiday
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /holidays [get]
+func (h *HolidayHandler) GetHolidays(c *gin.Context) {
+	startDate := time.Now().Truncate(24 * time.Hour)
+	endDate := startDate.AddDate(0, 0, 30)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			return
+		}
+		startDate = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			return
+		}
+		endDate = parsed
+	}
+
+	holidays, err := h.holidayService.ListHolidays(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+// CreateHoliday godoc
+// @Summary Add holiday
+// @Description Adds an organization-wide holiday or a closure of one address. Recurring series do not generate events on closed dates. Meal events already scheduled on the date are returned as affected_events.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body HolidayRequest true "Holiday"
+// @Success 201 {object} model.Holiday
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /holidays [post]
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	var req HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	holiday := model.Holiday{Date: date, Name: req.Name, EventAddressID: req.EventAddressID}
+	if err := h.holidayService.CreateHoliday(c.Request.Context(), &holiday, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+// DeleteHoliday godoc
+// @Summary Remove holiday
+// @Description Removes a date from the holiday calendar
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param holiday_id path int true "Holiday ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /holidays/{holiday_id} [delete]
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	holidayID, err := strconv.ParseUint(c.Param("holiday_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+		return
+	}
+
+	if err := h.holidayService.DeleteHoliday(c.Request.Context(), uint(holidayID)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday removed"})
+}
+
+// ImportHolidays godoc
+// @Summary Import holidays
+// @Description Adds the dates of every event in an iCalendar (.ics) file to the closure calendar. Dates that are already closed are skipped. Recurrence rules are not expanded.
+// @Tags holidays
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param file formData file true "iCalendar file"
+// @Param event_address_id formData int false "Limit the closures to this address"
+// @Success 201 {object} service.HolidayImport
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /holidays/import [post]
+func (h *HolidayHandler) ImportHolidays(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An iCalendar file is required"})
+		return
+	}
+	if fileHeader.Size > maxHolidayImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The iCalendar file is too large"})
+		return
+	}
+
+	var addressID *uint
+	if value := c.PostForm("event_address_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event address ID"})
+			return
+		}
+		id := uint(parsed)
+		addressID = &id
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the iCalendar file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.holidayService.ImportHolidays(c.Request.Context(), file, addressID, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetAffectedEvents godoc
+// @Summary List events affected by a closure
+// @Description Retrieves the meal events that are still scheduled on a closed date at a closed address. Events that stay open at some of their addresses carry a warning.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param holiday_id path int true "Holiday ID"
+// @Success 200 {array} model.MealEvent
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /holidays/{holiday_id}/affected-events [get]
+func (h *HolidayHandler) GetAffectedEvents(c *gin.Context) {
+	holidayID, err := strconv.ParseUint(c.Param("holiday_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+		return
+	}
+
+	meals, err := h.holidayService.FindAffectedEvents(c.Request.Context(), uint(holidayID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, meals)
+}
+
+// CancelAffectedEvents godoc
+// @Summary Cancel events affected by a closure
+// @Description Cancels the meal events scheduled on a closure and notifies their requesters. Without meal_event_ids every event closed at all of its addresses is cancelled. Events that stay open at some addresses are not cancelled.
+// @Tags holidays
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param holiday_id path int true "Holiday ID"
+// @Param request body CancelAffectedEventsRequest false "Events to cancel"
+// @Success 200 {array} model.MealEvent
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /holidays/{holiday_id}/cancel-events [post]
+func (h *HolidayHandler) CancelAffectedEvents(c *gin.Context) {
+	holidayID, err := strconv.ParseUint(c.Param("holiday_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+		return
+	}
+
+	var req CancelAffectedEventsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	cancelled, err := h.holidayService.CancelAffectedEvents(c.Request.Context(), uint(holidayID), req.MealEventIDs, req.Reason, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cancelled)
+}
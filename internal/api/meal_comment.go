@@ -51,14 +51,16 @@ func (h *MenuItemCommentHandler) GetComments(c *gin.Context) {
 
 // GetCommentByID handles GET /api/meals/:meal_event_id/comments/:id
 // @Summary      Get comment by ID
-// @Description  Get a specific comment by its ID
+// @Description  Get a specific comment by its ID. The ETag header is sent back in If-Match to update or delete it, or in If-None-Match to get 304 Not Modified while it is unchanged.
 // @Tags         menu-item-comments
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        meal_event_id  path      int  true  "Meal Event ID"
-// @Param        id            path      int  true  "Comment ID"
+// @Param        meal_event_id  path      int     true   "Meal Event ID"
+// @Param        id             path      int     true   "Comment ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
 // @Success      200          {object}  model.MenuItemComment
+// @Success      304          "Not Modified"
 // @Failure      400          {object}  ErrorResponse
 // @Failure      401          {object}  ErrorResponse
 // @Failure      404          {object}  ErrorResponse
@@ -77,7 +79,7 @@ func (h *MenuItemCommentHandler) GetCommentByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, comment)
+	writeVersioned(c, http.StatusOK, comment.Version, comment)
 }
 
 // CreateComment handles POST /api/meals/:meal_event_id/comments
@@ -122,19 +124,22 @@ func (h *MenuItemCommentHandler) CreateComment(c *gin.Context) {
 
 // UpdateComment handles PUT /api/meals/:meal_event_id/comments/:id
 // @Summary      Update comment
-// @Description  Update an existing comment
+// @Description  Update an existing comment. If-Match must carry the ETag the comment was read with; when someone else changed it since, the update fails with 412 and the current comment.
 // @Tags         menu-item-comments
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        meal_event_id  path           int             true  "Meal Event ID"
 // @Param        id            path           int             true  "Comment ID"
+// @Param        If-Match      header         string          true  "ETag of the comment"
 // @Param        comment        body           model.MenuItemComment true  "Comment Data"
 // @Success      200          {object}       model.MenuItemComment
 // @Failure      400          {object}       ErrorResponse
 // @Failure      401          {object}       ErrorResponse
 // @Failure      403          {object}       ErrorResponse
 // @Failure      404          {object}       ErrorResponse
+// @Failure      412          {object}       model.MenuItemComment  "Current comment"
+// @Failure      428          {object}       ErrorResponse
 // @Failure      500          {object}       ErrorResponse
 // @Router       /meals/{meal_event_id}/comments/{id} [put]
 func (h *MenuItemCommentHandler) UpdateComment(c *gin.Context) {
@@ -144,6 +149,11 @@ func (h *MenuItemCommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var comment model.MenuItemComment
 	if err := c.ShouldBindJSON(&comment); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
@@ -152,28 +162,31 @@ func (h *MenuItemCommentHandler) UpdateComment(c *gin.Context) {
 
 	userID := uint(1) // TODO: Get from context after auth
 
-	if err := h.menuItemCommentService.UpdateComment(c.Request.Context(), uint(id), &comment, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.menuItemCommentService.UpdateComment(c.Request.Context(), uint(id), version, &comment, userID); err != nil {
+		handleVersionError(c, err, h.currentComment(c, uint(id)))
 		return
 	}
 
-	c.JSON(http.StatusOK, comment)
+	writeVersioned(c, http.StatusOK, comment.Version, comment)
 }
 
 // DeleteComment handles DELETE /api/meals/:meal_event_id/comments/:id
 // @Summary      Delete comment
-// @Description  Delete an existing comment
+// @Description  Delete an existing comment. If-Match must carry the ETag the comment was read with; when someone else changed it since, the deletion fails with 412 and the current comment.
 // @Tags         menu-item-comments
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        meal_event_id  path      int  true  "Meal Event ID"
-// @Param        id            path      int  true  "Comment ID"
+// @Param        meal_event_id  path      int     true  "Meal Event ID"
+// @Param        id             path      int     true  "Comment ID"
+// @Param        If-Match       header    string  true  "ETag of the comment"
 // @Success      200          {object}  SuccessResponse
 // @Failure      400          {object}  ErrorResponse
 // @Failure      401          {object}  ErrorResponse
 // @Failure      403          {object}  ErrorResponse
 // @Failure      404          {object}  ErrorResponse
+// @Failure      412          {object}  model.MenuItemComment  "Current comment"
+// @Failure      428          {object}  ErrorResponse
 // @Failure      500          {object}  ErrorResponse
 // @Router       /meals/{meal_event_id}/comments/{id} [delete]
 func (h *MenuItemCommentHandler) DeleteComment(c *gin.Context) {
@@ -183,16 +196,32 @@ func (h *MenuItemCommentHandler) DeleteComment(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	userID := uint(1) // TODO: Get from context after auth
 
-	if err := h.menuItemCommentService.DeleteComment(c.Request.Context(), uint(id), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.menuItemCommentService.DeleteComment(c.Request.Context(), uint(id), version, userID); err != nil {
+		handleVersionError(c, err, h.currentComment(c, uint(id)))
 		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Comment deleted successfully"})
 }
 
+// currentComment loads the stored comment for a 412 response
+func (h *MenuItemCommentHandler) currentComment(c *gin.Context, id uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		comment, err := h.menuItemCommentService.GetCommentByID(c.Request.Context(), id)
+		if err != nil {
+			return nil, 0, err
+		}
+		return comment, comment.Version, nil
+	}
+}
+
 // GetUserComments handles GET /api/users/:user_id/comments
 // @Summary      List user comments
 // @Description  Get all comments made by a specific user
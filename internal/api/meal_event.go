@@ -13,30 +13,33 @@ import (
 
 // MealEventHandler handles meal event-related requests
 type MealEventHandler struct {
-	mealService service.MealEventService
+	mealService     service.MealEventService
+	timeZoneService service.TimeZoneService
 }
 
 // NewMealEventHandler creates a new MealEventHandler
-func NewMealEventHandler(mealService service.MealEventService) *MealEventHandler {
-	return &MealEventHandler{mealService: mealService}
+func NewMealEventHandler(mealService service.MealEventService, timeZoneService service.TimeZoneService) *MealEventHandler {
+	return &MealEventHandler{mealService: mealService, timeZoneService: timeZoneService}
 }
 
-// GetMealEventByID handles GET /api/meals/:id
+// GetMealEventByID handles GET /api/meals/:meal_id
 // @Summary      Get meal event by ID
-// @Description  Get a specific meal event by its ID
+// @Description  Get a specific meal event by its ID. The ETag header is sent back in If-Match to update or delete it, or in If-None-Match to get 304 Not Modified while it is unchanged.
 // @Tags         meals
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Meal Event ID"
+// @Param        meal_id        path      int     true   "Meal Event ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
 // @Success      200  {object}  model.MealEvent
+// @Success      304  "Not Modified"
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
-// @Router       /meals/{id} [get]
+// @Router       /meals/{meal_id} [get]
 func (h *MealEventHandler) GetMealEventByID(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
 		return
@@ -51,16 +54,16 @@ func (h *MealEventHandler) GetMealEventByID(c *gin.Context) {
 
 	meal, err := h.mealService.GetMealByID(c.Request.Context(), uint(id), userID, isAdmin)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, meal)
+	writeVersioned(c, http.StatusOK, meal.Version, meal)
 }
 
 // CreateMealEvent handles POST /api/meals
 // @Summary      Create meal event
-// @Description  Create a new meal event
+// @Description  Create a new meal event as a draft. The event must start within the next 30 days, its cutoff must come before the start and its duration must be positive. Overlapping events at the same address are reported in warnings.
 // @Tags         meals
 // @Accept       json
 // @Produce      json
@@ -85,36 +88,44 @@ func (h *MealEventHandler) CreateMealEvent(c *gin.Context) {
 	}
 
 	if err := h.mealService.CreateMeal(c.Request.Context(), &meal, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, meal)
 }
 
-// UpdateMealEvent handles PUT /api/meals/:id
+// UpdateMealEvent handles PUT /api/meals/:meal_id
 // @Summary      Update meal event
-// @Description  Update an existing meal event
+// @Description  Update an existing meal event. Menu sets and addresses are replaced when given and kept when omitted. The same rules as on creation apply; overlapping events at the same address are reported in warnings. Requesters are notified when the time, cutoff, menu sets or addresses change, and requests whose menu set or address was removed are flagged with needs_attention. If-Match must carry the ETag the meal event was read with; when someone else changed it since, the update fails with 412 and the current meal event.
 // @Tags         meals
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id    path      int             true  "Meal Event ID"
-// @Param        meal  body      model.MealEvent true  "Meal Event Data"
+// @Param        meal_id   path      int             true  "Meal Event ID"
+// @Param        If-Match  header    string          true  "ETag of the meal event"
+// @Param        meal      body      model.MealEvent true  "Meal Event Data"
 // @Success      200   {object}  model.MealEvent
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
 // @Failure      403   {object}  ErrorResponse
 // @Failure      404   {object}  ErrorResponse
+// @Failure      412   {object}  model.MealEvent  "Current meal event"
+// @Failure      428   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
-// @Router       /meals/{id} [put]
+// @Router       /meals/{meal_id} [put]
 func (h *MealEventHandler) UpdateMealEvent(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var meal model.MealEvent
 	if err := c.ShouldBindJSON(&meal); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
@@ -127,31 +138,34 @@ func (h *MealEventHandler) UpdateMealEvent(c *gin.Context) {
 		return
 	}
 
-	if err := h.mealService.UpdateMeal(c.Request.Context(), uint(id), &meal, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.mealService.UpdateMeal(c.Request.Context(), uint(id), version, &meal, userID); err != nil {
+		handleVersionError(c, err, h.currentMeal(c, uint(id), userID))
 		return
 	}
 
-	c.JSON(http.StatusOK, meal)
+	writeVersioned(c, http.StatusOK, meal.Version, meal)
 }
 
-// DeleteMealEvent handles DELETE /api/meals/:id
+// DeleteMealEvent handles DELETE /api/meals/:meal_id
 // @Summary      Delete meal event
-// @Description  Delete an existing meal event
+// @Description  Delete an existing meal event. If-Match must carry the ETag the meal event was read with; when someone else changed it since, the deletion fails with 412 and the current meal event.
 // @Tags         meals
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Meal Event ID"
+// @Param        meal_id   path      int     true  "Meal Event ID"
+// @Param        If-Match  header    string  true  "ETag of the meal event"
 // @Success      200  {object}  SuccessResponse
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      403  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
+// @Failure      412  {object}  model.MealEvent  "Current meal event"
+// @Failure      428  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
-// @Router       /meals/{id} [delete]
+// @Router       /meals/{meal_id} [delete]
 func (h *MealEventHandler) DeleteMealEvent(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
 		return
@@ -163,23 +177,40 @@ func (h *MealEventHandler) DeleteMealEvent(c *gin.Context) {
 		return
 	}
 
-	if err := h.mealService.DeleteMeal(c.Request.Context(), uint(id), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mealService.DeleteMeal(c.Request.Context(), uint(id), version, userID); err != nil {
+		handleVersionError(c, err, h.currentMeal(c, uint(id), userID))
 		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Meal event deleted successfully"})
 }
 
+// currentMeal loads the stored meal event, as the user sees it, for a 412 response
+func (h *MealEventHandler) currentMeal(c *gin.Context, id uint, userID uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		meal, err := h.mealService.GetMealByID(c.Request.Context(), id, userID, utils.IsAdminFromContext(c))
+		if err != nil {
+			return nil, 0, err
+		}
+		return meal, meal.Version, nil
+	}
+}
+
 // GetMealEventsByDateRange handles GET /api/meals/daterange
 // @Summary      List meal events by date range
-// @Description  Get meal events within a date range
+// @Description  Get meal events within a date range. Dates are calendar days in the user's time zone.
 // @Tags         meals
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        start_date  query     string  true  "Start Date (YYYY-MM-DD)"
 // @Param        end_date    query     string  true  "End Date (YYYY-MM-DD)"
+// @Param        meal_type   query     string  false "Meal type (breakfast, lunch, snacks)"
 // @Success      200  {array}   model.MealEvent
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
@@ -194,21 +225,34 @@ func (h *MealEventHandler) GetMealEventsByDateRange(c *gin.Context) {
 		return
 	}
 
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	// Dates are calendar days in the user's time zone
+	loc, err := h.timeZoneService.UserLocation(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
 	// Parse dates
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	startDate, err := utils.ParseDate(startDateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid start date format. Use YYYY-MM-DD"})
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := utils.ParseDate(endDateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid end date format. Use YYYY-MM-DD"})
 		return
 	}
 
 	// Set end date to the end of the day
-	endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	endDate = utils.EndOfDay(endDate, loc)
 
 	// Validate date range
 	if startDate.After(endDate) {
@@ -216,13 +260,14 @@ func (h *MealEventHandler) GetMealEventsByDateRange(c *gin.Context) {
 		return
 	}
 
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	mealType := model.MealType(c.Query("meal_type"))
+	if mealType != "" && !mealType.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal type. Use breakfast, lunch or snacks"})
 		return
 	}
 
-	// Get meals in the date range
-	meals, err := h.mealService.FindByDateRange(c.Request.Context(), startDate, endDate)
+	// Get meals in the date range; drafts are only listed for admins
+	meals, err := h.mealService.GetMealsByDateRange(c.Request.Context(), startDate, endDate, mealType, utils.IsAdminFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
@@ -230,3 +275,175 @@ func (h *MealEventHandler) GetMealEventsByDateRange(c *gin.Context) {
 
 	c.JSON(http.StatusOK, meals)
 }
+
+// CloneMealEventRequest represents the request body for cloning a meal event
+type CloneMealEventRequest struct {
+	Dates []string `json:"dates" binding:"required,min=1" example:"2025-05-12,2025-05-13"`
+}
+
+// CloneMealEvent handles POST /api/meals/:meal_id/clone
+// @Summary      Clone meal event
+// @Description  Copy a meal event with its menu sets and addresses to one or more dates, keeping the cutoff the same time before the event
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        meal_id  path      int                    true  "Meal Event ID"
+// @Param        request  body      CloneMealEventRequest  true  "Target dates (YYYY-MM-DD)"
+// @Success      201      {array}   model.MealEvent
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meals/{meal_id}/clone [post]
+func (h *MealEventHandler) CloneMealEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	var req CloneMealEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	dates := make([]time.Time, 0, len(req.Dates))
+	for _, value := range req.Dates {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		dates = append(dates, date)
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	meals, err := h.mealService.CloneMeal(c.Request.Context(), uint(id), dates, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, meals)
+}
+
+// MealEventStatusRequest represents the request body for changing a meal event's status
+type MealEventStatusRequest struct {
+	Status model.MealEventStatus `json:"status" binding:"required" enums:"published,closed,confirmed,completed,cancelled"`
+	Reason string                `json:"reason" example:"Office closed for maintenance"`
+}
+
+// UpdateMealEventStatus handles POST /api/meals/:meal_id/status
+// @Summary      Change meal event status
+// @Description  Move a meal event through its lifecycle (draft, published, closed, confirmed, completed, cancelled). Publishing announces the event; cancelling cancels all its meal requests and notifies the requesters.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        meal_id  path      int                     true  "Meal Event ID"
+// @Param        request  body      MealEventStatusRequest  true  "New status and reason"
+// @Success      200      {object}  model.MealEvent
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meals/{meal_id}/status [post]
+func (h *MealEventHandler) UpdateMealEventStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	var req MealEventStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	meal, err := h.mealService.TransitionStatus(c.Request.Context(), uint(id), req.Status, req.Reason, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, meal)
+}
+
+// GetMealEventTransitions handles GET /api/meals/:meal_id/transitions
+// @Summary      Get meal event status history
+// @Description  List every status change of a meal event with its actor and reason
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        meal_id  path      int  true  "Meal Event ID"
+// @Success      200      {array}   model.MealEventTransition
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meals/{meal_id}/transitions [get]
+func (h *MealEventHandler) GetMealEventTransitions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	transitions, err := h.mealService.FindTransitions(c.Request.Context(), uint(id))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
+
+// GetMealEventRevisions handles GET /api/meals/:meal_id/revisions
+// @Summary      Get meal event edit history
+// @Description  List every edit of a meal event, its menu sets and its addresses with the changed fields and the editor. Material revisions were announced to the requesters.
+// @Tags         meals
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        meal_id  path      int  true  "Meal Event ID"
+// @Success      200      {array}   model.MealEventRevision
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meals/{meal_id}/revisions [get]
+func (h *MealEventHandler) GetMealEventRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	revisions, err := h.mealService.FindRevisions(c.Request.Context(), uint(id))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MealEventSeriesHandler handles recurring meal event series requests
+type MealEventSeriesHandler struct {
+	seriesService service.MealEventSeriesService
+}
+
+// NewMealEventSeriesHandler creates a new instance of MealEventSeriesHandler
+func NewMealEventSeriesHandler(seriesService service.MealEventSeriesService) *MealEventSeriesHandler {
+	return &MealEventSeriesHandler{
+		seriesService: seriesService,
+	}
+}
+
+// EditOccurrenceRequest represents the request body for editing an occurrence of a series
+type EditOccurrenceRequest struct {
+	Scope service.SeriesEditScope `json:"scope" binding:"required,oneof=this following" enums:"this,following"`
+	service.SeriesChanges
+}
+
+// GetSeries godoc
+// @Summary List meal event series
+// @Description Retrieves all recurring meal event series
+// @Tags meal-series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.MealEventSeries
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-series [get]
+func (h *MealEventSeriesHandler) GetSeries(c *gin.Context) {
+	series, err := h.seriesService.ListSeries(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// GetSeriesByID godoc
+// @Summary Get meal event series
+// @Description Retrieves a recurring meal event series with its menu sets and addresses. The ETag header is sent back in If-Match to end it, or in If-None-Match to get 304 Not Modified while it is unchanged.
+// @Tags meal-series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param series_id path int true "Series ID"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {object} model.MealEventSeries
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /meal-series/{series_id} [get]
+func (h *MealEventSeriesHandler) GetSeriesByID(c *gin.Context) {
+	seriesID, err := strconv.ParseUint(c.Param("series_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(c.Request.Context(), uint(seriesID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, series.Version, series)
+}
+
+// CreateSeries godoc
+// @Summary Create meal event series
+// @Description Creates a recurring meal event series and generates its events for the next 30 days, skipping holidays
+// @Tags meal-series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param series body model.MealEventSeries true "Series data"
+// @Success 201 {object} model.MealEventSeries
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-series [post]
+func (h *MealEventSeriesHandler) CreateSeries(c *gin.Context) {
+	var series model.MealEventSeries
+	if err := c.ShouldBindJSON(&series); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.seriesService.CreateSeries(c.Request.Context(), &series, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+// EditOccurrence godoc
+// @Summary Edit a series occurrence
+// @Description Edits one occurrence of a series. With scope "this" only that event changes; with scope "following" the series is split so the changes apply to that occurrence and every later one. If-Match must carry the ETag the meal event of the occurrence was read with; when someone else changed it since, the edit fails with 412 and the current meal event.
+// @Tags meal-series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param series_id path int true "Series ID"
+// @Param meal_id path int true "Meal event ID of the occurrence"
+// @Param If-Match header string true "ETag of the meal event"
+// @Param request body EditOccurrenceRequest true "Changes"
+// @Success 200 {object} model.MealEvent "Updated event when scope is this"
+// @Success 201 {object} model.MealEventSeries "New series when scope is following"
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 412 {object} model.MealEvent "Current meal event"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-series/{series_id}/occurrences/{meal_id} [put]
+func (h *MealEventSeriesHandler) EditOccurrence(c *gin.Context) {
+	seriesID, err := strconv.ParseUint(c.Param("series_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal event ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req EditOccurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if req.Scope == service.SeriesEditFollowing {
+		series, err := h.seriesService.SplitSeries(c.Request.Context(), uint(seriesID), uint(mealID), version, &req.SeriesChanges, userID)
+		if err != nil {
+			handleVersionError(c, err, h.currentOccurrence(c, uint(seriesID), uint(mealID)))
+			return
+		}
+		c.JSON(http.StatusCreated, series)
+		return
+	}
+
+	meal, err := h.seriesService.EditOccurrence(c.Request.Context(), uint(seriesID), uint(mealID), version, &req.SeriesChanges, userID)
+	if err != nil {
+		handleVersionError(c, err, h.currentOccurrence(c, uint(seriesID), uint(mealID)))
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, meal.Version, meal)
+}
+
+// EndSeries godoc
+// @Summary End meal event series
+// @Description Stops a series from generating events and removes its upcoming events that have no meal requests. If-Match must carry the ETag the series was read with; when someone else changed it since, the request fails with 412 and the current series.
+// @Tags meal-series
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param series_id path int true "Series ID"
+// @Param If-Match header string true "ETag of the series"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 412 {object} model.MealEventSeries "Current series"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-series/{series_id} [delete]
+func (h *MealEventSeriesHandler) EndSeries(c *gin.Context) {
+	seriesID, err := strconv.ParseUint(c.Param("series_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.seriesService.EndSeries(c.Request.Context(), uint(seriesID), version, userID); err != nil {
+		handleVersionError(c, err, h.currentSeries(c, uint(seriesID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meal event series ended"})
+}
+
+// currentSeries loads the stored series for a 412 response
+func (h *MealEventSeriesHandler) currentSeries(c *gin.Context, id uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		series, err := h.seriesService.GetSeries(c.Request.Context(), id)
+		if err != nil {
+			return nil, 0, err
+		}
+		return series, series.Version, nil
+	}
+}
+
+// currentOccurrence loads the stored meal event of an occurrence for a 412 response
+func (h *MealEventSeriesHandler) currentOccurrence(c *gin.Context, seriesID uint, mealID uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		meal, err := h.seriesService.GetOccurrence(c.Request.Context(), seriesID, mealID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return meal, meal.Version, nil
+	}
+}
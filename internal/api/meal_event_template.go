@@ -0,0 +1,269 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MealEventTemplateHandler handles meal event template requests
+type MealEventTemplateHandler struct {
+	templateService service.MealEventTemplateService
+}
+
+// NewMealEventTemplateHandler creates a new instance of MealEventTemplateHandler
+func NewMealEventTemplateHandler(templateService service.MealEventTemplateService) *MealEventTemplateHandler {
+	return &MealEventTemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// TemplateEventRequest represents the request body for creating an event from a template
+type TemplateEventRequest struct {
+	Date string `json:"date" binding:"required" example:"2025-05-12"`
+}
+
+// GetTemplates godoc
+// @Summary List meal event templates
+// @Description Retrieves all meal event templates
+// @Tags meal-templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.MealEventTemplate
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-templates [get]
+func (h *MealEventTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplateByID godoc
+// @Summary Get meal event template
+// @Description Retrieves a meal event template with its menu sets and addresses. The ETag header is sent back in If-Match to update or delete it, or in If-None-Match to get 304 Not Modified while it is unchanged.
+// @Tags meal-templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path int true "Template ID"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {object} model.MealEventTemplate
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /meal-templates/{template_id} [get]
+func (h *MealEventTemplateHandler) GetTemplateByID(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("template_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(c.Request.Context(), uint(templateID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, template.Version, template)
+}
+
+// CreateTemplate godoc
+// @Summary Create meal event template
+// @Description Creates a named template holding event defaults, menu sets and addresses
+// @Tags meal-templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template body model.MealEventTemplate true "Template data"
+// @Success 201 {object} model.MealEventTemplate
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-templates [post]
+func (h *MealEventTemplateHandler) CreateTemplate(c *gin.Context) {
+	var template model.MealEventTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.templateService.CreateTemplate(c.Request.Context(), &template, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update meal event template
+// @Description Replaces the defaults, menu sets and addresses stored in a template. Events already created from it are not changed. If-Match must carry the ETag the template was read with; when someone else changed it since, the update fails with 412 and the current template.
+// @Tags meal-templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path int true "Template ID"
+// @Param If-Match header string true "ETag of the template"
+// @Param template body model.MealEventTemplate true "Template data"
+// @Success 200 {object} model.MealEventTemplate
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 412 {object} model.MealEventTemplate "Current template"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-templates/{template_id} [put]
+func (h *MealEventTemplateHandler) UpdateTemplate(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("template_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var template model.MealEventTemplate
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.templateService.UpdateTemplate(c.Request.Context(), uint(templateID), version, &template, userID); err != nil {
+		handleVersionError(c, err, h.currentTemplate(c, uint(templateID)))
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, template.Version, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete meal event template
+// @Description Deletes a meal event template. Events created from it are kept. If-Match must carry the ETag the template was read with; when someone else changed it since, the deletion fails with 412 and the current template.
+// @Tags meal-templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path int true "Template ID"
+// @Param If-Match header string true "ETag of the template"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 412 {object} model.MealEventTemplate "Current template"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Router /meal-templates/{template_id} [delete]
+func (h *MealEventTemplateHandler) DeleteTemplate(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("template_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), uint(templateID), version); err != nil {
+		handleVersionError(c, err, h.currentTemplate(c, uint(templateID)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+// currentTemplate loads the stored template for a 412 response
+func (h *MealEventTemplateHandler) currentTemplate(c *gin.Context, id uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		template, err := h.templateService.GetTemplate(c.Request.Context(), id)
+		if err != nil {
+			return nil, 0, err
+		}
+		return template, template.Version, nil
+	}
+}
+
+// CreateEventFromTemplate godoc
+// @Summary Create meal event from template
+// @Description Creates a meal event on the given date using the template's time, duration, cutoff, menu sets and addresses
+// @Tags meal-templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path int true "Template ID"
+// @Param request body TemplateEventRequest true "Event date"
+// @Success 201 {object} model.MealEvent
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-templates/{template_id}/events [post]
+func (h *MealEventTemplateHandler) CreateEventFromTemplate(c *gin.Context) {
+	templateID, err := strconv.ParseUint(c.Param("template_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req TemplateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	meal, err := h.templateService.CreateEventFromTemplate(c.Request.Context(), uint(templateID), date, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, meal)
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MealPickupHandler handles meal pickup check-in at kiosks
+type MealPickupHandler struct {
+	pickupService   service.MealPickupService
+	timeZoneService service.TimeZoneService
+}
+
+// NewMealPickupHandler creates a new instance of MealPickupHandler
+func NewMealPickupHandler(pickupService service.MealPickupService, timeZoneService service.TimeZoneService) *MealPickupHandler {
+	return &MealPickupHandler{
+		pickupService:   pickupService,
+		timeZoneService: timeZoneService,
+	}
+}
+
+// GetPickupToken godoc
+// @Summary Get a pickup code for my meal
+// @Description Signs a short-lived pickup token for the current user's confirmed meal request, to be shown as a QR code at the kiosk. Fetch a fresh token when it expires.
+// @Tags meal-pickups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Meal Request ID"
+// @Success 200 {object} service.PickupToken
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-requests/{id}/pickup-token [get]
+func (h *MealPickupHandler) GetPickupToken(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal request ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	token, err := h.pickupService.IssueToken(c.Request.Context(), uint(requestID), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// GetVerificationKey godoc
+// @Summary Get the pickup token verification key
+// @Description Returns the Ed25519 public key that pickup tokens are signed with, so kiosks can verify tokens while offline. The payload is JSON with the meal request (rid), user (uid), meal event (eid), requested address (aid), and issue and expiry times in Unix seconds (iat, exp).
+// @Tags meal-pickups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} service.PickupVerificationKey
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Router /pickups/verification-key [get]
+func (h *MealPickupHandler) GetVerificationKey(c *gin.Context) {
+	c.JSON(http.StatusOK, h.pickupService.GetVerificationKey())
+}
+
+// ScanPickup godoc
+// @Summary Scan a pickup code
+// @Description Checks a pickup token read at a kiosk and records the pickup time. A meal that was picked up before is reported as already_picked_up with the time of the first scan. A meal picked up away from its requested address is accepted with a warning. Offline kiosks send the time they read the token as scanned_at.
+// @Tags meal-pickups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param scan body service.PickupScan true "Scanned token"
+// @Success 200 {object} service.PickupScanResult
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /pickups/scan [post]
+func (h *MealPickupHandler) ScanPickup(c *gin.Context) {
+	var scan service.PickupScan
+	if err := c.ShouldBindJSON(&scan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	staffID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result, err := h.pickupService.ScanPickup(c.Request.Context(), &scan, staffID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMealPickups godoc
+// @Summary Get the pickups of a meal
+// @Description Compares the approved meals of a meal event with the meals picked up so far
+// @Tags meal-pickups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param meal_id path int true "Meal Event ID"
+// @Success 200 {object} service.MealPickupSummary
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meals/{meal_id}/pickups [get]
+func (h *MealPickupHandler) GetMealPickups(c *gin.Context) {
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal event ID"})
+		return
+	}
+
+	summary, err := h.pickupService.GetMealPickups(c.Request.Context(), uint(mealID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetNoShowReport godoc
+// @Summary Get the no-show report
+// @Description Counts the confirmed meals that were picked up or not, per user and per department, for the meal events within a date range whose meals were checked in at a kiosk. Dates are calendar days in the organization time zone. Defaults to the last 30 days.
+// @Tags meal-pickups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Success 200 {object} service.NoShowReport
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /pickups/no-shows [get]
+func (h *MealPickupHandler) GetNoShowReport(c *gin.Context) {
+	loc := h.timeZoneService.OrganizationLocation()
+	endDate := utils.EndOfDay(time.Now(), loc)
+	startDate := utils.StartOfDay(endDate.AddDate(0, 0, -29), loc)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := utils.ParseDate(value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			return
+		}
+		startDate = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := utils.ParseDate(value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			return
+		}
+		endDate = utils.EndOfDay(parsed, loc)
+	}
+
+	report, err := h.pickupService.GetNoShowReport(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
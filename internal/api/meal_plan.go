@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MealPlanHandler handles week-ahead meal planning
+type MealPlanHandler struct {
+	mealPlanService service.MealPlanService
+	timeZoneService service.TimeZoneService
+}
+
+// NewMealPlanHandler creates a new instance of MealPlanHandler
+func NewMealPlanHandler(mealPlanService service.MealPlanService, timeZoneService service.TimeZoneService) *MealPlanHandler {
+	return &MealPlanHandler{
+		mealPlanService: mealPlanService,
+		timeZoneService: timeZoneService,
+	}
+}
+
+// GetMealPlan godoc
+// @Summary Get my meal plan
+// @Description Lists the meal events of the next days with their menu sets, items and addresses, and the current user's pending or approved request for each. Days are calendar days in the user's time zone, starting today.
+// @Tags meal-requests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param days query int false "Number of days to plan, 1 to 31" default(7)
+// @Success 200 {object} service.MealPlan
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-requests/plan [get]
+func (h *MealPlanHandler) GetMealPlan(c *gin.Context) {
+	days := 7
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > service.MaxMealPlanDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid number of days. Use 1 to " + strconv.Itoa(service.MaxMealPlanDays)})
+			return
+		}
+		days = parsed
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	loc, err := h.timeZoneService.UserLocation(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	now := time.Now()
+	endDate := utils.EndOfDay(utils.StartOfDay(now, loc).AddDate(0, 0, days-1), loc)
+
+	plan, err := h.mealPlanService.GetMealPlan(c.Request.Context(), userID, now, endDate)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// ApplyMealPlan godoc
+// @Summary Submit my meal plan
+// @Description Chooses a menu set, items and address for many meal events at once, or withdraws from them with skip. Every entry is checked against its meal event (cutoff, offered menu sets and items, served addresses, seats). In per_event mode the valid entries are applied; in atomic mode all entries are applied or none. The response reports the outcome per event.
+// @Tags meal-requests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param plan body service.MealPlanBatch true "Meal plan"
+// @Success 200 {object} service.MealPlanResult
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-requests/plan [post]
+func (h *MealPlanHandler) ApplyMealPlan(c *gin.Context) {
+	var batch service.MealPlanBatch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result, err := h.mealPlanService.ApplyMealPlan(c.Request.Context(), &batch, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
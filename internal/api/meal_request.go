@@ -1,11 +1,17 @@
 package api
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -45,13 +51,15 @@ func (h *MealRequestHandler) GetMealRequests(c *gin.Context) {
 
 // GetMealRequestByID handles GET /api/meal-requests/:id
 // @Summary      Get meal request by ID
-// @Description  Get a specific meal request by its ID
+// @Description  Get a specific meal request by its ID. The ETag header is sent back in If-Match to update or cancel it, or in If-None-Match to get 304 Not Modified while it is unchanged.
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Meal Request ID"
+// @Param        id             path      int     true   "Meal Request ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
 // @Success      200  {object}  model.MealRequest
+// @Success      304  "Not Modified"
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
@@ -73,12 +81,12 @@ func (h *MealRequestHandler) GetMealRequestByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, request)
+	writeVersioned(c, http.StatusOK, request.Version, request)
 }
 
 // CreateMealRequest handles POST /api/meal-requests
 // @Summary      Create meal request
-// @Description  Create a new meal request
+// @Description  Create a new meal request before the cutoff. The menu set and address must be attached to the meal event, and any items must belong to the menu set with quantities within their limits.
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
@@ -96,10 +104,14 @@ func (h *MealRequestHandler) CreateMealRequest(c *gin.Context) {
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
 
 	if err := h.mealRequestService.CreateMealRequest(c.Request.Context(), &request, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -108,18 +120,21 @@ func (h *MealRequestHandler) CreateMealRequest(c *gin.Context) {
 
 // UpdateMealRequest handles PUT /api/meal-requests/:id
 // @Summary      Update meal request
-// @Description  Update an existing meal request
+// @Description  Change the menu set and address of a meal request before the cutoff. Items of a menu set that is no longer chosen are removed. If-Match must carry the ETag the meal request was read with; when someone else changed it since, the update fails with 412 and the current meal request.
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id       path      int              true  "Meal Request ID"
-// @Param        request  body      model.MealRequest  true  "Meal Request Data"
+// @Param        id        path      int              true  "Meal Request ID"
+// @Param        If-Match  header    string           true  "ETag of the meal request"
+// @Param        request   body      model.MealRequest  true  "Meal Request Data"
 // @Success      200     {object}  model.MealRequest
 // @Failure      400     {object}  ErrorResponse
 // @Failure      401     {object}  ErrorResponse
 // @Failure      403     {object}  ErrorResponse
 // @Failure      404     {object}  ErrorResponse
+// @Failure      412     {object}  model.MealRequest  "Current meal request"
+// @Failure      428     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /meal-requests/{id} [put]
 func (h *MealRequestHandler) UpdateMealRequest(c *gin.Context) {
@@ -129,36 +144,49 @@ func (h *MealRequestHandler) UpdateMealRequest(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var request model.MealRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
-	isAdmin := false  // TODO: Get from context after auth
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	isAdmin := utils.IsAdminFromContext(c)
 
-	if err := h.mealRequestService.UpdateMealRequest(c.Request.Context(), uint(id), &request, userID, isAdmin); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.mealRequestService.UpdateMealRequest(c.Request.Context(), uint(id), version, &request, userID, isAdmin); err != nil {
+		handleVersionError(c, err, h.currentMealRequest(c, uint(id), userID, isAdmin))
 		return
 	}
 
-	c.JSON(http.StatusOK, request)
+	writeVersioned(c, http.StatusOK, request.Version, request)
 }
 
 // DeleteMealRequest handles DELETE /api/meal-requests/:id
-// @Summary      Delete meal request
-// @Description  Delete an existing meal request
+// @Summary      Cancel meal request
+// @Description  Cancel a meal request before the cutoff. The request is kept with status cancelled and its history. If-Match must carry the ETag the meal request was read with; when someone else changed it since, the cancellation fails with 412 and the current meal request.
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Meal Request ID"
+// @Param        id        path      int     true  "Meal Request ID"
+// @Param        If-Match  header    string  true  "ETag of the meal request"
 // @Success      200  {object}  SuccessResponse
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      403  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      412  {object}  model.MealRequest  "Current meal request"
+// @Failure      428  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /meal-requests/{id} [delete]
 func (h *MealRequestHandler) DeleteMealRequest(c *gin.Context) {
@@ -168,20 +196,41 @@ func (h *MealRequestHandler) DeleteMealRequest(c *gin.Context) {
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
-	isAdmin := false  // TODO: Get from context after auth
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
 
-	if err := h.mealRequestService.DeleteMealRequest(c.Request.Context(), uint(id), userID, isAdmin); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	// The cancellation is recorded in the request's history under the caller's name
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+	isAdmin := utils.IsAdminFromContext(c)
 
-	c.JSON(http.StatusOK, SuccessResponse{Message: "Meal request deleted successfully"})
+	if err := h.mealRequestService.DeleteMealRequest(c.Request.Context(), uint(id), version, userID, isAdmin); err != nil {
+		handleVersionError(c, err, h.currentMealRequest(c, uint(id), userID, isAdmin))
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Meal request cancelled successfully"})
+}
+
+// currentMealRequest loads the stored meal request for a 412 response
+func (h *MealRequestHandler) currentMealRequest(c *gin.Context, id uint, userID uint, isAdmin bool) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		request, err := h.mealRequestService.GetMealRequestByID(c.Request.Context(), id, userID, isAdmin)
+		if err != nil {
+			return nil, 0, err
+		}
+		return request, request.Version, nil
+	}
 }
 
 // AddRequestItem handles POST /api/meal-requests/:id/items
 // @Summary      Add item to meal request
-// @Description  Add a new item to an existing meal request
+// @Description  Add an item of the request's menu set to a pending or approved meal request before the cutoff. The quantity must be within the menu item's limits and defaults to its minimum.
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
@@ -193,6 +242,7 @@ func (h *MealRequestHandler) DeleteMealRequest(c *gin.Context) {
 // @Failure      401   {object}  ErrorResponse
 // @Failure      403   {object}  ErrorResponse
 // @Failure      404   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /meal-requests/{id}/items [post]
 func (h *MealRequestHandler) AddRequestItem(c *gin.Context) {
@@ -208,11 +258,15 @@ func (h *MealRequestHandler) AddRequestItem(c *gin.Context) {
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
-	isAdmin := false  // TODO: Get from context after auth
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	isAdmin := utils.IsAdminFromContext(c)
 
 	if err := h.mealRequestService.AddRequestItem(c.Request.Context(), uint(requestID), &item, userID, isAdmin); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -221,7 +275,7 @@ func (h *MealRequestHandler) AddRequestItem(c *gin.Context) {
 
 // RemoveRequestItem handles DELETE /api/meal-requests/:id/items/:item_id
 // @Summary      Remove item from meal request
-// @Description  Remove an item from an existing meal request
+// @Description  Remove an item from a pending or approved meal request before the cutoff
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
@@ -248,11 +302,15 @@ func (h *MealRequestHandler) RemoveRequestItem(c *gin.Context) {
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
-	isAdmin := false  // TODO: Get from context after auth
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	isAdmin := utils.IsAdminFromContext(c)
 
 	if err := h.mealRequestService.RemoveRequestItem(c.Request.Context(), uint(requestID), uint(itemID), userID, isAdmin); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -280,32 +338,47 @@ func (h *MealRequestHandler) GetRequestItems(c *gin.Context) {
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
-	isAdmin := false  // TODO: Get from context after auth
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	isAdmin := utils.IsAdminFromContext(c)
 
 	items, err := h.mealRequestService.GetRequestItems(c.Request.Context(), uint(requestID), userID, isAdmin)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, items)
 }
 
-// UpdateRequestStatus handles PUT /api/requests/:id/status
+// MealRequestStatusRequest represents the request body for changing a meal request's status
+type MealRequestStatusRequest struct {
+	Status model.RequestStatus `json:"status" binding:"required" enums:"approved,rejected,completed,cancelled" example:"approved"`
+	Reason string              `json:"reason" example:"Dietary restriction confirmed"`
+}
+
+// UpdateRequestStatus handles PUT /api/meal-requests/:id/status
 // @Summary      Update meal request status
-// @Description  Update the status of a specific meal request
+// @Description  Move a meal request through its lifecycle: pending to approved, rejected or cancelled, and approved to completed or cancelled. Employees may only cancel their own requests before the cutoff. Every change is recorded in the request's history. If-Match must carry the ETag the meal request was read with; when someone else changed it since, the request fails with 412 and the current meal request.
 // @Tags         meal-requests
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id      path    int     true  "Meal Request ID"
-// @Param        status  body    string  true  "New Status"  Enums(pending, accepted, rejected, completed)
-// @Success      200     {object}  model.MealRequest
-// @Failure      400     {object}  ErrorResponse
-// @Failure      401     {object}  ErrorResponse
-// @Failure      404     {object}  ErrorResponse
-// @Failure      500     {object}  ErrorResponse
+// @Param        id        path    int                       true  "Meal Request ID"
+// @Param        If-Match  header  string                    true  "ETag of the meal request"
+// @Param        request   body    MealRequestStatusRequest  true  "New status and reason"
+// @Success      200      {object}  model.MealRequest
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      412      {object}  model.MealRequest  "Current meal request"
+// @Failure      428      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
 // @Router       /meal-requests/{id}/status [put]
 func (h *MealRequestHandler) UpdateRequestStatus(c *gin.Context) {
 	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -314,18 +387,464 @@ func (h *MealRequestHandler) UpdateRequestStatus(c *gin.Context) {
 		return
 	}
 
-	var status model.RequestStatus
-	if err := c.ShouldBindJSON(&status); err != nil {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req MealRequestStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
 		return
 	}
 
-	userID := uint(1) // TODO: Get from context after auth
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
 
-	if err := h.mealRequestService.UpdateRequestStatus(c.Request.Context(), uint(requestID), status, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	isAdmin := utils.IsAdminFromContext(c)
+	request, err := h.mealRequestService.UpdateRequestStatus(c.Request.Context(), uint(requestID), version, req.Status, req.Reason, userID, isAdmin)
+	if err != nil {
+		handleVersionError(c, err, h.currentMealRequest(c, uint(requestID), userID, isAdmin))
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, request.Version, request)
+}
+
+// GetRequestHistory handles GET /api/meal-requests/:id/history
+// @Summary      Get meal request status history
+// @Description  List every status change of a meal request with its actor, time and reason
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Meal Request ID"
+// @Success      200  {array}   model.MealRequestTransition
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /meal-requests/{id}/history [get]
+func (h *MealRequestHandler) GetRequestHistory(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal request ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	history, err := h.mealRequestService.GetRequestHistory(c.Request.Context(), uint(requestID), userID, utils.IsAdminFromContext(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// maxBulkImportSize limits the size of uploaded employee lists
+const maxBulkImportSize = 1 << 20
+
+// AssignMealRequests handles POST /api/meal-requests/bulk
+// @Summary      Assign meals to employees
+// @Description  Create or change the meal requests of many employees for one meal event, selected by user IDs, department or employee IDs. Works after the cutoff. Every employee is notified, and the response reports the outcome per employee.
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      service.BulkRequestAssignment  true  "Employees, meal choice and reason"
+// @Success      200      {object}  service.BulkRequestResult
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meal-requests/bulk [post]
+func (h *MealRequestHandler) AssignMealRequests(c *gin.Context) {
+	var req service.BulkRequestAssignment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result, err := h.mealRequestService.AssignMealRequests(c.Request.Context(), &req, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CancelMealRequests handles POST /api/meal-requests/bulk/cancel
+// @Summary      Cancel meals of employees
+// @Description  Cancel the open meal requests of many employees for one meal event, selected by user IDs, department or employee IDs. Works after the cutoff. Every employee is notified, and the response reports the outcome per employee.
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      service.BulkRequestCancellation  true  "Employees and reason"
+// @Success      200      {object}  service.BulkRequestResult
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meal-requests/bulk/cancel [post]
+func (h *MealRequestHandler) CancelMealRequests(c *gin.Context) {
+	var req service.BulkRequestCancellation
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result, err := h.mealRequestService.CancelMealRequestsFor(c.Request.Context(), &req, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ImportMealRequests handles POST /api/meal-requests/bulk/import
+// @Summary      Assign or cancel meals from a CSV file
+// @Description  Assign or cancel the meal requests of the employees listed in a CSV file. The file needs a header row with an employee_id column; other columns are ignored. Works after the cutoff, and the response reports the outcome per employee.
+// @Tags         meal-requests
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file              formData  file    true   "CSV file with an employee_id column"
+// @Param        action            formData  string  true   "assign or cancel"  Enums(assign, cancel)
+// @Param        meal_event_id     formData  int     true   "Meal event ID"
+// @Param        menu_set_id       formData  int     false  "Menu set ID, required to assign"
+// @Param        event_address_id  formData  int     false  "Event address ID, required to assign"
+// @Param        items             formData  string  false  "JSON array of items to assign"
+// @Param        reason            formData  string  true   "Reason for the audit trail"
+// @Success      200  {object}  service.BulkRequestResult
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /meal-requests/bulk/import [post]
+func (h *MealRequestHandler) ImportMealRequests(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "A CSV file is required"})
+		return
+	}
+	if fileHeader.Size > maxBulkImportSize {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "The CSV file is too large"})
 		return
 	}
 
-	c.JSON(http.StatusOK, SuccessResponse{Message: "Meal request status updated successfully"})
+	mealEventID, err := strconv.ParseUint(c.PostForm("meal_event_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read the CSV file"})
+		return
+	}
+	defer file.Close()
+
+	employeeIDs, err := readEmployeeIDs(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	targets := service.BulkRequestTargets{EmployeeIDs: employeeIDs}
+
+	var result *service.BulkRequestResult
+	switch c.PostForm("action") {
+	case "assign":
+		assignment := service.BulkRequestAssignment{
+			BulkRequestTargets: targets,
+			MealEventID:        uint(mealEventID),
+			Reason:             c.PostForm("reason"),
+		}
+		if value := c.PostForm("menu_set_id"); value != "" {
+			menuSetID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid menu set ID"})
+				return
+			}
+			assignment.MenuSetID = uint(menuSetID)
+		}
+		if value := c.PostForm("event_address_id"); value != "" {
+			addressID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid event address ID"})
+				return
+			}
+			assignment.EventAddressID = uint(addressID)
+		}
+		if value := c.PostForm("items"); value != "" {
+			if err := json.Unmarshal([]byte(value), &assignment.Items); err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid items"})
+				return
+			}
+		}
+		result, err = h.mealRequestService.AssignMealRequests(c.Request.Context(), &assignment, userID)
+	case "cancel":
+		cancellation := service.BulkRequestCancellation{
+			BulkRequestTargets: targets,
+			MealEventID:        uint(mealEventID),
+			Reason:             c.PostForm("reason"),
+		}
+		result, err = h.mealRequestService.CancelMealRequestsFor(c.Request.Context(), &cancellation, userID)
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Action must be assign or cancel"})
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// LateMealRequest represents the request body for asking for a meal after the cutoff
+type LateMealRequest struct {
+	MealEventID    uint   `json:"meal_event_id" binding:"required" example:"1"`
+	MenuSetID      uint   `json:"menu_set_id" binding:"required" example:"2"`
+	EventAddressID uint   `json:"event_address_id" binding:"required" example:"1"`
+	Reason         string `json:"reason" binding:"required" example:"Client meeting ran over the cutoff"`
+}
+
+// LateRequestReview represents the request body for approving or rejecting a late request
+type LateRequestReview struct {
+	Approve bool   `json:"approve" example:"true"`
+	Note    string `json:"note" example:"Two portions of the vegetarian set are left"`
+}
+
+// CreateLateRequest handles POST /api/meal-requests/late
+// @Summary      Ask for a meal after the cutoff
+// @Description  Submit a late request with a justification for a meal event that accepts late requests, between the cutoff and the end of the event. The request stays pending until the kitchen approves or rejects it based on the surplus left; kitchen admins are notified.
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      LateMealRequest  true  "Late request"
+// @Success      201      {object}  model.MealRequest
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meal-requests/late [post]
+func (h *MealRequestHandler) CreateLateRequest(c *gin.Context) {
+	var req LateMealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	request := model.MealRequest{
+		MealEventID:    req.MealEventID,
+		MenuSetID:      req.MenuSetID,
+		EventAddressID: req.EventAddressID,
+		LateReason:     req.Reason,
+	}
+	if err := h.mealRequestService.CreateLateRequest(c.Request.Context(), &request, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+// ReviewLateRequest handles POST /api/meal-requests/:id/late-review
+// @Summary      Approve or reject a late request
+// @Description  Approve or reject a meal request submitted after the cutoff. Approval needs surplus left for the request's menu set and room under the meal event's late request cap. The requester is notified.
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                true  "Meal Request ID"
+// @Param        request  body      LateRequestReview  true  "Decision"
+// @Success      200      {object}  model.MealRequest
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meal-requests/{id}/late-review [post]
+func (h *MealRequestHandler) ReviewLateRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal request ID"})
+		return
+	}
+
+	var req LateRequestReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	request, err := h.mealRequestService.ReviewLateRequest(c.Request.Context(), uint(id), req.Approve, req.Note, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// GetLateRequests handles GET /api/meals/:meal_id/late-requests
+// @Summary      Get the late requests of a meal event
+// @Description  List the late requests of a meal event, pending ones first, with the surplus, approved and pending late requests and remaining portions per menu set
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        meal_id  path      int  true  "Meal Event ID"
+// @Success      200      {object}  service.LateRequestOverview
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meals/{meal_id}/late-requests [get]
+func (h *MealRequestHandler) GetLateRequests(c *gin.Context) {
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	overview, err := h.mealRequestService.GetLateRequests(c.Request.Context(), uint(mealID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// SetSurplus handles PUT /api/meals/:meal_id/surplus
+// @Summary      Record the surplus of a meal event
+// @Description  Record the spare portions per menu set that late requests can be served from. Menu sets left out keep their surplus.
+// @Tags         meal-requests
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        meal_id  path      int                       true  "Meal Event ID"
+// @Param        request  body      []service.MenuSetSurplus  true  "Spare portions per menu set"
+// @Success      200      {object}  service.LateRequestOverview
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      403      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /meals/{meal_id}/surplus [put]
+func (h *MealRequestHandler) SetSurplus(c *gin.Context) {
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid meal event ID"})
+		return
+	}
+
+	var req []service.MenuSetSurplus
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	overview, err := h.mealRequestService.SetSurplus(c.Request.Context(), uint(mealID), req, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// readEmployeeIDs reads the employee_id column of a CSV file with a header row
+func readEmployeeIDs(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("the CSV file has no header row")
+	}
+	column := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), "employee_id") {
+			column = i
+			break
+		}
+	}
+	if column < 0 {
+		return nil, fmt.Errorf("the CSV file has no employee_id column")
+	}
+
+	var employeeIDs []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %v", err)
+		}
+		if column < len(record) && strings.TrimSpace(record[column]) != "" {
+			employeeIDs = append(employeeIDs, strings.TrimSpace(record[column]))
+		}
+	}
+	return employeeIDs, nil
 }
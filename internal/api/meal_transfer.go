@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MealTransferHandler handles post-cutoff meal transfers and the surplus pool
+type MealTransferHandler struct {
+	transferService service.MealTransferService
+}
+
+// NewMealTransferHandler creates a new instance of MealTransferHandler
+func NewMealTransferHandler(transferService service.MealTransferService) *MealTransferHandler {
+	return &MealTransferHandler{
+		transferService: transferService,
+	}
+}
+
+// OfferMeal godoc
+// @Summary Offer my meal to a colleague or the surplus pool
+// @Description After the cutoff, offers the current user's confirmed meal to a colleague, who can accept or decline it, or releases it to the surplus pool when to_user_id is left empty, where anyone can claim it. The meal stays the user's until the offer is accepted or claimed, and can be offered until the meal event ends.
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Meal Request ID"
+// @Param offer body service.MealTransferOffer true "Offer"
+// @Success 201 {object} model.MealTransfer
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-requests/{id}/offer [post]
+func (h *MealTransferHandler) OfferMeal(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal request ID"})
+		return
+	}
+
+	var offer service.MealTransferOffer
+	if err := c.ShouldBindJSON(&offer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	transfer, err := h.transferService.OfferMeal(c.Request.Context(), uint(requestID), userID, &offer)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// GetRequestTransfers godoc
+// @Summary Get the transfers of a meal request
+// @Description Lists every offer made for a meal request, oldest first; accepted and claimed ones show who owned the meal when. Visible to admins, the current owner and the parties of its transfers.
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Meal Request ID"
+// @Success 200 {array} model.MealTransfer
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-requests/{id}/transfers [get]
+func (h *MealTransferHandler) GetRequestTransfers(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal request ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	transfers, err := h.transferService.GetRequestTransfers(c.Request.Context(), uint(requestID), userID, utils.IsAdminFromContext(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// GetMyTransfers godoc
+// @Summary List my meal transfers
+// @Description Lists the meals the current user offered or was offered, latest first
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.MealTransfer
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-transfers [get]
+func (h *MealTransferHandler) GetMyTransfers(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	transfers, err := h.transferService.GetMyTransfers(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// GetPool godoc
+// @Summary List the surplus pool
+// @Description Lists the confirmed meals released to the surplus pool that can still be claimed, the soonest meal event first
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.MealTransfer
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-transfers/pool [get]
+func (h *MealTransferHandler) GetPool(c *gin.Context) {
+	transfers, err := h.transferService.GetPool(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// AcceptOffer godoc
+// @Summary Accept a meal offered to me
+// @Description Makes the current user the owner of a meal a colleague offered them. Users who already have a request for the meal event cannot take over another meal.
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param transfer_id path int true "Meal Transfer ID"
+// @Success 200 {object} model.MealTransfer
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-transfers/{transfer_id}/accept [post]
+func (h *MealTransferHandler) AcceptOffer(c *gin.Context) {
+	h.respond(c, h.transferService.AcceptOffer)
+}
+
+// ClaimMeal godoc
+// @Summary Claim a meal from the surplus pool
+// @Description Makes the current user the owner of a meal in the surplus pool. The first claim wins; later claims fail with 409 Conflict.
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param transfer_id path int true "Meal Transfer ID"
+// @Success 200 {object} model.MealTransfer
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-transfers/{transfer_id}/claim [post]
+func (h *MealTransferHandler) ClaimMeal(c *gin.Context) {
+	h.respond(c, h.transferService.ClaimMeal)
+}
+
+// DeclineOffer godoc
+// @Summary Decline a meal offered to me
+// @Description Turns down a meal a colleague offered the current user; the meal stays theirs
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param transfer_id path int true "Meal Transfer ID"
+// @Success 200 {object} model.MealTransfer
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-transfers/{transfer_id}/decline [post]
+func (h *MealTransferHandler) DeclineOffer(c *gin.Context) {
+	h.respond(c, h.transferService.DeclineOffer)
+}
+
+// WithdrawOffer godoc
+// @Summary Withdraw my meal offer
+// @Description Takes back an offer the current user made that was not accepted or claimed yet
+// @Tags meal-transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param transfer_id path int true "Meal Transfer ID"
+// @Success 200 {object} model.MealTransfer
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-transfers/{transfer_id}/withdraw [post]
+func (h *MealTransferHandler) WithdrawOffer(c *gin.Context) {
+	h.respond(c, h.transferService.WithdrawOffer)
+}
+
+// respond runs an action of the current user on the meal transfer in the path
+func (h *MealTransferHandler) respond(c *gin.Context, action func(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error)) {
+	transferID, err := strconv.ParseUint(c.Param("transfer_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal transfer ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	transfer, err := action(c.Request.Context(), uint(transferID), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MealTypeDefaultHandler handles per meal type default requests
+type MealTypeDefaultHandler struct {
+	defaultService service.MealTypeDefaultService
+}
+
+// NewMealTypeDefaultHandler creates a new instance of MealTypeDefaultHandler
+func NewMealTypeDefaultHandler(defaultService service.MealTypeDefaultService) *MealTypeDefaultHandler {
+	return &MealTypeDefaultHandler{
+		defaultService: defaultService,
+	}
+}
+
+// GetMealTypeDefaults godoc
+// @Summary List meal type defaults
+// @Description Retrieves the organization defaults of every configured meal type
+// @Tags meal-types
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.MealTypeDefault
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-types [get]
+func (h *MealTypeDefaultHandler) GetMealTypeDefaults(c *gin.Context) {
+	defaults, err := h.defaultService.ListDefaults(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defaults)
+}
+
+// GetMealTypeDefault godoc
+// @Summary Get meal type defaults
+// @Description Retrieves the typical time, duration, cutoff offset, menu sets and addresses used for new events of a meal type
+// @Tags meal-types
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param meal_type path string true "Meal type" Enums(breakfast, lunch, snacks)
+// @Success 200 {object} model.MealTypeDefault
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /meal-types/{meal_type} [get]
+func (h *MealTypeDefaultHandler) GetMealTypeDefault(c *gin.Context) {
+	defaults, err := h.defaultService.GetDefault(c.Request.Context(), model.MealType(c.Param("meal_type")))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defaults)
+}
+
+// SetMealTypeDefault godoc
+// @Summary Set meal type defaults
+// @Description Creates or replaces the defaults used when an event of this meal type is created without them
+// @Tags meal-types
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param meal_type path string true "Meal type" Enums(breakfast, lunch, snacks)
+// @Param defaults body model.MealTypeDefault true "Meal type defaults"
+// @Success 200 {object} model.MealTypeDefault
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-types/{meal_type} [put]
+func (h *MealTypeDefaultHandler) SetMealTypeDefault(c *gin.Context) {
+	var defaults model.MealTypeDefault
+	if err := c.ShouldBindJSON(&defaults); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	mealType := model.MealType(c.Param("meal_type"))
+	if err := h.defaultService.SetDefault(c.Request.Context(), mealType, &defaults, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defaults)
+}
+
+// DeleteMealTypeDefault godoc
+// @Summary Delete meal type defaults
+// @Description Removes the defaults of a meal type. Existing events are not affected.
+// @Tags meal-types
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param meal_type path string true "Meal type" Enums(breakfast, lunch, snacks)
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /meal-types/{meal_type} [delete]
+func (h *MealTypeDefaultHandler) DeleteMealTypeDefault(c *gin.Context) {
+	if err := h.defaultService.DeleteDefault(c.Request.Context(), model.MealType(c.Param("meal_type"))); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meal type defaults deleted"})
+}
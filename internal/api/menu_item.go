@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"github.com/arafat-hasan/mealsync/internal/service"
@@ -21,17 +22,24 @@ func NewMenuItemHandler(menuItemService service.MenuItemService) *MenuItemHandle
 
 // GetMenuItems handles GET /api/menu-items
 // @Summary      List menu items
-// @Description  Get all menu items for the authenticated user
+// @Description  Get all menu items for the authenticated user, optionally only those that carry all given dietary tags and contain none of the given allergens
 // @Tags         menu-items
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        dietary_tags       query     string  false  "Comma-separated dietary tags the items must carry, e.g. vegan,halal"
+// @Param        exclude_allergens  query     string  false  "Comma-separated allergens the items must not contain, e.g. peanuts,milk"
 // @Success      200  {array}   model.MenuItem
 // @Failure      401  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /menu-items [get]
 func (h *MenuItemHandler) GetMenuItems(c *gin.Context) {
-	items, err := h.menuItemService.GetMenuItems(c.Request.Context())
+	filter := service.MenuItemFilter{
+		DietaryTags:      splitList(c.Query("dietary_tags")),
+		ExcludeAllergens: splitList(c.Query("exclude_allergens")),
+	}
+
+	items, err := h.menuItemService.GetMenuItems(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
@@ -42,13 +50,15 @@ func (h *MenuItemHandler) GetMenuItems(c *gin.Context) {
 
 // GetMenuItemByID handles GET /api/menu-items/:id
 // @Summary      Get menu item by ID
-// @Description  Get a specific menu item by its ID
+// @Description  Get a specific menu item by its ID. The ETag header is sent back in If-Match to update or delete it, or in If-None-Match to get 304 Not Modified while it is unchanged.
 // @Tags         menu-items
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Menu Item ID"
+// @Param        id             path      int     true   "Menu Item ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
 // @Success      200  {object}  model.MenuItem
+// @Success      304  "Not Modified"
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
@@ -67,12 +77,12 @@ func (h *MenuItemHandler) GetMenuItemByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	writeVersioned(c, http.StatusOK, item.Version, item)
 }
 
 // CreateMenuItem handles POST /api/menu-items
 // @Summary      Create menu item
-// @Description  Create a new menu item
+// @Description  Create a new menu item. Allergens may name any of the EU 14 (gluten, crustaceans, eggs, fish, peanuts, soybeans, milk, nuts, celery, mustard, sesame, sulphites, lupin, molluscs) as well as custom ones. Dietary tags are vegetarian, vegan, halal, kosher, gluten_free, dairy_free and nut_free; vegan items are also vegetarian and dairy-free, and tags may not contradict the allergens.
 // @Tags         menu-items
 // @Accept       json
 // @Produce      json
@@ -93,7 +103,7 @@ func (h *MenuItemHandler) CreateMenuItem(c *gin.Context) {
 	userID := uint(1) // TODO: Get from context after auth
 
 	if err := h.menuItemService.CreateMenuItem(c.Request.Context(), &item, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -102,18 +112,21 @@ func (h *MenuItemHandler) CreateMenuItem(c *gin.Context) {
 
 // UpdateMenuItem handles PUT /api/menu-items/:id
 // @Summary      Update menu item
-// @Description  Update an existing menu item
+// @Description  Update an existing menu item. If-Match must carry the ETag the menu item was read with; when someone else changed it since, the update fails with 412 and the current menu item.
 // @Tags         menu-items
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id    path      int           true  "Menu Item ID"
-// @Param        item  body      model.MenuItem  true  "Menu Item Data"
+// @Param        id        path      int             true  "Menu Item ID"
+// @Param        If-Match  header    string          true  "ETag of the menu item"
+// @Param        item      body      model.MenuItem  true  "Menu Item Data"
 // @Success      200   {object}  model.MenuItem
 // @Failure      400   {object}  ErrorResponse
 // @Failure      401   {object}  ErrorResponse
 // @Failure      403   {object}  ErrorResponse
 // @Failure      404   {object}  ErrorResponse
+// @Failure      412   {object}  model.MenuItem  "Current menu item"
+// @Failure      428   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /menu-items/{id} [put]
 func (h *MenuItemHandler) UpdateMenuItem(c *gin.Context) {
@@ -123,6 +136,11 @@ func (h *MenuItemHandler) UpdateMenuItem(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var item model.MenuItem
 	if err := c.ShouldBindJSON(&item); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
@@ -131,27 +149,30 @@ func (h *MenuItemHandler) UpdateMenuItem(c *gin.Context) {
 
 	userID := uint(1) // TODO: Get from context after auth
 
-	if err := h.menuItemService.UpdateMenuItem(c.Request.Context(), uint(id), &item, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.menuItemService.UpdateMenuItem(c.Request.Context(), uint(id), version, &item, userID); err != nil {
+		handleVersionError(c, err, h.currentMenuItem(c, uint(id)))
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	writeVersioned(c, http.StatusOK, item.Version, item)
 }
 
 // DeleteMenuItem handles DELETE /api/menu-items/:id
 // @Summary      Delete menu item
-// @Description  Delete an existing menu item
+// @Description  Delete an existing menu item. If-Match must carry the ETag the menu item was read with; when someone else changed it since, the deletion fails with 412 and the current menu item.
 // @Tags         menu-items
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Menu Item ID"
+// @Param        id        path      int     true  "Menu Item ID"
+// @Param        If-Match  header    string  true  "ETag of the menu item"
 // @Success      200  {object}  SuccessResponse
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      403  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
+// @Failure      412  {object}  model.MenuItem  "Current menu item"
+// @Failure      428  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /menu-items/{id} [delete]
 func (h *MenuItemHandler) DeleteMenuItem(c *gin.Context) {
@@ -161,16 +182,32 @@ func (h *MenuItemHandler) DeleteMenuItem(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	userID := uint(1) // TODO: Get from context after auth
 
-	if err := h.menuItemService.DeleteMenuItem(c.Request.Context(), uint(id), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.menuItemService.DeleteMenuItem(c.Request.Context(), uint(id), version, userID); err != nil {
+		handleVersionError(c, err, h.currentMenuItem(c, uint(id)))
 		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Menu item deleted successfully"})
 }
 
+// currentMenuItem loads the stored menu item for a 412 response
+func (h *MenuItemHandler) currentMenuItem(c *gin.Context, id uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		item, err := h.menuItemService.GetMenuItemByID(c.Request.Context(), id)
+		if err != nil {
+			return nil, 0, err
+		}
+		return item, item.Version, nil
+	}
+}
+
 // GetMenuItemsByCategory godoc
 // @Summary Get menu items by category
 // @Description Retrieves all menu items belonging to a specific category
@@ -225,3 +262,14 @@ func (h *MenuItemHandler) GetMenuItemsByMenuSet(c *gin.Context) {
 
 	c.JSON(http.StatusOK, items)
 }
+
+// splitList splits a comma-separated query parameter, dropping blank entries
+func splitList(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
@@ -42,13 +42,15 @@ func (h *MenuSetHandler) GetMenuSets(c *gin.Context) {
 
 // GetMenuSetByID handles GET /api/menu-sets/:id
 // @Summary      Get menu set by ID
-// @Description  Get a specific menu set by its ID
+// @Description  Get a specific menu set by its ID. The ETag header is sent back in If-Match to update or delete it, or in If-None-Match to get 304 Not Modified while it is unchanged.
 // @Tags         menu-sets
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Menu Set ID"
+// @Param        id             path      int     true   "Menu Set ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
 // @Success      200  {object}  model.MenuSet
+// @Success      304  "Not Modified"
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
@@ -67,7 +69,7 @@ func (h *MenuSetHandler) GetMenuSetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, menuSet)
+	writeVersioned(c, http.StatusOK, menuSet.Version, menuSet)
 }
 
 // CreateMenuSet handles POST /api/menu-sets
@@ -102,18 +104,21 @@ func (h *MenuSetHandler) CreateMenuSet(c *gin.Context) {
 
 // UpdateMenuSet handles PUT /api/menu-sets/:id
 // @Summary      Update menu set
-// @Description  Update an existing menu set
+// @Description  Update an existing menu set. If-Match must carry the ETag the menu set was read with; when someone else changed it since, the update fails with 412 and the current menu set.
 // @Tags         menu-sets
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id       path      int          true  "Menu Set ID"
-// @Param        menuSet  body      model.MenuSet true  "Menu Set Data"
+// @Param        id        path      int           true  "Menu Set ID"
+// @Param        If-Match  header    string        true  "ETag of the menu set"
+// @Param        menuSet   body      model.MenuSet true  "Menu Set Data"
 // @Success      200     {object}  model.MenuSet
 // @Failure      400     {object}  ErrorResponse
 // @Failure      401     {object}  ErrorResponse
 // @Failure      403     {object}  ErrorResponse
 // @Failure      404     {object}  ErrorResponse
+// @Failure      412     {object}  model.MenuSet  "Current menu set"
+// @Failure      428     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /menu-sets/{id} [put]
 func (h *MenuSetHandler) UpdateMenuSet(c *gin.Context) {
@@ -123,6 +128,11 @@ func (h *MenuSetHandler) UpdateMenuSet(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	var menuSet model.MenuSet
 	if err := c.ShouldBindJSON(&menuSet); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request format"})
@@ -131,27 +141,30 @@ func (h *MenuSetHandler) UpdateMenuSet(c *gin.Context) {
 
 	userID := uint(1) // TODO: Get from context after auth
 
-	if err := h.menuSetService.UpdateMenuSet(c.Request.Context(), uint(id), &menuSet, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.menuSetService.UpdateMenuSet(c.Request.Context(), uint(id), version, &menuSet, userID); err != nil {
+		handleVersionError(c, err, h.currentMenuSet(c, uint(id)))
 		return
 	}
 
-	c.JSON(http.StatusOK, menuSet)
+	writeVersioned(c, http.StatusOK, menuSet.Version, menuSet)
 }
 
 // DeleteMenuSet handles DELETE /api/menu-sets/:id
 // @Summary      Delete menu set
-// @Description  Delete an existing menu set
+// @Description  Delete an existing menu set. If-Match must carry the ETag the menu set was read with; when someone else changed it since, the deletion fails with 412 and the current menu set.
 // @Tags         menu-sets
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Menu Set ID"
+// @Param        id        path      int     true  "Menu Set ID"
+// @Param        If-Match  header    string  true  "ETag of the menu set"
 // @Success      200  {object}  SuccessResponse
 // @Failure      400  {object}  ErrorResponse
 // @Failure      401  {object}  ErrorResponse
 // @Failure      403  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
+// @Failure      412  {object}  model.MenuSet  "Current menu set"
+// @Failure      428  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /menu-sets/{id} [delete]
 func (h *MenuSetHandler) DeleteMenuSet(c *gin.Context) {
@@ -161,16 +174,32 @@ func (h *MenuSetHandler) DeleteMenuSet(c *gin.Context) {
 		return
 	}
 
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
 	userID := uint(1) // TODO: Get from context after auth
 
-	if err := h.menuSetService.DeleteMenuSet(c.Request.Context(), uint(id), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := h.menuSetService.DeleteMenuSet(c.Request.Context(), uint(id), version, userID); err != nil {
+		handleVersionError(c, err, h.currentMenuSet(c, uint(id)))
 		return
 	}
 
 	c.JSON(http.StatusOK, SuccessResponse{Message: "Menu set deleted successfully"})
 }
 
+// currentMenuSet loads the stored menu set for a 412 response
+func (h *MenuSetHandler) currentMenuSet(c *gin.Context, id uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		menuSet, err := h.menuSetService.GetMenuSetByID(c.Request.Context(), id)
+		if err != nil {
+			return nil, 0, err
+		}
+		return menuSet, menuSet.Version, nil
+	}
+}
+
 // GetMenuSetItems handles GET /api/menus/:id/items
 // @Summary      List menu set items
 // @Description  Get all items in a specific menu set
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// NoShowPolicyHandler handles no-show policies, penalties and appeals
+type NoShowPolicyHandler struct {
+	policyService service.NoShowPolicyService
+}
+
+// NewNoShowPolicyHandler creates a new instance of NoShowPolicyHandler
+func NewNoShowPolicyHandler(policyService service.NoShowPolicyService) *NoShowPolicyHandler {
+	return &NoShowPolicyHandler{
+		policyService: policyService,
+	}
+}
+
+// NoShowPolicyRequest represents the request body for creating or replacing a no-show policy
+type NoShowPolicyRequest struct {
+	Name         string             `json:"name" binding:"required" example:"Three strikes"`
+	Threshold    int                `json:"threshold" binding:"required" example:"3"`
+	WindowDays   int                `json:"window_days" binding:"required" example:"30"`
+	Action       model.NoShowAction `json:"action" binding:"required" enums:"warn,limit_standing_orders,require_approval"`
+	DurationDays int                `json:"duration_days" example:"14"` // required for restrictions
+	IsActive     *bool              `json:"is_active" example:"true"`   // defaults to true
+}
+
+// toModel converts the request into a no-show policy
+func (r *NoShowPolicyRequest) toModel() *model.NoShowPolicy {
+	policy := &model.NoShowPolicy{
+		Name:         r.Name,
+		Threshold:    r.Threshold,
+		WindowDays:   r.WindowDays,
+		Action:       r.Action,
+		DurationDays: r.DurationDays,
+		IsActive:     true,
+	}
+	if r.IsActive != nil {
+		policy.IsActive = *r.IsActive
+	}
+	return policy
+}
+
+// NoShowAppealRequest represents the request body for excusing a no-show or lifting a penalty
+type NoShowAppealRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Sick leave reported after the cutoff"`
+}
+
+// GetPolicies godoc
+// @Summary List no-show policies
+// @Description Lists the no-show policies, the lowest threshold first
+// @Tags no-shows
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.NoShowPolicy
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /no-show-policies [get]
+func (h *NoShowPolicyHandler) GetPolicies(c *gin.Context) {
+	policies, err := h.policyService.GetPolicies(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// CreatePolicy godoc
+// @Summary Create no-show policy
+// @Description Creates a policy that triggers an action for users with at least threshold unexcused no-shows at the meal events of the last window_days days: a warning notification, pausing their standing orders, or holding their new requests for their line manager's approval. Restrictions last duration_days days. Policies are checked every 15 minutes, and each triggers at most once per window for a user.
+// @Tags no-shows
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param policy body NoShowPolicyRequest true "No-show policy"
+// @Success 201 {object} model.NoShowPolicy
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /no-show-policies [post]
+func (h *NoShowPolicyHandler) CreatePolicy(c *gin.Context) {
+	var req NoShowPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	policy := req.toModel()
+	if err := h.policyService.CreatePolicy(c.Request.Context(), policy, adminID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy godoc
+// @Summary Replace no-show policy
+// @Description Replaces the rule of a no-show policy; set is_active to false to switch it off. Penalties it already triggered stay in effect until they end or are lifted.
+// @Tags no-shows
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param policy_id path int true "No-show policy ID"
+// @Param policy body NoShowPolicyRequest true "No-show policy"
+// @Success 200 {object} model.NoShowPolicy
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /no-show-policies/{policy_id} [put]
+func (h *NoShowPolicyHandler) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("policy_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid no-show policy ID"})
+		return
+	}
+
+	var req NoShowPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	policy := req.toModel()
+	if err := h.policyService.UpdatePolicy(c.Request.Context(), uint(id), policy, adminID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetNoShowHistory godoc
+// @Summary Get a user's no-show history
+// @Description Lists a user's no-shows, excused ones included, the penalties they led to and the restrictions in effect. Users see their own history, line managers that of their reports, and admins everyone's.
+// @Tags no-shows
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path int true "User ID"
+// @Success 200 {object} service.NoShowHistory
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /users/{user_id}/no-shows [get]
+func (h *NoShowPolicyHandler) GetNoShowHistory(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	actorID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	history, err := h.policyService.GetNoShowHistory(c.Request.Context(), uint(userID), actorID, utils.IsAdminFromContext(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// ExcuseNoShow godoc
+// @Summary Excuse a no-show
+// @Description Stops a no-show from counting towards no-show policies, e.g. after an appeal. Penalties it contributed to are lifted separately.
+// @Tags no-shows
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Meal Request ID"
+// @Param appeal body NoShowAppealRequest true "Reason"
+// @Success 200 {object} model.MealRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /meal-requests/{id}/excuse-no-show [post]
+func (h *NoShowPolicyHandler) ExcuseNoShow(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal request ID"})
+		return
+	}
+
+	var req NoShowAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	request, err := h.policyService.ExcuseNoShow(c.Request.Context(), uint(requestID), req.Reason, adminID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// LiftPenalty godoc
+// @Summary Lift a no-show penalty
+// @Description Ends a no-show penalty early, e.g. after an appeal, and tells the user
+// @Tags no-shows
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param penalty_id path int true "No-show penalty ID"
+// @Param appeal body NoShowAppealRequest true "Reason"
+// @Success 200 {object} model.NoShowPenalty
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /no-show-penalties/{penalty_id}/lift [post]
+func (h *NoShowPolicyHandler) LiftPenalty(c *gin.Context) {
+	penaltyID, err := strconv.ParseUint(c.Param("penalty_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid no-show penalty ID"})
+		return
+	}
+
+	var req NoShowAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	penalty, err := h.policyService.LiftPenalty(c.Request.Context(), uint(penaltyID), req.Reason, adminID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, penalty)
+}
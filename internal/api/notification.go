@@ -1,8 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/arafat-hasan/mealsync/internal/errors"
 	"github.com/arafat-hasan/mealsync/internal/model"
@@ -26,20 +29,39 @@ func NewNotificationHandler(notificationService service.NotificationService) *No
 // handleError properly formats and returns API errors
 func handleError(c *gin.Context, err error) {
 	if appErr, ok := err.(*errors.AppError); ok {
+		if len(appErr.Fields) > 0 {
+			c.JSON(appErr.Code, gin.H{"error": appErr.Error(), "fields": appErr.Fields})
+			return
+		}
 		c.JSON(appErr.Code, gin.H{"error": appErr.Error()})
 		return
 	}
 	c.JSON(http.StatusInternalServerError, gin.H{"error": "An unexpected error occurred"})
 }
 
+// NotificationIDsRequest represents the request body for bulk notification operations
+type NotificationIDsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
 // GetNotifications godoc
 // @Summary Get notifications for current user
-// @Description Retrieves all notifications for the authenticated user
+// @Description Retrieves the notifications of the authenticated user, newest first. One page is returned and, when more follow, the X-Next-Cursor header and a Link header with rel="next" point to the next page.
 // @Tags notifications
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param type query string false "Comma separated notification types" example(reminder,event-info)
+// @Param read query bool false "Filter by read state"
+// @Param archived query bool false "Show archived notifications instead of the inbox"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD)"
+// @Param cursor query int false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
 // @Success 200 {array} model.Notification
+// @Header 200 {integer} X-Next-Cursor "Cursor of the next page, absent on the last page"
+// @Header 200 {string} Link "URL of the next page with rel=\"next\", absent on the last page"
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
 // @Failure 401 {object} errors.ErrorResponse "Unauthorized"
 // @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
 // @Router /notifications [get]
@@ -50,13 +72,28 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		return
 	}
 
-	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), userID)
+	filter, err := parseNotificationQuery(c)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, notifications)
+	page, err := h.notificationService.GetNotifications(c.Request.Context(), userID, filter)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if page.NextCursor != nil {
+		cursor := strconv.FormatUint(uint64(*page.NextCursor), 10)
+		next := *c.Request.URL
+		values := next.Query()
+		values.Set("cursor", cursor)
+		next.RawQuery = values.Encode()
+		c.Header("X-Next-Cursor", cursor)
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+	c.JSON(http.StatusOK, page.Items)
 }
 
 // GetNotificationsByType godoc
@@ -66,7 +103,7 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Param type path string true "Notification type" Enums(reminder, confirmation, admin-message, event-info)
+// @Param type path string true "Notification type" Enums(reminder, confirmation, admin-message, event-info, event-change, digest)
 // @Success 200 {array} model.Notification
 // @Failure 400 {object} errors.ErrorResponse "Bad Request"
 // @Failure 401 {object} errors.ErrorResponse "Unauthorized"
@@ -148,6 +185,152 @@ func (h *NotificationHandler) GetUnreadNotificationCount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"count": count})
 }
 
+// GetUndeliveredNotificationCount godoc
+// @Summary Get undelivered notification count
+// @Description Retrieves the count of undelivered notifications for the authenticated user
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/undelivered/count [get]
+func (h *NotificationHandler) GetUndeliveredNotificationCount(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	count, err := h.notificationService.GetUndeliveredNotificationCount(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"count": count})
+}
+
+// MarkAllNotificationsAsRead godoc
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/read-all [put]
+func (h *NotificationHandler) MarkAllNotificationsAsRead(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	count, err := h.notificationService.MarkAllNotificationsAsRead(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": count})
+}
+
+// DeleteNotifications godoc
+// @Summary Delete notifications in bulk
+// @Description Deletes the given notifications of the authenticated user. IDs belonging to other users are ignored.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body NotificationIDsRequest true "Notification IDs"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/bulk-delete [post]
+func (h *NotificationHandler) DeleteNotifications(c *gin.Context) {
+	var req NotificationIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	count, err := h.notificationService.DeleteNotifications(c.Request.Context(), req.IDs, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": count})
+}
+
+// ArchiveNotifications godoc
+// @Summary Archive notifications
+// @Description Moves the given notifications of the authenticated user out of the inbox into the archive
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body NotificationIDsRequest true "Notification IDs"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/archive [put]
+func (h *NotificationHandler) ArchiveNotifications(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveNotifications godoc
+// @Summary Unarchive notifications
+// @Description Moves the given notifications of the authenticated user from the archive back into the inbox
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body NotificationIDsRequest true "Notification IDs"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /notifications/unarchive [put]
+func (h *NotificationHandler) UnarchiveNotifications(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+// setArchived binds the notification IDs and updates their archive state
+func (h *NotificationHandler) setArchived(c *gin.Context, archived bool) {
+	var req NotificationIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	count, err := h.notificationService.ArchiveNotifications(c.Request.Context(), req.IDs, userID, archived)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": count})
+}
+
 // MarkNotificationAsRead godoc
 // @Summary Mark notification as read
 // @Description Marks a notification as read for the authenticated user
@@ -263,9 +446,79 @@ func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
 func isValidNotificationType(notificationType model.NotificationType) bool {
 	switch notificationType {
 	case model.NotificationTypeReminder, model.NotificationTypeConfirmation,
-		model.NotificationTypeAdminMessage, model.NotificationTypeEventInfo:
+		model.NotificationTypeAdminMessage, model.NotificationTypeEventInfo,
+		model.NotificationTypeEventChange, model.NotificationTypeDigest:
 		return true
 	default:
 		return false
 	}
 }
+
+// parseNotificationQuery reads the inbox filters and cursor from the query string
+func parseNotificationQuery(c *gin.Context) (model.NotificationFilter, error) {
+	var filter model.NotificationFilter
+
+	if types := c.Query("type"); types != "" {
+		for _, value := range strings.Split(types, ",") {
+			notificationType := model.NotificationType(strings.TrimSpace(value))
+			if !isValidNotificationType(notificationType) {
+				return filter, errors.NewValidationError("invalid notification type: "+value, nil)
+			}
+			filter.Types = append(filter.Types, notificationType)
+		}
+	}
+
+	if read := c.Query("read"); read != "" {
+		parsed, err := strconv.ParseBool(read)
+		if err != nil {
+			return filter, errors.NewValidationError("invalid read filter", err)
+		}
+		filter.Read = &parsed
+	}
+
+	if archived := c.Query("archived"); archived != "" {
+		parsed, err := strconv.ParseBool(archived)
+		if err != nil {
+			return filter, errors.NewValidationError("invalid archived filter", err)
+		}
+		filter.Archived = parsed
+	}
+
+	for param, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseQueryTime(value)
+		if err != nil {
+			return filter, errors.NewValidationError("invalid "+param+" date", err)
+		}
+		*target = &parsed
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		parsed, err := strconv.ParseUint(cursor, 10, 32)
+		if err != nil {
+			return filter, errors.NewValidationError("invalid cursor", err)
+		}
+		filter.Cursor = uint(parsed)
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 {
+			return filter, errors.NewValidationError("invalid limit", err)
+		}
+		filter.Limit = parsed
+	}
+
+	return filter, nil
+}
+
+// parseQueryTime accepts either a full RFC3339 timestamp or a plain date
+func parseQueryTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -2,23 +2,31 @@ package api
 
 import (
 	"github.com/arafat-hasan/mealsync/internal/config"
+	"github.com/arafat-hasan/mealsync/internal/idempotency"
 	"github.com/arafat-hasan/mealsync/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRoutes configures all API routes
-func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, mealHandler *MealEventHandler, menuSetHandler *MenuSetHandler, MenuItemCommentHandler *MenuItemCommentHandler, menuItemHandler *MenuItemHandler, mealRequestHandler *MealRequestHandler, notificationHandler *NotificationHandler) {
+func SetupRoutes(r *gin.Engine, cfg *config.Config, idempotencyStore idempotency.Store, authHandler *AuthHandler, mealHandler *MealEventHandler, menuSetHandler *MenuSetHandler, MenuItemCommentHandler *MenuItemCommentHandler, menuItemHandler *MenuItemHandler, mealRequestHandler *MealRequestHandler, notificationHandler *NotificationHandler, digestHandler *DigestHandler, seriesHandler *MealEventSeriesHandler, holidayHandler *HolidayHandler, templateHandler *MealEventTemplateHandler, mealTypeDefaultHandler *MealTypeDefaultHandler, estimationHandler *EstimationHandler, timeZoneHandler *TimeZoneHandler, calendarHandler *CalendarHandler, standingOrderHandler *StandingOrderHandler, guestRequestHandler *GuestRequestHandler, teamHandler *TeamHandler, mealPlanHandler *MealPlanHandler, pickupHandler *MealPickupHandler, noShowPolicyHandler *NoShowPolicyHandler, transferHandler *MealTransferHandler, dietaryHandler *DietaryHandler) {
 	// Public routes (no auth required)
 	public := r.Group("/api")
 	{
 		public.POST("/register", authHandler.Register)
 		public.POST("/login", authHandler.Login)
 		public.POST("/refresh", authHandler.RefreshToken)
+
+		// Calendar clients cannot log in; the token in the URL authorizes them
+		public.GET("/calendar/:token", calendarHandler.GetFeed)
 	}
 
 	// Protected routes
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware(cfg))
+	// Retried POST, PUT, PATCH and DELETE requests with the same Idempotency-Key get the first response
+	protected.Use(middleware.Idempotency(idempotencyStore, cfg.IdempotencyTTL))
+	// GET requests whose If-None-Match names the current ETag get 304 Not Modified
+	protected.Use(middleware.ConditionalGet())
 	{
 		// Meal event routes
 		meals := protected.Group("/meals")
@@ -36,6 +44,15 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, me
 				meal.GET("", mealHandler.GetMealEventByID)
 				meal.PUT("", mealHandler.UpdateMealEvent)
 				meal.DELETE("", mealHandler.DeleteMealEvent)
+				meal.POST("/clone", middleware.AdminOnly(), mealHandler.CloneMealEvent)
+				meal.POST("/status", middleware.AdminOnly(), mealHandler.UpdateMealEventStatus)
+				meal.GET("/transitions", middleware.AdminOnly(), mealHandler.GetMealEventTransitions)
+				meal.GET("/revisions", middleware.AdminOnly(), mealHandler.GetMealEventRevisions)
+				meal.GET("/guests", middleware.AdminOnly(), guestRequestHandler.GetEventGuests)
+				meal.GET("/attendees", middleware.AdminOnly(), guestRequestHandler.ExportAttendees)
+				meal.GET("/pickups", middleware.AdminOnly(), pickupHandler.GetMealPickups)
+				meal.GET("/late-requests", middleware.AdminOnly(), mealRequestHandler.GetLateRequests)
+				meal.PUT("/surplus", middleware.AdminOnly(), mealRequestHandler.SetSurplus)
 
 				// Comment routes under meal event
 				comments := meal.Group("/comments")
@@ -46,6 +63,56 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, me
 			}
 		}
 
+		// Recurring meal event series routes
+		series := protected.Group("/meal-series")
+		series.Use(middleware.AdminOnly())
+		{
+			series.GET("", seriesHandler.GetSeries)
+			series.POST("", seriesHandler.CreateSeries)
+			series.GET("/:series_id", seriesHandler.GetSeriesByID)
+			series.DELETE("/:series_id", seriesHandler.EndSeries)
+			series.PUT("/:series_id/occurrences/:meal_id", seriesHandler.EditOccurrence)
+		}
+
+		// Meal event template routes
+		templates := protected.Group("/meal-templates")
+		templates.Use(middleware.AdminOnly())
+		{
+			templates.GET("", templateHandler.GetTemplates)
+			templates.POST("", templateHandler.CreateTemplate)
+			templates.GET("/:template_id", templateHandler.GetTemplateByID)
+			templates.PUT("/:template_id", templateHandler.UpdateTemplate)
+			templates.DELETE("/:template_id", templateHandler.DeleteTemplate)
+			templates.POST("/:template_id/events", templateHandler.CreateEventFromTemplate)
+		}
+
+		// Meal type default routes
+		mealTypes := protected.Group("/meal-types")
+		{
+			mealTypes.GET("", mealTypeDefaultHandler.GetMealTypeDefaults)
+			mealTypes.GET("/:meal_type", mealTypeDefaultHandler.GetMealTypeDefault)
+			mealTypes.PUT("/:meal_type", middleware.AdminOnly(), mealTypeDefaultHandler.SetMealTypeDefault)
+			mealTypes.DELETE("/:meal_type", middleware.AdminOnly(), mealTypeDefaultHandler.DeleteMealTypeDefault)
+		}
+
+		// Meal estimation routes
+		estimations := protected.Group("/estimations")
+		estimations.Use(middleware.AdminOnly())
+		{
+			estimations.GET("", estimationHandler.GetEstimates)
+		}
+
+		// Holiday and closure calendar routes
+		holidays := protected.Group("/holidays")
+		{
+			holidays.GET("", holidayHandler.GetHolidays)
+			holidays.POST("", middleware.AdminOnly(), holidayHandler.CreateHoliday)
+			holidays.POST("/import", middleware.AdminOnly(), holidayHandler.ImportHolidays)
+			holidays.DELETE("/:holiday_id", middleware.AdminOnly(), holidayHandler.DeleteHoliday)
+			holidays.GET("/:holiday_id/affected-events", middleware.AdminOnly(), holidayHandler.GetAffectedEvents)
+			holidays.POST("/:holiday_id/cancel-events", middleware.AdminOnly(), holidayHandler.CancelAffectedEvents)
+		}
+
 		// Menu set routes
 		menus := protected.Group("/menus")
 		{
@@ -77,9 +144,25 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, me
 			mealRequests.GET("", mealRequestHandler.GetMealRequests)
 			mealRequests.GET("/:id", mealRequestHandler.GetMealRequestByID)
 			mealRequests.POST("", mealRequestHandler.CreateMealRequest)
+			mealRequests.POST("/late", mealRequestHandler.CreateLateRequest)
 			mealRequests.PUT("/:id", mealRequestHandler.UpdateMealRequest)
 			mealRequests.DELETE("/:id", mealRequestHandler.DeleteMealRequest)
 			mealRequests.PUT("/:id/status", mealRequestHandler.UpdateRequestStatus)
+			mealRequests.GET("/:id/history", mealRequestHandler.GetRequestHistory)
+			mealRequests.POST("/:id/late-review", middleware.AdminOnly(), mealRequestHandler.ReviewLateRequest)
+			mealRequests.GET("/:id/pickup-token", pickupHandler.GetPickupToken)
+			mealRequests.POST("/:id/excuse-no-show", middleware.AdminOnly(), noShowPolicyHandler.ExcuseNoShow)
+			mealRequests.POST("/:id/offer", transferHandler.OfferMeal)
+			mealRequests.GET("/:id/transfers", transferHandler.GetRequestTransfers)
+
+			// Admins act for employees, also after the cutoff
+			mealRequests.POST("/bulk", middleware.AdminOnly(), mealRequestHandler.AssignMealRequests)
+			mealRequests.POST("/bulk/cancel", middleware.AdminOnly(), mealRequestHandler.CancelMealRequests)
+			mealRequests.POST("/bulk/import", middleware.AdminOnly(), mealRequestHandler.ImportMealRequests)
+
+			// Week-ahead planning of the current user's meals
+			mealRequests.GET("/plan", mealPlanHandler.GetMealPlan)
+			mealRequests.POST("/plan", mealPlanHandler.ApplyMealPlan)
 
 			// Meal request items
 			mealRequests.GET("/:id/items", mealRequestHandler.GetRequestItems)
@@ -87,6 +170,35 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, me
 			mealRequests.DELETE("/:id/items/:item_id", mealRequestHandler.RemoveRequestItem)
 		}
 
+		// Meal transfer routes, for confirmed meals changing hands after the cutoff
+		mealTransfers := protected.Group("/meal-transfers")
+		{
+			mealTransfers.GET("", transferHandler.GetMyTransfers)
+			mealTransfers.GET("/pool", transferHandler.GetPool)
+			mealTransfers.POST("/:transfer_id/accept", transferHandler.AcceptOffer)
+			mealTransfers.POST("/:transfer_id/decline", transferHandler.DeclineOffer)
+			mealTransfers.POST("/:transfer_id/withdraw", transferHandler.WithdrawOffer)
+			mealTransfers.POST("/:transfer_id/claim", transferHandler.ClaimMeal)
+		}
+
+		// Meal pickup routes; kiosks are operated by admins
+		pickups := protected.Group("/pickups")
+		{
+			pickups.GET("/verification-key", pickupHandler.GetVerificationKey)
+			pickups.POST("/scan", middleware.AdminOnly(), pickupHandler.ScanPickup)
+			pickups.GET("/no-shows", middleware.AdminOnly(), pickupHandler.GetNoShowReport)
+		}
+
+		// No-show policy routes
+		noShowPolicies := protected.Group("/no-show-policies")
+		noShowPolicies.Use(middleware.AdminOnly())
+		{
+			noShowPolicies.GET("", noShowPolicyHandler.GetPolicies)
+			noShowPolicies.POST("", noShowPolicyHandler.CreatePolicy)
+			noShowPolicies.PUT("/:policy_id", noShowPolicyHandler.UpdatePolicy)
+		}
+		protected.POST("/no-show-penalties/:penalty_id/lift", middleware.AdminOnly(), noShowPolicyHandler.LiftPenalty)
+
 		// Comment routes
 		comments := protected.Group("/comments")
 		{
@@ -100,6 +212,75 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, me
 		users := protected.Group("/users")
 		{
 			users.GET("/:user_id/comments", MenuItemCommentHandler.GetUserComments)
+			users.PUT("/:user_id/manager", middleware.AdminOnly(), teamHandler.SetManager)
+			users.GET("/:user_id/no-shows", noShowPolicyHandler.GetNoShowHistory)
+		}
+
+		// Team routes for line managers acting for their reports
+		team := protected.Group("/team")
+		team.Use(middleware.ManagerOnly())
+		{
+			team.GET("", teamHandler.GetTeam)
+			team.GET("/summary", teamHandler.GetTeamSummary)
+			team.GET("/meals/:meal_id", teamHandler.GetTeamEventStatus)
+			team.POST("/requests", teamHandler.PlaceTeamRequest)
+			team.POST("/requests/:request_id/withdraw", teamHandler.WithdrawTeamRequest)
+			team.GET("/approvals", teamHandler.GetPendingApprovals)
+			team.POST("/requests/:request_id/approve", teamHandler.ApproveTeamRequest)
+		}
+
+		// Reporting line routes
+		protected.POST("/reporting-lines/import", middleware.AdminOnly(), teamHandler.ImportReportingLines)
+
+		// Profile routes for the current user
+		profile := protected.Group("/profile")
+		{
+			profile.GET("/timezone", timeZoneHandler.GetTimeZone)
+			profile.PUT("/timezone", timeZoneHandler.UpdateTimeZone)
+			profile.GET("/dietary", dietaryHandler.GetDietaryProfile)
+			profile.PUT("/dietary", dietaryHandler.UpdateDietaryProfile)
+		}
+
+		// Guest meal request routes
+		guestRequests := protected.Group("/guest-requests")
+		{
+			guestRequests.GET("", guestRequestHandler.GetGuestRequests)
+			guestRequests.POST("", guestRequestHandler.CreateGuestRequest)
+			guestRequests.GET("/pending", middleware.AdminOnly(), guestRequestHandler.GetPendingGuestRequests)
+			guestRequests.GET("/:guest_id", guestRequestHandler.GetGuestRequest)
+			guestRequests.PUT("/:guest_id", guestRequestHandler.UpdateGuestRequest)
+			guestRequests.DELETE("/:guest_id", guestRequestHandler.CancelGuestRequest)
+			guestRequests.POST("/:guest_id/review", middleware.AdminOnly(), guestRequestHandler.ReviewGuestRequest)
+		}
+
+		// Department guest quota routes
+		guestQuotas := protected.Group("/guest-quotas")
+		guestQuotas.Use(middleware.AdminOnly())
+		{
+			guestQuotas.GET("", guestRequestHandler.GetGuestQuotas)
+			guestQuotas.PUT("/:department", guestRequestHandler.SetGuestQuota)
+			guestQuotas.DELETE("/:department", guestRequestHandler.DeleteGuestQuota)
+		}
+
+		// Standing order routes
+		standingOrders := protected.Group("/standing-orders")
+		{
+			standingOrders.GET("", standingOrderHandler.GetStandingOrders)
+			standingOrders.POST("", standingOrderHandler.CreateStandingOrder)
+			standingOrders.GET("/pauses", standingOrderHandler.GetStandingOrderPauses)
+			standingOrders.POST("/pauses", standingOrderHandler.CreateStandingOrderPause)
+			standingOrders.DELETE("/pauses/:pause_id", standingOrderHandler.DeleteStandingOrderPause)
+			standingOrders.GET("/:order_id", standingOrderHandler.GetStandingOrder)
+			standingOrders.PUT("/:order_id", standingOrderHandler.UpdateStandingOrder)
+			standingOrders.DELETE("/:order_id", standingOrderHandler.DeleteStandingOrder)
+		}
+
+		// Calendar feed management routes
+		calendarFeeds := protected.Group("/calendar-feeds")
+		{
+			calendarFeeds.GET("", calendarHandler.GetFeeds)
+			calendarFeeds.POST("", calendarHandler.CreateFeed)
+			calendarFeeds.DELETE("/:feed_id", calendarHandler.RevokeFeed)
 		}
 
 		// Notification routes
@@ -108,7 +289,14 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config, authHandler *AuthHandler, me
 			notifications.GET("", notificationHandler.GetNotifications)
 			notifications.GET("/unread", notificationHandler.GetUnreadNotifications)
 			notifications.GET("/unread/count", notificationHandler.GetUnreadNotificationCount)
+			notifications.GET("/undelivered/count", notificationHandler.GetUndeliveredNotificationCount)
 			notifications.GET("/type/:type", notificationHandler.GetNotificationsByType)
+			notifications.PUT("/read-all", notificationHandler.MarkAllNotificationsAsRead)
+			notifications.POST("/bulk-delete", notificationHandler.DeleteNotifications)
+			notifications.PUT("/archive", notificationHandler.ArchiveNotifications)
+			notifications.PUT("/unarchive", notificationHandler.UnarchiveNotifications)
+			notifications.PUT("/digest", digestHandler.UpdateDigestPreference)
+			notifications.GET("/digest/preview", digestHandler.PreviewDigest)
 			notifications.PUT("/:notification_id/read", notificationHandler.MarkNotificationAsRead)
 			notifications.PUT("/:notification_id/delivered", notificationHandler.MarkNotificationAsDelivered)
 			notifications.DELETE("/:notification_id", notificationHandler.DeleteNotification)
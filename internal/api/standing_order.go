@@ -0,0 +1,387 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// StandingOrderHandler handles standing order requests
+type StandingOrderHandler struct {
+	standingOrderService service.StandingOrderService
+}
+
+// NewStandingOrderHandler creates a new instance of StandingOrderHandler
+func NewStandingOrderHandler(standingOrderService service.StandingOrderService) *StandingOrderHandler {
+	return &StandingOrderHandler{
+		standingOrderService: standingOrderService,
+	}
+}
+
+// StandingOrderItemRequest deselects a menu item or adds a note to it
+type StandingOrderItemRequest struct {
+	MenuItemID uint   `json:"menu_item_id" binding:"required" example:"7"`
+	IsSelected *bool  `json:"is_selected" example:"false"` // defaults to true
+	Notes      string `json:"notes" example:"No onions"`
+}
+
+// StandingOrderRequest represents the request body for creating or replacing a standing order
+type StandingOrderRequest struct {
+	MealType       model.MealType              `json:"meal_type" binding:"required" enums:"breakfast,lunch,snacks" example:"lunch"`
+	Weekdays       string                      `json:"weekdays" example:"mon,tue,wed,thu,fri"`
+	EventAddressID uint                        `json:"event_address_id" binding:"required" example:"1"`
+	MenuSetID      *uint                       `json:"menu_set_id" example:"2"`
+	Fallback       model.StandingOrderFallback `json:"fallback" enums:"skip,any_set" example:"any_set"`
+	IsActive       *bool                       `json:"is_active"`
+	Items          []StandingOrderItemRequest  `json:"items"`
+}
+
+// toModel converts the request into a standing order
+func (r *StandingOrderRequest) toModel() *model.StandingOrder {
+	order := &model.StandingOrder{
+		MealType:       r.MealType,
+		Weekdays:       r.Weekdays,
+		EventAddressID: r.EventAddressID,
+		MenuSetID:      r.MenuSetID,
+		Fallback:       r.Fallback,
+		IsActive:       r.IsActive == nil || *r.IsActive,
+	}
+	for _, item := range r.Items {
+		order.Items = append(order.Items, model.StandingOrderItem{
+			MenuItemID: item.MenuItemID,
+			IsSelected: item.IsSelected == nil || *item.IsSelected,
+			Notes:      item.Notes,
+		})
+	}
+	return order
+}
+
+// StandingOrderPauseRequest represents the request body for pausing standing orders
+type StandingOrderPauseRequest struct {
+	StartDate string `json:"start_date" binding:"required" example:"2025-07-01"`
+	EndDate   string `json:"end_date" binding:"required" example:"2025-07-14"`
+	Reason    string `json:"reason" example:"Vacation"`
+}
+
+// GetStandingOrders godoc
+// @Summary List standing orders
+// @Description Retrieves the current user's standing orders
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.StandingOrder
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /standing-orders [get]
+func (h *StandingOrderHandler) GetStandingOrders(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	orders, err := h.standingOrderService.ListStandingOrders(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetStandingOrder godoc
+// @Summary Get standing order
+// @Description Retrieves one of the current user's standing orders. The ETag header is sent back in If-Match to replace or delete it, or in If-None-Match to get 304 Not Modified while it is unchanged.
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param order_id path int true "Standing order ID"
+// @Param If-None-Match header string false "ETag of the copy the client has"
+// @Success 200 {object} model.StandingOrder
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /standing-orders/{order_id} [get]
+func (h *StandingOrderHandler) GetStandingOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid standing order ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order, err := h.standingOrderService.GetStandingOrder(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, order.Version, order)
+}
+
+// CreateStandingOrder godoc
+// @Summary Create standing order
+// @Description Requests a meal automatically whenever a meal event of the given type is published on one of the given weekdays. Without a preferred menu set the first offered set is requested; otherwise the fallback decides whether another set is requested or none. Requests created this way can be changed or withdrawn until the cutoff.
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param order body StandingOrderRequest true "Standing order"
+// @Success 201 {object} model.StandingOrder
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /standing-orders [post]
+func (h *StandingOrderHandler) CreateStandingOrder(c *gin.Context) {
+	var req StandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order := req.toModel()
+	if err := h.standingOrderService.CreateStandingOrder(c.Request.Context(), order, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// UpdateStandingOrder godoc
+// @Summary Replace standing order
+// @Description Replaces one of the current user's standing orders. Requests it already created are kept. If-Match must carry the ETag the standing order was read with; when it was changed since, the request fails with 412 and the current standing order.
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param order_id path int true "Standing order ID"
+// @Param If-Match header string true "ETag of the standing order"
+// @Param order body StandingOrderRequest true "Standing order"
+// @Success 200 {object} model.StandingOrder
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 412 {object} model.StandingOrder "Current standing order"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /standing-orders/{order_id} [put]
+func (h *StandingOrderHandler) UpdateStandingOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid standing order ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req StandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	order := req.toModel()
+	if err := h.standingOrderService.UpdateStandingOrder(c.Request.Context(), uint(id), version, order, userID); err != nil {
+		handleVersionError(c, err, h.currentStandingOrder(c, uint(id), userID))
+		return
+	}
+
+	writeVersioned(c, http.StatusOK, order.Version, order)
+}
+
+// DeleteStandingOrder godoc
+// @Summary Delete standing order
+// @Description Deletes one of the current user's standing orders. Requests it already created are kept. If-Match must carry the ETag the standing order was read with; when it was changed since, the request fails with 412 and the current standing order.
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param order_id path int true "Standing order ID"
+// @Param If-Match header string true "ETag of the standing order"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 412 {object} model.StandingOrder "Current standing order"
+// @Failure 428 {object} errors.ErrorResponse "Precondition Required"
+// @Router /standing-orders/{order_id} [delete]
+func (h *StandingOrderHandler) DeleteStandingOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid standing order ID"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.standingOrderService.DeleteStandingOrder(c.Request.Context(), uint(id), version, userID); err != nil {
+		handleVersionError(c, err, h.currentStandingOrder(c, uint(id), userID))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Standing order deleted"})
+}
+
+// currentStandingOrder loads the stored standing order for a 412 response
+func (h *StandingOrderHandler) currentStandingOrder(c *gin.Context, id uint, userID uint) func() (interface{}, int, error) {
+	return func() (interface{}, int, error) {
+		order, err := h.standingOrderService.GetStandingOrder(c.Request.Context(), id, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return order, order.Version, nil
+	}
+}
+
+// GetStandingOrderPauses godoc
+// @Summary List standing order pauses
+// @Description Retrieves the date ranges in which the current user's standing orders are paused
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.StandingOrderPause
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /standing-orders/pauses [get]
+func (h *StandingOrderHandler) GetStandingOrderPauses(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	pauses, err := h.standingOrderService.ListPauses(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pauses)
+}
+
+// CreateStandingOrderPause godoc
+// @Summary Pause standing orders
+// @Description Pauses all of the current user's standing orders from the start to the end date, both included, e.g. for a vacation
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param pause body StandingOrderPauseRequest true "Pause"
+// @Success 201 {object} model.StandingOrderPause
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /standing-orders/pauses [post]
+func (h *StandingOrderHandler) CreateStandingOrderPause(c *gin.Context) {
+	var req StandingOrderPauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	pause := &model.StandingOrderPause{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    req.Reason,
+	}
+	if err := h.standingOrderService.CreatePause(c.Request.Context(), pause, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pause)
+}
+
+// DeleteStandingOrderPause godoc
+// @Summary Delete standing order pause
+// @Description Ends or removes one of the current user's pauses
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param pause_id path int true "Pause ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /standing-orders/pauses/{pause_id} [delete]
+func (h *StandingOrderHandler) DeleteStandingOrderPause(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("pause_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pause ID"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.standingOrderService.DeletePause(c.Request.Context(), uint(id), userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Standing order pause deleted"})
+}
@@ -0,0 +1,483 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// TeamHandler handles line managers acting for their reports
+type TeamHandler struct {
+	teamService     service.TeamService
+	timeZoneService service.TimeZoneService
+}
+
+// NewTeamHandler creates a new instance of TeamHandler
+func NewTeamHandler(teamService service.TeamService, timeZoneService service.TimeZoneService) *TeamHandler {
+	return &TeamHandler{
+		teamService:     teamService,
+		timeZoneService: timeZoneService,
+	}
+}
+
+// TeamRequestRequest represents the request body for requesting a meal for a report
+type TeamRequestRequest struct {
+	UserID         uint `json:"user_id" binding:"required" example:"4"`
+	MealEventID    uint `json:"meal_event_id" binding:"required" example:"12"`
+	MenuSetID      uint `json:"menu_set_id" binding:"required" example:"2"`
+	EventAddressID uint `json:"event_address_id" binding:"required" example:"1"`
+}
+
+// TeamWithdrawRequest represents the request body for withdrawing a report's meal request
+type TeamWithdrawRequest struct {
+	Reason string `json:"reason" example:"Offsite with a client all day"`
+}
+
+// SetManagerRequest represents the request body for setting an employee's line manager
+type SetManagerRequest struct {
+	ManagerID *uint `json:"manager_id" example:"3"` // null clears the line manager
+}
+
+// GetTeam godoc
+// @Summary List my team
+// @Description Retrieves the employees who report directly to the current user. Admins may look at another manager's team.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param manager_id query int false "Manager ID (admins only)"
+// @Success 200 {array} model.User
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team [get]
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	managerID, ok := teamManagerID(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.teamService.GetTeam(c.Request.Context(), managerID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// GetTeamEventStatus godoc
+// @Summary Get my team's requests for a meal
+// @Description Shows for each report of the current user whether they requested a meal event, and their request if so
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param meal_id path int true "Meal Event ID"
+// @Param manager_id query int false "Manager ID (admins only)"
+// @Success 200 {object} service.TeamEventStatus
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team/meals/{meal_id} [get]
+func (h *TeamHandler) GetTeamEventStatus(c *gin.Context) {
+	mealID, err := strconv.ParseUint(c.Param("meal_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal event ID"})
+		return
+	}
+
+	managerID, ok := teamManagerID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.teamService.GetTeamEventStatus(c.Request.Context(), managerID, uint(mealID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// PlaceTeamRequest godoc
+// @Summary Request a meal for a report
+// @Description Requests a meal for an employee who reports to the current user, e.g. one who is in meetings or offsite. The request belongs to the employee, records the manager as its creator, and the employee is notified.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body TeamRequestRequest true "Meal request"
+// @Success 201 {object} model.MealRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team/requests [post]
+func (h *TeamHandler) PlaceTeamRequest(c *gin.Context) {
+	var req TeamRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	managerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	request := &model.MealRequest{
+		MealEventID:    req.MealEventID,
+		MenuSetID:      req.MenuSetID,
+		EventAddressID: req.EventAddressID,
+	}
+	if err := h.teamService.PlaceTeamRequest(c.Request.Context(), managerID, req.UserID, request); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+// WithdrawTeamRequest godoc
+// @Summary Withdraw a report's meal request
+// @Description Cancels the meal request of an employee who reports to the current user before the cutoff. The employee is notified.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request_id path int true "Meal Request ID"
+// @Param withdrawal body TeamWithdrawRequest false "Reason"
+// @Success 200 {object} model.MealRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team/requests/{request_id}/withdraw [post]
+func (h *TeamHandler) WithdrawTeamRequest(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("request_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal request ID"})
+		return
+	}
+
+	var req TeamWithdrawRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	managerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	request, err := h.teamService.WithdrawTeamRequest(c.Request.Context(), managerID, uint(requestID), req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// GetPendingApprovals godoc
+// @Summary List my team's requests awaiting approval
+// @Description Lists the pending meal requests of the current user's reports that wait for the manager's approval after repeated no-shows. Requests not approved when their meal event is confirmed are rejected.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param manager_id query int false "Manager ID (admins only)"
+// @Success 200 {array} model.MealRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team/approvals [get]
+func (h *TeamHandler) GetPendingApprovals(c *gin.Context) {
+	managerID, ok := teamManagerID(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.teamService.GetPendingApprovals(c.Request.Context(), managerID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// ApproveTeamRequest godoc
+// @Summary Approve a report's meal request
+// @Description Approves a meal request of an employee who reports to the current user and waits for approval after repeated no-shows. The employee is notified. To turn the request down, withdraw it.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request_id path int true "Meal Request ID"
+// @Success 200 {object} model.MealRequest
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 409 {object} errors.ErrorResponse "Conflict"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team/requests/{request_id}/approve [post]
+func (h *TeamHandler) ApproveTeamRequest(c *gin.Context) {
+	requestID, err := strconv.ParseUint(c.Param("request_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meal request ID"})
+		return
+	}
+
+	managerID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	request, err := h.teamService.ApproveTeamRequest(c.Request.Context(), managerID, uint(requestID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// GetTeamSummary godoc
+// @Summary Get my team's meal participation
+// @Description Counts the meals each report of the current user requested and cancelled for the events within a date range, and how often they took part. Dates are calendar days in the organization time zone. Defaults to the last 30 days.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_date query string false "Start Date (YYYY-MM-DD)"
+// @Param end_date query string false "End Date (YYYY-MM-DD)"
+// @Param manager_id query int false "Manager ID (admins only)"
+// @Success 200 {object} service.TeamSummary
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /team/summary [get]
+func (h *TeamHandler) GetTeamSummary(c *gin.Context) {
+	loc := h.timeZoneService.OrganizationLocation()
+	endDate := utils.EndOfDay(time.Now(), loc)
+	startDate := utils.StartOfDay(endDate.AddDate(0, 0, -29), loc)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := utils.ParseDate(value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			return
+		}
+		startDate = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := utils.ParseDate(value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			return
+		}
+		endDate = utils.EndOfDay(parsed, loc)
+	}
+
+	managerID, ok := teamManagerID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.teamService.GetTeamSummary(c.Request.Context(), managerID, startDate, endDate)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// SetManager godoc
+// @Summary Set an employee's line manager
+// @Description Sets or clears the line manager of an employee. Employees who gain a report become managers and need to sign in again to use the team endpoints.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path int true "User ID"
+// @Param manager body SetManagerRequest true "Line manager"
+// @Success 200 {object} model.User
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /users/{user_id}/manager [put]
+func (h *TeamHandler) SetManager(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req SetManagerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	user, err := h.teamService.SetManager(c.Request.Context(), uint(userID), req.ManagerID, adminID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ImportReportingLines godoc
+// @Summary Import reporting lines
+// @Description Assigns employees to their line managers from a CSV file with a header row holding employee_id and manager_employee_id columns. An empty manager_employee_id clears the employee's line manager. The response reports the outcome per line.
+// @Tags team
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param file formData file true "CSV file with employee_id and manager_employee_id columns"
+// @Success 200 {object} service.ReportingLineImport
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /reporting-lines/import [post]
+func (h *TeamHandler) ImportReportingLines(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A CSV file is required"})
+		return
+	}
+	if fileHeader.Size > maxBulkImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The CSV file is too large"})
+		return
+	}
+
+	adminID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the CSV file"})
+		return
+	}
+	defer file.Close()
+
+	lines, err := readReportingLines(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.teamService.ImportReportingLines(c.Request.Context(), lines, adminID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// teamManagerID returns the manager whose team is looked at: the caller, or for admins
+// the manager given in the query. It responds with an error and returns false otherwise.
+func teamManagerID(c *gin.Context) (uint, bool) {
+	if value := c.Query("manager_id"); value != "" && utils.IsAdminFromContext(c) {
+		managerID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manager ID"})
+			return 0, false
+		}
+		return uint(managerID), true
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// readReportingLines reads the employee_id and manager_employee_id columns of a CSV file
+func readReportingLines(r io.Reader) ([]service.ReportingLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("the CSV file has no header row")
+	}
+	employeeColumn, managerColumn := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "employee_id":
+			employeeColumn = i
+		case "manager_employee_id":
+			managerColumn = i
+		}
+	}
+	if employeeColumn < 0 || managerColumn < 0 {
+		return nil, fmt.Errorf("the CSV file needs employee_id and manager_employee_id columns")
+	}
+
+	var lines []service.ReportingLine
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %v", err)
+		}
+		if employeeColumn >= len(record) || strings.TrimSpace(record[employeeColumn]) == "" {
+			continue
+		}
+		line := service.ReportingLine{EmployeeID: strings.TrimSpace(record[employeeColumn])}
+		if managerColumn < len(record) {
+			line.ManagerEmployeeID = strings.TrimSpace(record[managerColumn])
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/arafat-hasan/mealsync/internal/service"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// TimeZoneHandler handles time zone preference requests
+type TimeZoneHandler struct {
+	timeZoneService service.TimeZoneService
+}
+
+// NewTimeZoneHandler creates a new instance of TimeZoneHandler
+func NewTimeZoneHandler(timeZoneService service.TimeZoneService) *TimeZoneHandler {
+	return &TimeZoneHandler{
+		timeZoneService: timeZoneService,
+	}
+}
+
+// TimeZoneRequest represents the request body for updating the time zone preference
+type TimeZoneRequest struct {
+	TimeZone string `json:"time_zone" example:"Europe/Berlin"`
+}
+
+// GetTimeZone godoc
+// @Summary Get time zone preference
+// @Description Retrieves the current user's time zone preference, the organization default and the zone in effect
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} service.TimeZoneSettings
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Router /profile/timezone [get]
+func (h *TimeZoneHandler) GetTimeZone(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	settings, err := h.timeZoneService.GetTimeZoneSettings(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateTimeZone godoc
+// @Summary Update time zone preference
+// @Description Sets the IANA time zone used for the current user's date ranges and digests. An empty value falls back to the organization default.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body TimeZoneRequest true "Time zone"
+// @Success 200 {object} service.TimeZoneSettings
+// @Failure 400 {object} errors.ErrorResponse "Bad Request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Not Found"
+// @Failure 500 {object} errors.ErrorResponse "Internal Server Error"
+// @Router /profile/timezone [put]
+func (h *TimeZoneHandler) UpdateTimeZone(c *gin.Context) {
+	var req TimeZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	settings, err := h.timeZoneService.UpdateUserTimeZone(c.Request.Context(), userID, req.TimeZone)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -1,32 +1,106 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration
 type Config struct {
 	// Add configuration fields as needed
-	JWTSecret        string
-	JWTRefreshSecret string
-	DBHost           string
-	DBPort           string
-	DBUser           string
-	DBPass           string
-	DBName           string
+	JWTSecret                 string
+	JWTRefreshSecret          string
+	DBHost                    string
+	DBPort                    string
+	DBUser                    string
+	DBPass                    string
+	DBName                    string
+	DigestHour                int
+	DigestWeekday             time.Weekday
+	NotificationRetentionDays int
+	TimeZone                  string
+	PublicURL                 string
+	SMTPHost                  string
+	SMTPPort                  int
+	SMTPUsername              string
+	SMTPPassword              string
+	MailFrom                  string
+	ClosurePolicy             string
+	PickupSigningKey          []byte // Ed25519 seed that signs meal pickup tokens
+	PickupTokenTTL            time.Duration
+	NoShowGrace               time.Duration
+	IdempotencyStore          string // where Idempotency-Key responses are kept: postgres or memory
+	IdempotencyTTL            time.Duration
 }
 
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
-	return &Config{
-		JWTSecret:        getEnvOrDefault("JWT_SECRET", "your-secret-key"),
-		JWTRefreshSecret: getEnvOrDefault("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
-		DBHost:           getEnvOrDefault("DB_HOST", "localhost"),
-		DBPort:           getEnvOrDefault("DB_PORT", "5432"),
-		DBUser:           getEnvOrDefault("DB_USER", "postgres"),
-		DBPass:           getEnvOrDefault("DB_PASS", "postgres"),
-		DBName:           getEnvOrDefault("DB_NAME", "mealsync"),
-	}, nil
+	cfg := &Config{
+		JWTSecret:                 getEnvOrDefault("JWT_SECRET", "your-secret-key"),
+		JWTRefreshSecret:          getEnvOrDefault("JWT_REFRESH_SECRET", "your-refresh-secret-key"),
+		DBHost:                    getEnvOrDefault("DB_HOST", "localhost"),
+		DBPort:                    getEnvOrDefault("DB_PORT", "5432"),
+		DBUser:                    getEnvOrDefault("DB_USER", "postgres"),
+		DBPass:                    getEnvOrDefault("DB_PASS", "postgres"),
+		DBName:                    getEnvOrDefault("DB_NAME", "mealsync"),
+		DigestHour:                getEnvIntOrDefault("DIGEST_HOUR", 7),
+		DigestWeekday:             getEnvWeekdayOrDefault("DIGEST_WEEKDAY", time.Monday),
+		NotificationRetentionDays: getEnvIntOrDefault("NOTIFICATION_RETENTION_DAYS", 90),
+		TimeZone:                  getEnvOrDefault("TIMEZONE", "UTC"),
+		PublicURL:                 strings.TrimRight(getEnvOrDefault("PUBLIC_URL", "http://localhost:8080"), "/"),
+		SMTPHost:                  os.Getenv("SMTP_HOST"),
+		SMTPPort:                  getEnvIntOrDefault("SMTP_PORT", 587),
+		SMTPUsername:              os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:              os.Getenv("SMTP_PASSWORD"),
+		MailFrom:                  getEnvOrDefault("MAIL_FROM", "mealsync@localhost"),
+		ClosurePolicy:             getEnvOrDefault("CLOSURE_POLICY", "reject"),
+		PickupTokenTTL:            time.Duration(getEnvIntOrDefault("PICKUP_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+		NoShowGrace:               time.Duration(getEnvIntOrDefault("NO_SHOW_GRACE_MINUTES", 60)) * time.Minute,
+		IdempotencyStore:          getEnvOrDefault("IDEMPOTENCY_STORE", "postgres"),
+		IdempotencyTTL:            time.Duration(getEnvIntOrDefault("IDEMPOTENCY_TTL_HOURS", 24)) * time.Hour,
+	}
+
+	// The organization time zone is the fallback for users and addresses without one
+	if _, err := time.LoadLocation(cfg.TimeZone); err != nil {
+		return nil, fmt.Errorf("invalid TIMEZONE %q: %w", cfg.TimeZone, err)
+	}
+
+	// Events on closed dates are either rejected or created with a warning
+	if cfg.ClosurePolicy != "reject" && cfg.ClosurePolicy != "flag" {
+		return nil, fmt.Errorf("invalid CLOSURE_POLICY %q: use reject or flag", cfg.ClosurePolicy)
+	}
+
+	// Pickup tokens are verified offline by kiosks, so the signing key must survive restarts.
+	// Without PICKUP_SIGNING_KEY it is derived from the JWT secret.
+	if value := os.Getenv("PICKUP_SIGNING_KEY"); value != "" {
+		seed, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid PICKUP_SIGNING_KEY: use a base64 encoded %d byte seed", ed25519.SeedSize)
+		}
+		cfg.PickupSigningKey = seed
+	} else {
+		seed := sha256.Sum256([]byte("mealsync-pickup:" + cfg.JWTSecret))
+		cfg.PickupSigningKey = seed[:]
+	}
+	if cfg.PickupTokenTTL <= 0 {
+		return nil, fmt.Errorf("invalid PICKUP_TOKEN_TTL_MINUTES: must be positive")
+	}
+
+	// The in-memory store only suits a single instance; replays are lost on restart
+	if cfg.IdempotencyStore != "postgres" && cfg.IdempotencyStore != "memory" {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_STORE %q: use postgres or memory", cfg.IdempotencyStore)
+	}
+	if cfg.IdempotencyTTL <= 0 {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL_HOURS: must be positive")
+	}
+
+	return cfg, nil
 }
 
 // getEnvOrDefault returns environment variable value or default if not set
@@ -36,3 +110,24 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvIntOrDefault returns environment variable value as int or default if not set or invalid
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvWeekdayOrDefault returns environment variable value as weekday or default if not set or invalid
+func getEnvWeekdayOrDefault(key string, defaultValue time.Weekday) time.Weekday {
+	value := strings.ToLower(os.Getenv(key))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == value {
+			return day
+		}
+	}
+	return defaultValue
+}
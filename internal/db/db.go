@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"gorm.io/driver/postgres"
@@ -22,7 +23,8 @@ func InitDB() error {
 	dbname := os.Getenv("DB_NAME")
 
 	// Create DSN string
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	// Sessions run in UTC so instants are stored and read back unambiguously
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=UTC",
 		host, port, user, password, dbname)
 
 	// Configure GORM logger
@@ -31,6 +33,9 @@ func InitDB() error {
 	// Open database connection
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: newLogger,
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
@@ -43,11 +48,36 @@ func InitDB() error {
 		&model.MenuSet{},
 		&model.MenuSetItem{},
 		&model.MealEvent{},
+		&model.MealEventTransition{},
+		&model.MealEventRevision{},
+		&model.MealEventSeries{},
+		&model.MealEventSeriesSet{},
+		&model.MealEventSeriesAddress{},
+		&model.MealEventTemplate{},
+		&model.MealEventTemplateSet{},
+		&model.MealEventTemplateAddress{},
+		&model.MealTypeDefault{},
+		&model.MealTypeDefaultSet{},
+		&model.MealTypeDefaultAddress{},
+		&model.Holiday{},
 		&model.MealEventAddress{},
 		&model.MealRequest{},
 		&model.MealRequestItem{},
+		&model.MealRequestTransition{},
+		&model.MealPickup{},
+		&model.NoShowPolicy{},
+		&model.NoShowPenalty{},
+		&model.MealTransfer{},
 		&model.MenuItemComment{},
 		&model.Notification{},
+		&model.CalendarFeed{},
+		&model.StandingOrder{},
+		&model.StandingOrderItem{},
+		&model.StandingOrderPause{},
+		&model.GuestRequest{},
+		&model.GuestRequestItem{},
+		&model.DepartmentGuestQuota{},
+		&model.IdempotencyKey{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_notifications_user_delivered;
+
+DELETE FROM notifications WHERE type = 'digest';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+  CHECK (type IN ('reminder', 'confirmation', 'admin-message', 'event-info'));
+
+DROP INDEX IF EXISTS idx_users_digest_frequency;
+ALTER TABLE users DROP COLUMN IF EXISTS last_digest_at;
+ALTER TABLE users DROP COLUMN IF EXISTS digest_frequency;
+ALTER TABLE users DROP COLUMN IF EXISTS notification_enabled;
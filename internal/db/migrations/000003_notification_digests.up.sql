@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notification_enabled BOOLEAN DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN digest_frequency VARCHAR(10) NOT NULL DEFAULT 'none'
+  CHECK (digest_frequency IN ('none', 'daily', 'weekly'));
+ALTER TABLE users ADD COLUMN last_digest_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX idx_users_digest_frequency ON users(digest_frequency);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+  CHECK (type IN ('reminder', 'confirmation', 'admin-message', 'event-info', 'digest'));
+
+CREATE INDEX idx_notifications_user_delivered ON notifications(user_id, delivered);
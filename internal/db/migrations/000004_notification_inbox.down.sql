@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_read_created;
+DROP INDEX IF EXISTS idx_notifications_inbox;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS archived;
@@ -0,0 +1,5 @@
+ALTER TABLE notifications ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE notifications ADD COLUMN archived_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX idx_notifications_inbox ON notifications(user_id, archived, id DESC);
+CREATE INDEX idx_notifications_read_created ON notifications(created_at) WHERE read = TRUE;
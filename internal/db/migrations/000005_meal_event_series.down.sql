@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS holidays;
+
+DROP INDEX IF EXISTS idx_unique_series_occurrence;
+DROP INDEX IF EXISTS idx_meal_events_series_id;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS is_exception;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS series_occurrence;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS meal_event_series_addresses;
+DROP TABLE IF EXISTS meal_event_series_sets;
+DROP TABLE IF EXISTS meal_event_series;
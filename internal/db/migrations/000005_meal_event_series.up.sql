@@ -0,0 +1,66 @@
+CREATE TABLE meal_event_series (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  description TEXT,
+  frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekdays', 'weekly')),
+  weekdays VARCHAR(100),
+  start_date TIMESTAMP NOT NULL,
+  start_time VARCHAR(5) NOT NULL,
+  until TIMESTAMP DEFAULT NULL,
+  count INT DEFAULT NULL CHECK (count > 0),
+  event_duration INT NOT NULL, -- in minutes
+  cutoff_offset INT NOT NULL, -- in minutes before the event starts
+  is_active BOOLEAN DEFAULT TRUE,
+  generated_until TIMESTAMP DEFAULT NULL,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE INDEX idx_meal_event_series_deleted_at ON meal_event_series(deleted_at);
+
+CREATE TABLE meal_event_series_sets (
+  series_id INT REFERENCES meal_event_series(id) ON DELETE CASCADE,
+  menu_set_id INT REFERENCES menu_sets(id) ON DELETE CASCADE,
+  PRIMARY KEY (series_id, menu_set_id),
+  label TEXT,
+  note TEXT,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE TABLE meal_event_series_addresses (
+  series_id INT REFERENCES meal_event_series(id) ON DELETE CASCADE,
+  address_id INT REFERENCES event_addresses(id) ON DELETE CASCADE,
+  PRIMARY KEY (series_id, address_id),
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+ALTER TABLE meal_events ADD COLUMN series_id INT REFERENCES meal_event_series(id) ON DELETE SET NULL;
+ALTER TABLE meal_events ADD COLUMN series_occurrence TIMESTAMP DEFAULT NULL;
+ALTER TABLE meal_events ADD COLUMN is_exception BOOLEAN DEFAULT FALSE;
+
+CREATE INDEX idx_meal_events_series_id ON meal_events(series_id);
+CREATE UNIQUE INDEX idx_unique_series_occurrence ON meal_events(series_id, series_occurrence)
+  WHERE series_id IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE holidays (
+  id SERIAL PRIMARY KEY,
+  date DATE NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE INDEX idx_holidays_deleted_at ON holidays(deleted_at);
+CREATE UNIQUE INDEX idx_unique_holiday_date ON holidays(date) WHERE deleted_at IS NULL;
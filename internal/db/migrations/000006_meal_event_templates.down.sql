@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS meal_event_template_addresses;
+DROP TABLE IF EXISTS meal_event_template_sets;
+DROP TABLE IF EXISTS meal_event_templates;
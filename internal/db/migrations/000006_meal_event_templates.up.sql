@@ -0,0 +1,40 @@
+CREATE TABLE meal_event_templates (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  event_name VARCHAR(100) NOT NULL,
+  description TEXT,
+  start_time VARCHAR(5) NOT NULL,
+  event_duration INT NOT NULL, -- in minutes
+  cutoff_offset INT NOT NULL, -- in minutes before the event starts
+  is_active BOOLEAN DEFAULT TRUE,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE INDEX idx_meal_event_templates_deleted_at ON meal_event_templates(deleted_at);
+CREATE UNIQUE INDEX idx_unique_meal_event_template_name ON meal_event_templates(name) WHERE deleted_at IS NULL;
+
+CREATE TABLE meal_event_template_sets (
+  template_id INT REFERENCES meal_event_templates(id) ON DELETE CASCADE,
+  menu_set_id INT REFERENCES menu_sets(id) ON DELETE CASCADE,
+  PRIMARY KEY (template_id, menu_set_id),
+  label TEXT,
+  note TEXT,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE TABLE meal_event_template_addresses (
+  template_id INT REFERENCES meal_event_templates(id) ON DELETE CASCADE,
+  address_id INT REFERENCES event_addresses(id) ON DELETE CASCADE,
+  PRIMARY KEY (template_id, address_id),
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
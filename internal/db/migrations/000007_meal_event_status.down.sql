@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS meal_event_transitions;
+DROP INDEX IF EXISTS idx_meal_events_status;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS status;
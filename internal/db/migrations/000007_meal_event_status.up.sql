@@ -0,0 +1,27 @@
+ALTER TABLE meal_events ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft'
+  CHECK (status IN ('draft', 'published', 'closed', 'confirmed', 'completed', 'cancelled'));
+
+-- Existing events were already visible to employees
+UPDATE meal_events SET status = CASE
+  WHEN is_active = FALSE THEN 'cancelled'
+  WHEN confirmed_at IS NOT NULL THEN 'confirmed'
+  WHEN cutoff_time <= NOW() THEN 'closed'
+  ELSE 'published'
+END;
+
+CREATE INDEX idx_meal_events_status ON meal_events(status);
+
+CREATE TABLE meal_event_transitions (
+  id SERIAL PRIMARY KEY,
+  meal_event_id INT NOT NULL REFERENCES meal_events(id) ON DELETE CASCADE,
+  from_status VARCHAR(20) NOT NULL,
+  to_status VARCHAR(20) NOT NULL,
+  reason TEXT,
+  actor_id INT REFERENCES users(id), -- NULL for system transitions
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_meal_event_transitions_meal_event_id ON meal_event_transitions(meal_event_id);
+CREATE INDEX idx_meal_event_transitions_deleted_at ON meal_event_transitions(deleted_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS meal_type_default_addresses;
+DROP TABLE IF EXISTS meal_type_default_sets;
+DROP TABLE IF EXISTS meal_type_defaults;
+DROP INDEX IF EXISTS idx_meal_events_meal_type;
+ALTER TABLE meal_event_templates DROP COLUMN IF EXISTS meal_type;
+ALTER TABLE meal_event_series DROP COLUMN IF EXISTS meal_type;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS meal_type;
@@ -0,0 +1,46 @@
+ALTER TABLE meal_events ADD COLUMN meal_type VARCHAR(20) NOT NULL DEFAULT 'lunch'
+  CHECK (meal_type IN ('breakfast', 'lunch', 'snacks'));
+ALTER TABLE meal_event_series ADD COLUMN meal_type VARCHAR(20) NOT NULL DEFAULT 'lunch'
+  CHECK (meal_type IN ('breakfast', 'lunch', 'snacks'));
+ALTER TABLE meal_event_templates ADD COLUMN meal_type VARCHAR(20) NOT NULL DEFAULT 'lunch'
+  CHECK (meal_type IN ('breakfast', 'lunch', 'snacks'));
+
+CREATE INDEX idx_meal_events_meal_type ON meal_events(meal_type);
+
+CREATE TABLE meal_type_defaults (
+  id SERIAL PRIMARY KEY,
+  meal_type VARCHAR(20) NOT NULL CHECK (meal_type IN ('breakfast', 'lunch', 'snacks')),
+  start_time VARCHAR(5) NOT NULL,
+  event_duration INT NOT NULL, -- in minutes
+  cutoff_offset INT NOT NULL, -- in minutes before the event starts
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE INDEX idx_meal_type_defaults_deleted_at ON meal_type_defaults(deleted_at);
+CREATE UNIQUE INDEX idx_unique_meal_type_default ON meal_type_defaults(meal_type);
+
+CREATE TABLE meal_type_default_sets (
+  default_id INT REFERENCES meal_type_defaults(id) ON DELETE CASCADE,
+  menu_set_id INT REFERENCES menu_sets(id) ON DELETE CASCADE,
+  PRIMARY KEY (default_id, menu_set_id),
+  label TEXT,
+  note TEXT,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
+
+CREATE TABLE meal_type_default_addresses (
+  default_id INT REFERENCES meal_type_defaults(id) ON DELETE CASCADE,
+  address_id INT REFERENCES event_addresses(id) ON DELETE CASCADE,
+  PRIMARY KEY (default_id, address_id),
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id)
+);
@@ -0,0 +1,16 @@
+ALTER TABLE users DROP COLUMN IF EXISTS time_zone;
+ALTER TABLE event_addresses DROP COLUMN IF EXISTS time_zone;
+
+ALTER TABLE meal_event_series
+  ALTER COLUMN start_date TYPE TIMESTAMP USING start_date AT TIME ZONE 'UTC',
+  ALTER COLUMN until TYPE TIMESTAMP USING until AT TIME ZONE 'UTC',
+  ALTER COLUMN generated_until TYPE TIMESTAMP USING generated_until AT TIME ZONE 'UTC';
+
+ALTER TABLE meal_requests
+  ALTER COLUMN confirmed_at TYPE TIMESTAMP USING confirmed_at AT TIME ZONE 'UTC';
+
+ALTER TABLE meal_events
+  ALTER COLUMN event_date TYPE TIMESTAMP USING event_date AT TIME ZONE 'UTC',
+  ALTER COLUMN cutoff_time TYPE TIMESTAMP USING cutoff_time AT TIME ZONE 'UTC',
+  ALTER COLUMN confirmed_at TYPE TIMESTAMP USING confirmed_at AT TIME ZONE 'UTC',
+  ALTER COLUMN series_occurrence TYPE TIMESTAMP USING series_occurrence AT TIME ZONE 'UTC';
@@ -0,0 +1,18 @@
+-- Scheduling instants are stored as absolute times; existing values were written in UTC
+ALTER TABLE meal_events
+  ALTER COLUMN event_date TYPE TIMESTAMPTZ USING event_date AT TIME ZONE 'UTC',
+  ALTER COLUMN cutoff_time TYPE TIMESTAMPTZ USING cutoff_time AT TIME ZONE 'UTC',
+  ALTER COLUMN confirmed_at TYPE TIMESTAMPTZ USING confirmed_at AT TIME ZONE 'UTC',
+  ALTER COLUMN series_occurrence TYPE TIMESTAMPTZ USING series_occurrence AT TIME ZONE 'UTC';
+
+ALTER TABLE meal_requests
+  ALTER COLUMN confirmed_at TYPE TIMESTAMPTZ USING confirmed_at AT TIME ZONE 'UTC';
+
+-- start_date and until stay calendar dates, kept as UTC midnight
+ALTER TABLE meal_event_series
+  ALTER COLUMN start_date TYPE TIMESTAMPTZ USING date_trunc('day', start_date) AT TIME ZONE 'UTC',
+  ALTER COLUMN until TYPE TIMESTAMPTZ USING date_trunc('day', until) AT TIME ZONE 'UTC',
+  ALTER COLUMN generated_until TYPE TIMESTAMPTZ USING generated_until AT TIME ZONE 'UTC';
+
+ALTER TABLE event_addresses ADD COLUMN time_zone VARCHAR(64) DEFAULT NULL;
+ALTER TABLE users ADD COLUMN time_zone VARCHAR(64) DEFAULT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS calendar_feeds;
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS sequence;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,18 @@
+ALTER TABLE meal_events ADD COLUMN sequence INT NOT NULL DEFAULT 0;
+ALTER TABLE meal_requests ADD COLUMN sequence INT NOT NULL DEFAULT 0;
+
+CREATE TABLE calendar_feeds (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('events', 'personal')),
+  token VARCHAR(64) NOT NULL,
+  revoked_at TIMESTAMPTZ DEFAULT NULL,
+  last_accessed_at TIMESTAMPTZ DEFAULT NULL,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_calendar_feeds_token ON calendar_feeds(token);
+CREATE INDEX idx_calendar_feeds_user_id ON calendar_feeds(user_id);
+CREATE INDEX idx_calendar_feeds_deleted_at ON calendar_feeds(deleted_at);
@@ -0,0 +1,8 @@
+DELETE FROM holidays WHERE event_address_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_unique_holiday_date;
+CREATE UNIQUE INDEX idx_unique_holiday_date ON holidays(date) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_holidays_event_address_id;
+ALTER TABLE holidays DROP COLUMN IF EXISTS import_uid;
+ALTER TABLE holidays DROP COLUMN IF EXISTS event_address_id;
@@ -0,0 +1,8 @@
+ALTER TABLE holidays ADD COLUMN event_address_id INT DEFAULT NULL REFERENCES event_addresses(id) ON DELETE CASCADE;
+ALTER TABLE holidays ADD COLUMN import_uid TEXT DEFAULT NULL;
+
+CREATE INDEX idx_holidays_event_address_id ON holidays(event_address_id);
+
+-- A date can be closed once for the whole organization and once per address
+DROP INDEX IF EXISTS idx_unique_holiday_date;
+CREATE UNIQUE INDEX idx_unique_holiday_date ON holidays(date, COALESCE(event_address_id, 0)) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+DELETE FROM notifications WHERE type = 'event-change';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+  CHECK (type IN ('reminder', 'confirmation', 'admin-message', 'event-info', 'digest'));
+
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS attention_note;
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS needs_attention;
+
+DROP TABLE IF EXISTS meal_event_revisions;
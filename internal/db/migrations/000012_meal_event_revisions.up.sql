@@ -0,0 +1,22 @@
+CREATE TABLE meal_event_revisions (
+  id SERIAL PRIMARY KEY,
+  meal_event_id INT NOT NULL REFERENCES meal_events(id) ON DELETE CASCADE,
+  sequence INT NOT NULL,
+  changes JSONB NOT NULL DEFAULT '[]',
+  material BOOLEAN NOT NULL DEFAULT FALSE,
+  actor_id INT REFERENCES users(id),
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_meal_event_revisions_meal_event_id ON meal_event_revisions(meal_event_id);
+CREATE INDEX idx_meal_event_revisions_deleted_at ON meal_event_revisions(deleted_at);
+
+-- Requests whose menu set or address was removed from the event
+ALTER TABLE meal_requests ADD COLUMN needs_attention BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meal_requests ADD COLUMN attention_note TEXT DEFAULT NULL;
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+  CHECK (type IN ('reminder', 'confirmation', 'admin-message', 'event-info', 'event-change', 'digest'));
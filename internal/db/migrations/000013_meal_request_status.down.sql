@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS meal_request_transitions;
+
+-- Cancelled and rejected requests were deleted before statuses existed
+DELETE FROM meal_requests WHERE status IN ('cancelled', 'rejected');
+
+DROP INDEX IF EXISTS idx_unique_meal_request;
+CREATE UNIQUE INDEX idx_unique_meal_request ON meal_requests(user_id, meal_event_id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_meal_requests_status;
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS status;
+
+ALTER TABLE meal_requests DROP CONSTRAINT IF EXISTS meal_requests_pkey;
+ALTER TABLE meal_requests ADD PRIMARY KEY (user_id, meal_event_id);
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS id;
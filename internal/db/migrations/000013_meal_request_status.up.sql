@@ -0,0 +1,37 @@
+-- Requests are addressed by ID, so a cancelled request no longer blocks a new one for the same event
+ALTER TABLE meal_requests ADD COLUMN IF NOT EXISTS id SERIAL;
+ALTER TABLE meal_requests DROP CONSTRAINT IF EXISTS meal_requests_pkey;
+ALTER TABLE meal_requests ADD PRIMARY KEY (id);
+
+ALTER TABLE meal_requests ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending'
+  CHECK (status IN ('pending', 'approved', 'rejected', 'completed', 'cancelled'));
+
+-- Requests of confirmed events were already confirmed to the requesters
+UPDATE meal_requests SET status = CASE
+  WHEN meal_events.status = 'completed' THEN 'completed'
+  WHEN meal_events.status = 'confirmed' OR meal_requests.confirmed_at IS NOT NULL THEN 'approved'
+  ELSE 'pending'
+END
+FROM meal_events
+WHERE meal_events.id = meal_requests.meal_event_id;
+
+CREATE INDEX idx_meal_requests_status ON meal_requests(status);
+
+DROP INDEX IF EXISTS idx_unique_meal_request;
+CREATE UNIQUE INDEX idx_unique_meal_request ON meal_requests(user_id, meal_event_id)
+  WHERE deleted_at IS NULL AND status IN ('pending', 'approved', 'completed');
+
+CREATE TABLE meal_request_transitions (
+  id SERIAL PRIMARY KEY,
+  meal_request_id INT NOT NULL REFERENCES meal_requests(id) ON DELETE CASCADE,
+  from_status VARCHAR(20) NOT NULL,
+  to_status VARCHAR(20) NOT NULL,
+  reason TEXT,
+  actor_id INT REFERENCES users(id), -- NULL for system transitions
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_meal_request_transitions_meal_request_id ON meal_request_transitions(meal_request_id);
+CREATE INDEX idx_meal_request_transitions_deleted_at ON meal_request_transitions(deleted_at);
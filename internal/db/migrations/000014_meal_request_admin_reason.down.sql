@@ -0,0 +1 @@
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS admin_reason;
@@ -0,0 +1,2 @@
+-- Why an admin last assigned or changed a meal request on behalf of its employee
+ALTER TABLE meal_requests ADD COLUMN admin_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS standing_order_id;
+
+DROP TABLE IF EXISTS standing_order_pauses;
+DROP TABLE IF EXISTS standing_order_items;
+DROP TABLE IF EXISTS standing_orders;
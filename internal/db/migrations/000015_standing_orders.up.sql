@@ -0,0 +1,48 @@
+CREATE TABLE standing_orders (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  meal_type VARCHAR(20) NOT NULL CHECK (meal_type IN ('breakfast', 'lunch', 'snacks')),
+  weekdays TEXT,
+  event_address_id INT NOT NULL REFERENCES event_addresses(id) ON DELETE CASCADE,
+  menu_set_id INT REFERENCES menu_sets(id) ON DELETE SET NULL,
+  fallback VARCHAR(20) NOT NULL DEFAULT 'skip' CHECK (fallback IN ('skip', 'any_set')),
+  is_active BOOLEAN DEFAULT TRUE,
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id),
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_standing_orders_user_id ON standing_orders(user_id);
+CREATE INDEX idx_standing_orders_meal_type ON standing_orders(meal_type) WHERE is_active;
+CREATE INDEX idx_standing_orders_deleted_at ON standing_orders(deleted_at);
+
+-- Deselected items and item notes copied into every request of a standing order
+CREATE TABLE standing_order_items (
+  standing_order_id INT REFERENCES standing_orders(id) ON DELETE CASCADE,
+  menu_item_id INT REFERENCES menu_items(id) ON DELETE CASCADE,
+  PRIMARY KEY (standing_order_id, menu_item_id),
+  is_selected BOOLEAN NOT NULL DEFAULT TRUE,
+  notes TEXT,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE standing_order_pauses (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  start_date DATE NOT NULL,
+  end_date DATE NOT NULL CHECK (end_date >= start_date),
+  reason TEXT,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_standing_order_pauses_user_id ON standing_order_pauses(user_id);
+CREATE INDEX idx_standing_order_pauses_dates ON standing_order_pauses(start_date, end_date);
+CREATE INDEX idx_standing_order_pauses_deleted_at ON standing_order_pauses(deleted_at);
+
+-- Requests created by a standing order remember it
+ALTER TABLE meal_requests ADD COLUMN standing_order_id INT REFERENCES standing_orders(id) ON DELETE SET NULL;
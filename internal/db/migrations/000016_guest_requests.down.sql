@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS department_guest_quotas;
+DROP TABLE IF EXISTS guest_request_items;
+DROP TABLE IF EXISTS guest_requests;
+
+ALTER TABLE meal_event_addresses DROP COLUMN IF EXISTS capacity;
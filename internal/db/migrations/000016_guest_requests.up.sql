@@ -0,0 +1,52 @@
+-- Seats per location of a meal event, shared by employees and guests; 0 is unlimited
+ALTER TABLE meal_event_addresses ADD COLUMN capacity INT NOT NULL DEFAULT 0 CHECK (capacity >= 0);
+
+CREATE TABLE guest_requests (
+  id SERIAL PRIMARY KEY,
+  meal_event_id INT NOT NULL REFERENCES meal_events(id) ON DELETE CASCADE,
+  host_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  guest_name VARCHAR(100) NOT NULL,
+  organization VARCHAR(100),
+  menu_set_id INT NOT NULL REFERENCES menu_sets(id),
+  event_address_id INT NOT NULL REFERENCES event_addresses(id),
+  dietary_notes TEXT,
+  status VARCHAR(20) NOT NULL DEFAULT 'approved'
+    CHECK (status IN ('awaiting_approval', 'approved', 'rejected', 'cancelled')),
+  reviewed_by INT REFERENCES users(id),
+  review_note TEXT,
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id),
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_guest_requests_meal_event_id ON guest_requests(meal_event_id);
+CREATE INDEX idx_guest_requests_host_user_id ON guest_requests(host_user_id);
+CREATE INDEX idx_guest_requests_status ON guest_requests(status);
+CREATE INDEX idx_guest_requests_deleted_at ON guest_requests(deleted_at);
+
+CREATE TABLE guest_request_items (
+  id SERIAL PRIMARY KEY,
+  guest_request_id INT NOT NULL REFERENCES guest_requests(id) ON DELETE CASCADE,
+  menu_item_id INT NOT NULL REFERENCES menu_items(id),
+  is_selected BOOLEAN NOT NULL DEFAULT TRUE,
+  quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+  notes TEXT,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_guest_request_items_guest_request_id ON guest_request_items(guest_request_id);
+
+CREATE TABLE department_guest_quotas (
+  id SERIAL PRIMARY KEY,
+  department VARCHAR(100) NOT NULL UNIQUE,
+  monthly_limit INT NOT NULL CHECK (monthly_limit >= 0),
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id),
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_manager_id;
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
+
+UPDATE users SET role = 'employee' WHERE role = 'manager';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'user', 'employee'));
@@ -0,0 +1,8 @@
+-- Managers are a role of their own now
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'user', 'employee', 'manager'));
+
+-- Reporting lines: each employee has at most one line manager
+ALTER TABLE users ADD COLUMN manager_id INT REFERENCES users(id) ON DELETE SET NULL CHECK (manager_id <> id);
+
+CREATE INDEX idx_users_manager_id ON users(manager_id);
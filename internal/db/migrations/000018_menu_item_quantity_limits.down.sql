@@ -0,0 +1,27 @@
+CREATE OR REPLACE FUNCTION validate_requested_item()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM meal_requests mr
+        JOIN menu_set_items msi ON mr.menu_set_id = msi.menu_set_id
+        WHERE mr.user_id = NEW.user_id
+          AND mr.meal_event_id = NEW.meal_event_id
+          AND msi.menu_item_id = NEW.menu_item_id
+    ) THEN
+        RAISE EXCEPTION 'Invalid request: Menu item % not in selected menu set for user % in event %',
+            NEW.menu_item_id, NEW.user_id, NEW.meal_event_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_validate_requested_item
+BEFORE INSERT OR UPDATE ON user_requested_items
+FOR EACH ROW
+EXECUTE FUNCTION validate_requested_item();
+
+ALTER TABLE menu_items DROP CONSTRAINT IF EXISTS menu_items_quantity_range_check;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS max_quantity;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS min_quantity;
@@ -0,0 +1,10 @@
+-- Servings one meal request may ask for; a max_quantity of 0 is unlimited
+ALTER TABLE menu_items ADD COLUMN min_quantity INT NOT NULL DEFAULT 1 CHECK (min_quantity >= 1);
+ALTER TABLE menu_items ADD COLUMN max_quantity INT NOT NULL DEFAULT 0 CHECK (max_quantity >= 0);
+ALTER TABLE menu_items ADD CONSTRAINT menu_items_quantity_range_check
+  CHECK (max_quantity = 0 OR max_quantity >= min_quantity);
+
+-- Request items are validated by the service layer. This trigger guarded user_requested_items,
+-- a table the application never writes; request items live in meal_request_items.
+DROP TRIGGER IF EXISTS trg_validate_requested_item ON user_requested_items;
+DROP FUNCTION IF EXISTS validate_requested_item();
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS meal_pickups;
+
+-- Before pickups were tracked, approved meals were completed with their meal event
+UPDATE meal_requests SET status = CASE
+  WHEN meal_events.status = 'completed' THEN 'completed'
+  ELSE 'approved'
+END
+FROM meal_events
+WHERE meal_events.id = meal_requests.meal_event_id AND meal_requests.status = 'no_show';
+
+DROP INDEX IF EXISTS idx_unique_meal_request;
+CREATE UNIQUE INDEX idx_unique_meal_request ON meal_requests(user_id, meal_event_id)
+  WHERE deleted_at IS NULL AND status IN ('pending', 'approved', 'completed');
+
+ALTER TABLE meal_requests DROP CONSTRAINT IF EXISTS meal_requests_status_check;
+ALTER TABLE meal_requests ADD CONSTRAINT meal_requests_status_check
+  CHECK (status IN ('pending', 'approved', 'rejected', 'completed', 'cancelled'));
@@ -0,0 +1,28 @@
+-- Approved meals that were not picked up become no-shows
+ALTER TABLE meal_requests DROP CONSTRAINT IF EXISTS meal_requests_status_check;
+ALTER TABLE meal_requests ADD CONSTRAINT meal_requests_status_check
+  CHECK (status IN ('pending', 'approved', 'rejected', 'completed', 'cancelled', 'no_show'));
+
+DROP INDEX IF EXISTS idx_unique_meal_request;
+CREATE UNIQUE INDEX idx_unique_meal_request ON meal_requests(user_id, meal_event_id)
+  WHERE deleted_at IS NULL AND status IN ('pending', 'approved', 'completed', 'no_show');
+
+CREATE TABLE meal_pickups (
+  id SERIAL PRIMARY KEY,
+  meal_request_id INT NOT NULL REFERENCES meal_requests(id) ON DELETE CASCADE,
+  meal_event_id INT NOT NULL REFERENCES meal_events(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  address_id INT NOT NULL REFERENCES event_addresses(id),
+  picked_up_at TIMESTAMP NOT NULL,
+  wrong_location BOOLEAN NOT NULL DEFAULT FALSE,
+  scanned_by INT NOT NULL REFERENCES users(id),
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- A second scan of the same meal is rejected by the database as well
+CREATE UNIQUE INDEX idx_meal_pickups_meal_request_id ON meal_pickups(meal_request_id);
+CREATE INDEX idx_meal_pickups_meal_event_id ON meal_pickups(meal_event_id);
+CREATE INDEX idx_meal_pickups_user_id ON meal_pickups(user_id);
+CREATE INDEX idx_meal_pickups_deleted_at ON meal_pickups(deleted_at);
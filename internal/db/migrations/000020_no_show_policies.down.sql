@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS no_show_penalties;
+DROP TABLE IF EXISTS no_show_policies;
+
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS no_show_excused;
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS awaiting_approval;
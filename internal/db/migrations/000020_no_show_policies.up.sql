@@ -0,0 +1,41 @@
+-- Requests of users under a require_approval penalty wait for their line manager
+ALTER TABLE meal_requests ADD COLUMN awaiting_approval BOOLEAN NOT NULL DEFAULT FALSE;
+-- Excused no-shows do not count towards no-show policies
+ALTER TABLE meal_requests ADD COLUMN no_show_excused BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE no_show_policies (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  threshold INT NOT NULL CHECK (threshold > 0),
+  window_days INT NOT NULL CHECK (window_days > 0),
+  action VARCHAR(30) NOT NULL CHECK (action IN ('warn', 'limit_standing_orders', 'require_approval')),
+  duration_days INT NOT NULL DEFAULT 0 CHECK (duration_days >= 0),
+  is_active BOOLEAN DEFAULT TRUE,
+  created_by INT REFERENCES users(id),
+  updated_by INT REFERENCES users(id),
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_no_show_policies_deleted_at ON no_show_policies(deleted_at);
+
+CREATE TABLE no_show_penalties (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  policy_id INT NOT NULL REFERENCES no_show_policies(id) ON DELETE CASCADE,
+  action VARCHAR(30) NOT NULL,
+  no_shows INT NOT NULL,
+  starts_at TIMESTAMP NOT NULL,
+  ends_at TIMESTAMP,
+  lifted_at TIMESTAMP,
+  lifted_by INT REFERENCES users(id),
+  lift_reason TEXT,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_no_show_penalties_user_id ON no_show_penalties(user_id);
+CREATE INDEX idx_no_show_penalties_policy_id ON no_show_penalties(policy_id);
+CREATE INDEX idx_no_show_penalties_deleted_at ON no_show_penalties(deleted_at);
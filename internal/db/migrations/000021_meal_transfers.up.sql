@@ -0,0 +1,26 @@
+CREATE TABLE meal_transfers (
+  id SERIAL PRIMARY KEY,
+  meal_request_id INT NOT NULL REFERENCES meal_requests(id) ON DELETE CASCADE,
+  meal_event_id INT NOT NULL REFERENCES meal_events(id) ON DELETE CASCADE,
+  from_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  to_user_id INT REFERENCES users(id) ON DELETE CASCADE, -- NULL while offered to the surplus pool
+  pool BOOLEAN NOT NULL DEFAULT FALSE,
+  status VARCHAR(20) NOT NULL DEFAULT 'offered'
+    CHECK (status IN ('offered', 'accepted', 'declined', 'withdrawn', 'claimed')),
+  note TEXT,
+  responded_at TIMESTAMP,
+  deleted_at TIMESTAMP DEFAULT NULL,
+  created_at TIMESTAMP DEFAULT NOW(),
+  updated_at TIMESTAMP DEFAULT NOW(),
+  CHECK (pool OR to_user_id IS NOT NULL)
+);
+
+CREATE INDEX idx_meal_transfers_meal_request_id ON meal_transfers(meal_request_id);
+CREATE INDEX idx_meal_transfers_meal_event_id ON meal_transfers(meal_event_id);
+CREATE INDEX idx_meal_transfers_from_user_id ON meal_transfers(from_user_id);
+CREATE INDEX idx_meal_transfers_to_user_id ON meal_transfers(to_user_id);
+CREATE INDEX idx_meal_transfers_deleted_at ON meal_transfers(deleted_at);
+
+-- A meal is offered once at a time
+CREATE UNIQUE INDEX idx_meal_transfers_open_offer ON meal_transfers(meal_request_id)
+  WHERE status = 'offered' AND deleted_at IS NULL;
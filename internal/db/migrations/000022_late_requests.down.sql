@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_meal_requests_late;
+
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS late_reason;
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS is_late;
+
+ALTER TABLE meal_event_sets DROP COLUMN IF EXISTS surplus;
+
+ALTER TABLE meal_events DROP COLUMN IF EXISTS late_request_cap;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS allow_late_requests;
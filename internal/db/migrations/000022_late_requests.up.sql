@@ -0,0 +1,11 @@
+-- Meal events may accept requests after the cutoff, up to a cap of approved late requests
+ALTER TABLE meal_events ADD COLUMN allow_late_requests BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meal_events ADD COLUMN late_request_cap INT NOT NULL DEFAULT 0 CHECK (late_request_cap >= 0);
+
+-- Spare portions per menu set that late requests are served from
+ALTER TABLE meal_event_sets ADD COLUMN surplus INT NOT NULL DEFAULT 0 CHECK (surplus >= 0);
+
+ALTER TABLE meal_requests ADD COLUMN is_late BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meal_requests ADD COLUMN late_reason TEXT;
+
+CREATE INDEX idx_meal_requests_late ON meal_requests (meal_event_id) WHERE is_late;
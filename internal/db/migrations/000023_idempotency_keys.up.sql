@@ -0,0 +1,14 @@
+-- Responses to mutating requests sent with an Idempotency-Key header, replayed on retries
+CREATE TABLE idempotency_keys (
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  key VARCHAR(255) NOT NULL,
+  fingerprint TEXT NOT NULL,
+  status_code INT NOT NULL DEFAULT 0,
+  content_type TEXT,
+  body BYTEA,
+  created_at TIMESTAMP DEFAULT NOW(),
+  expires_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
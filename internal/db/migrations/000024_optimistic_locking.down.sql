@@ -0,0 +1,3 @@
+ALTER TABLE menu_items DROP COLUMN IF EXISTS version;
+ALTER TABLE menu_sets DROP COLUMN IF EXISTS version;
+ALTER TABLE meal_events DROP COLUMN IF EXISTS version;
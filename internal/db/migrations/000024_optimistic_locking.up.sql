@@ -0,0 +1,4 @@
+-- Versions for optimistic locking; every change raises them and clients send them back in If-Match.
+ALTER TABLE meal_events ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE menu_sets ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE menu_items ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS dietary_tags;
+ALTER TABLE users DROP COLUMN IF EXISTS allergens;
+
+ALTER TABLE menu_items DROP COLUMN IF EXISTS dietary_tags;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS allergens;
@@ -0,0 +1,7 @@
+-- Allergens and dietary tags of menu items, stored as JSON arrays of names
+ALTER TABLE menu_items ADD COLUMN allergens JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE menu_items ADD COLUMN dietary_tags JSONB NOT NULL DEFAULT '[]';
+
+-- Allergens a user avoids and dietary tags their meals should carry, used to warn on requests
+ALTER TABLE users ADD COLUMN allergens JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE users ADD COLUMN dietary_tags JSONB NOT NULL DEFAULT '[]';
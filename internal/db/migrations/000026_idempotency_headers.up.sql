@@ -0,0 +1,2 @@
+-- Headers of stored responses, such as ETag and Location, replayed along with the body
+ALTER TABLE idempotency_keys ADD COLUMN headers JSONB NOT NULL DEFAULT '{}';
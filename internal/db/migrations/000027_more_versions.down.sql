@@ -0,0 +1,6 @@
+ALTER TABLE menu_item_comments DROP COLUMN IF EXISTS version;
+ALTER TABLE meal_event_series DROP COLUMN IF EXISTS version;
+ALTER TABLE meal_event_templates DROP COLUMN IF EXISTS version;
+ALTER TABLE standing_orders DROP COLUMN IF EXISTS version;
+ALTER TABLE guest_requests DROP COLUMN IF EXISTS version;
+ALTER TABLE meal_requests DROP COLUMN IF EXISTS version;
@@ -0,0 +1,8 @@
+-- Versions for the remaining records that clients update or delete, so that every PUT and DELETE
+-- checks If-Match instead of overwriting changes made by someone else.
+ALTER TABLE meal_requests ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE guest_requests ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE standing_orders ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE meal_event_templates ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE meal_event_series ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE menu_item_comments ADD COLUMN version INT NOT NULL DEFAULT 1;
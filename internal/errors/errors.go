@@ -28,26 +28,38 @@ const (
 	ErrorTypeInternal ErrorType = "INTERNAL_ERROR"
 	// ErrorTypeConflict represents conflict errors
 	ErrorTypeConflict ErrorType = "CONFLICT"
+	// ErrorTypePreconditionFailed represents failed If-Match preconditions
+	ErrorTypePreconditionFailed ErrorType = "PRECONDITION_FAILED"
+	// ErrorTypePreconditionRequired represents missing If-Match preconditions
+	ErrorTypePreconditionRequired ErrorType = "PRECONDITION_REQUIRED"
 )
 
 // ErrorResponse represents the error response for the Swagger documentation
 // This is used to document the error responses in the Swagger documentation
 type ErrorResponse struct {
-	Type      ErrorType `json:"type"`
-	Message   string    `json:"message"`
-	Code      int       `json:"code"`
-	Details   string    `json:"details,omitempty"`
-	RequestID string    `json:"request_id,omitempty"`
+	Type      ErrorType    `json:"type"`
+	Message   string       `json:"message"`
+	Code      int          `json:"code"`
+	Details   string       `json:"details,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// FieldError describes a validation failure of a single input field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // AppError represents an application error
 type AppError struct {
-	Type      ErrorType `json:"type"`
-	Message   string    `json:"message"`
-	Code      int       `json:"code"`
-	Details   string    `json:"details,omitempty"`
-	RequestID string    `json:"request_id,omitempty"`
-	Err       error     `json:"-"`
+	Type      ErrorType    `json:"type"`
+	Message   string       `json:"message"`
+	Code      int          `json:"code"`
+	Details   string       `json:"details,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Err       error        `json:"-"`
 }
 
 // Error implements the error interface
@@ -63,6 +75,11 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether any error in err's chain matches target
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
 // New creates a new AppError
 func New(errType ErrorType, message string, code int, err error) *AppError {
 	return &AppError{
@@ -103,6 +120,16 @@ func NewConflictError(message string, err error) *AppError {
 	return New(ErrorTypeConflict, message, http.StatusConflict, err)
 }
 
+// NewPreconditionFailedError creates a new precondition failed error
+func NewPreconditionFailedError(message string, err error) *AppError {
+	return New(ErrorTypePreconditionFailed, message, http.StatusPreconditionFailed, err)
+}
+
+// NewPreconditionRequiredError creates a new precondition required error
+func NewPreconditionRequiredError(message string, err error) *AppError {
+	return New(ErrorTypePreconditionRequired, message, http.StatusPreconditionRequired, err)
+}
+
 // WithRequestID adds a request ID to the error
 func (e *AppError) WithRequestID(requestID string) *AppError {
 	e.RequestID = requestID
@@ -114,3 +141,9 @@ func (e *AppError) WithDetails(details string) *AppError {
 	e.Details = details
 	return e
 }
+
+// WithFields adds field-level validation failures to the error
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	e.Fields = append(e.Fields, fields...)
+	return e
+}
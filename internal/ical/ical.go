@@ -0,0 +1,179 @@
+// Package ical reads and writes iCalendar (RFC 5545) documents for meal events and closures.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Method tells a calendar client what to do with the events of a calendar
+type Method string
+
+const (
+	// MethodPublish marks a subscribed feed
+	MethodPublish Method = "PUBLISH"
+	// MethodRequest adds or updates events sent by email
+	MethodRequest Method = "REQUEST"
+	// MethodCancel removes events sent by email
+	MethodCancel Method = "CANCEL"
+)
+
+// Status represents the status of an event
+type Status string
+
+const (
+	StatusTentative Status = "TENTATIVE"
+	StatusConfirmed Status = "CONFIRMED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+const (
+	// timestampLayout formats instants in UTC, so no VTIMEZONE components are needed
+	timestampLayout = "20060102T150405Z"
+	// dateLayout formats the calendar dates of all-day events
+	dateLayout = "20060102"
+)
+
+// maxLineLength is the maximum length of a content line in octets, excluding the line break
+const maxLineLength = 75
+
+// Alarm reminds the attendee at an absolute point in time
+type Alarm struct {
+	Trigger     time.Time
+	Description string
+}
+
+// Event represents a VEVENT.
+// Clients match updates to an event by UID and apply them only if Sequence is higher than before.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time // exclusive; the day after the last day of all-day events
+	AllDay      bool
+	Summary     string
+	Description string
+	Location    string
+	Status      Status
+	Organizer   string // email address
+	Attendee    string // email address
+	Updated     time.Time
+	Alarm       *Alarm
+}
+
+// Calendar represents a VCALENDAR holding events
+type Calendar struct {
+	Name   string
+	Method Method
+	Events []Event
+}
+
+// Encode renders the calendar as an iCalendar document
+func (c *Calendar) Encode() []byte {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//MealSync//Meal Events//EN")
+	w.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD:" + string(c.Method))
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	for i := range c.Events {
+		w.event(&c.Events[i])
+	}
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// writer collects folded content lines
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) event(e *Event) {
+	updated := e.Updated
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + e.UID)
+	w.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	w.line("DTSTAMP:" + formatTimestamp(updated))
+	w.line("LAST-MODIFIED:" + formatTimestamp(updated))
+	if e.AllDay {
+		w.line("DTSTART;VALUE=DATE:" + e.Start.Format(dateLayout))
+		w.line("DTEND;VALUE=DATE:" + e.End.Format(dateLayout))
+	} else {
+		w.line("DTSTART:" + formatTimestamp(e.Start))
+		w.line("DTEND:" + formatTimestamp(e.End))
+	}
+	w.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.Status != "" {
+		w.line("STATUS:" + string(e.Status))
+	}
+	if e.Organizer != "" {
+		w.line("ORGANIZER:mailto:" + e.Organizer)
+	}
+	if e.Attendee != "" {
+		w.line("ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:" + e.Attendee)
+	}
+	if e.Alarm != nil {
+		w.line("BEGIN:VALARM")
+		w.line("ACTION:DISPLAY")
+		w.line("TRIGGER;VALUE=DATE-TIME:" + formatTimestamp(e.Alarm.Trigger))
+		w.line("DESCRIPTION:" + escapeText(e.Alarm.Description))
+		w.line("END:VALARM")
+	}
+	w.line("END:VEVENT")
+}
+
+// line writes a content line, folding it after every 75 octets without splitting UTF-8 sequences
+func (w *writer) line(content string) {
+	limit := maxLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+// isRuneStart reports whether b is the first byte of a UTF-8 sequence
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// formatTimestamp formats an instant as a UTC date-time
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+// escapeText escapes a TEXT value
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
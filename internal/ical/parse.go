@@ -0,0 +1,206 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// localTimestampLayout parses floating and TZID date-times
+	localTimestampLayout = "20060102T150405"
+	// maxParsedLineLength bounds a single unfolded content line
+	maxParsedLineLength = 64 * 1024
+)
+
+// property is a parsed content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the events of an iCalendar document.
+// Recurrence rules are not expanded; each VEVENT yields a single event.
+// Floating times are read as UTC, times with an unknown TZID fail to parse.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var current *Event
+	var hasEnd bool
+	depth := 0 // nesting below VEVENT, e.g. VALARM
+	for number, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", number+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && current == nil:
+			current = &Event{}
+			hasEnd = false
+		case current == nil:
+			continue
+		case prop.name == "BEGIN":
+			depth++
+		case prop.name == "END" && depth > 0:
+			depth--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("ical: line %d: event %q has no DTSTART", number+1, current.Summary)
+			}
+			if !hasEnd {
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case depth > 0:
+			continue
+		default:
+			if err := current.set(prop); err != nil {
+				return nil, fmt.Errorf("ical: line %d: %w", number+1, err)
+			}
+			if prop.name == "DTEND" {
+				hasEnd = true
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("ical: unterminated VEVENT")
+	}
+	return events, nil
+}
+
+// set applies one property to the event
+func (e *Event) set(prop property) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SEQUENCE":
+		_, err = fmt.Sscanf(prop.value, "%d", &e.Sequence)
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "LOCATION":
+		e.Location = unescapeText(prop.value)
+	case "STATUS":
+		e.Status = Status(strings.ToUpper(prop.value))
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseDateTime(prop)
+	case "DTEND":
+		e.End, _, err = parseDateTime(prop)
+	}
+	return err
+}
+
+// unfold reads content lines, joining folded continuation lines
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxParsedLineLength)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) (property, error) {
+	colon := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, nil
+}
+
+// parseDateTime parses a DATE or DATE-TIME value. Dates are returned as midnight UTC.
+func parseDateTime(prop property) (time.Time, bool, error) {
+	value := prop.value
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.Parse(dateLayout, value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(timestampLayout, value)
+		return t, false, err
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+	t, err := time.ParseInLocation(localTimestampLayout, value, loc)
+	return t, false, err
+}
+
+// unescapeText reverses the escaping of a TEXT value
+func unescapeText(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+			} else {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		escaped = false
+		if r == 'n' || r == 'N' {
+			b.WriteRune('\n')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+)
+
+// memoryKey identifies a record in the in-memory store
+type memoryKey struct {
+	userID uint
+	key    string
+}
+
+// memoryStore keeps idempotency records in process memory
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[memoryKey]model.IdempotencyKey
+}
+
+// NewMemoryStore creates a Store that keeps records in memory. Records are lost on restart and
+// are not shared between instances, so it only suits development and single-instance setups.
+func NewMemoryStore() Store {
+	return &memoryStore{records: make(map[memoryKey]model.IdempotencyKey)}
+}
+
+// Reserve claims a key unless a live record for it exists
+func (s *memoryStore) Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := memoryKey{userID: record.UserID, key: record.Key}
+	if existing, ok := s.records[id]; ok && time.Now().Before(existing.ExpiresAt) {
+		return &existing, nil
+	}
+	record.CreatedAt = time.Now()
+	s.records[id] = *record
+	return nil, nil
+}
+
+// Complete stores the response of a reserved request
+func (s *memoryStore) Complete(ctx context.Context, record *model.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[memoryKey{userID: record.UserID, key: record.Key}] = *record
+	return nil
+}
+
+// Release forgets a reserved key
+func (s *memoryStore) Release(ctx context.Context, userID uint, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, memoryKey{userID: userID, key: key})
+	return nil
+}
+
+// DeleteExpired removes the records that expired by now
+func (s *memoryStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, id)
+		}
+	}
+	return nil
+}
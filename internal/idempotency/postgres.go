@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresStore keeps idempotency records in the idempotency_keys table
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a Store backed by the database, shared by all instances
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db}
+}
+
+// Reserve claims a key unless a live record for it exists. The primary key on user and key
+// makes sure only one of two concurrent requests claims it.
+func (s *postgresStore) Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	db := s.db.WithContext(ctx)
+
+	// An expired record no longer blocks its key
+	err := db.Where("user_id = ? AND key = ? AND expires_at <= ?", record.UserID, record.Key, time.Now()).
+		Delete(&model.IdempotencyKey{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing model.IdempotencyKey
+	if err := db.Where("user_id = ? AND key = ?", record.UserID, record.Key).First(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// Complete stores the response of a reserved request
+func (s *postgresStore) Complete(ctx context.Context, record *model.IdempotencyKey) error {
+	return s.db.WithContext(ctx).
+		Model(&model.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", record.UserID, record.Key).
+		Updates(map[string]interface{}{
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"headers":      record.Headers,
+			"body":         record.Body,
+		}).Error
+}
+
+// Release forgets a reserved key
+func (s *postgresStore) Release(ctx context.Context, userID uint, key string) error {
+	return s.db.WithContext(ctx).
+		Where("user_id = ? AND key = ?", userID, key).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+// DeleteExpired removes the records that expired by now
+func (s *postgresStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	return s.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&model.IdempotencyKey{}).Error
+}
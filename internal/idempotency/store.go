@@ -0,0 +1,23 @@
+// Package idempotency keeps the responses to mutating requests sent with an Idempotency-Key
+// header, so that retried requests are answered without being applied twice.
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+)
+
+// Store keeps idempotency records per user and key until they expire
+type Store interface {
+	// Reserve claims a key for a request in flight. If a live record for the user and key
+	// exists already, it is returned and nothing is stored.
+	Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error)
+	// Complete stores the response of a reserved request
+	Complete(ctx context.Context, record *model.IdempotencyKey) error
+	// Release forgets a reserved key so that the request can be retried
+	Release(ctx context.Context, userID uint, key string) error
+	// DeleteExpired removes the records that expired by now
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
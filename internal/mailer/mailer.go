@@ -0,0 +1,46 @@
+// Package mailer sends email messages with optional attachments.
+package mailer
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message represents a plain text email
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Mailer sends email messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// logMailer writes messages to the log instead of sending them
+type logMailer struct{}
+
+// NewLogMailer creates a Mailer that only logs messages. It is used when no SMTP server is configured.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+// Send logs the recipients, subject and attachment names of a message
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	filenames := make([]string, 0, len(msg.Attachments))
+	for _, attachment := range msg.Attachments {
+		filenames = append(filenames, attachment.Filename)
+	}
+	log.Printf("mailer: to=%s subject=%q attachments=%s", strings.Join(msg.To, ","), msg.Subject, strings.Join(filenames, ","))
+	return nil
+}
@@ -90,3 +90,23 @@ func AdminOnly() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ManagerOnly middleware restricts access to line managers and admin users
+func ManagerOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+			c.Abort()
+			return
+		}
+
+		if role != "manager" && role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Manager access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
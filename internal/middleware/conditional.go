@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter holds back the response so that it can be replaced by 304 Not Modified
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader keeps the status code until the response is sent
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow does nothing; the header is written when the response is sent
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// Write keeps the data until the response is sent
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString keeps the string until the response is sent
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Status returns the status code written so far
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of body bytes written so far
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+// Written reports whether a body was written
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// ConditionalGet answers GET requests with 304 Not Modified when their If-None-Match header names
+// the ETag of the response. Handlers may set the ETag themselves; otherwise it is derived from the
+// response body, so clients still save the transfer when the handler does not know about versions.
+func ConditionalGet() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = buffered
+		// Restored before a panic reaches Recovery, which writes its own response
+		defer func() { c.Writer = original }()
+		c.Next()
+		c.Writer = original
+
+		body := buffered.body.Bytes()
+		if buffered.status == http.StatusOK {
+			etag := original.Header().Get("ETag")
+			if etag == "" {
+				sum := sha256.Sum256(body)
+				etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+				original.Header().Set("ETag", etag)
+			}
+			if etagMatches(c.GetHeader("If-None-Match"), etag) {
+				original.Header().Del("Content-Length")
+				original.WriteHeader(http.StatusNotModified)
+				original.WriteHeaderNow()
+				return
+			}
+		}
+
+		original.WriteHeader(buffered.status)
+		_, _ = original.Write(body)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header names the ETag. Weak and strong tags are
+// compared alike, as If-None-Match requires.
+func etagMatches(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
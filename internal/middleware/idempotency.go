@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/idempotency"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a mutating request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response that was replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// responseRecorder keeps a copy of the response body written by the handlers
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the client and keeps a copy
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the client and keeps a copy
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a POST, PUT, PATCH or DELETE request is retried
+// with the same Idempotency-Key header. Keys are scoped to the authenticated user and kept for
+// the TTL. Reusing a key for a different request is rejected, and so is a retry that arrives
+// while the first request is still running. Server errors are not stored, so such requests can
+// be retried with the same key. It must run after AuthMiddleware.
+func Idempotency(store idempotency.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &model.IdempotencyKey{
+			UserID:      userID.(uint),
+			Key:         key,
+			Fingerprint: requestFingerprint(c.Request, body),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+		existing, err := store.Reserve(c.Request.Context(), record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			case !existing.IsComplete():
+				c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+			default:
+				for name, values := range existing.Headers {
+					c.Writer.Header()[name] = values
+				}
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		// The response is stored even if the client went away, since that is when it retries
+		ctx := context.WithoutCancel(c.Request.Context())
+		release := func() {
+			if err := store.Release(ctx, record.UserID, record.Key); err != nil {
+				log.Printf("idempotency: failed to release key %q: %v", record.Key, err)
+			}
+		}
+
+		// A panicking handler must not leave the key reserved until it expires
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			release()
+			return
+		}
+
+		record.StatusCode = status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.Headers = replayableHeaders(recorder.Header())
+		record.Body = recorder.body.Bytes()
+		if err := store.Complete(ctx, record); err != nil {
+			log.Printf("idempotency: failed to store response for key %q: %v", record.Key, err)
+		}
+	}
+}
+
+// replayableHeaders copies the response headers that a replay repeats. The content type is
+// stored on its own, and the length, date and cookies are set afresh for every response.
+func replayableHeaders(header http.Header) model.ResponseHeaders {
+	headers := model.ResponseHeaders{}
+	for name, values := range header {
+		switch name {
+		case "Content-Type", "Content-Length", "Date", "Set-Cookie":
+			continue
+		}
+		headers[name] = append([]string(nil), values...)
+	}
+	return headers
+}
+
+// isMutating reports whether requests with the method change state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestFingerprint hashes what makes two requests the same: the method, path, query and body
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
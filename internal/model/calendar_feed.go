@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// CalendarFeedKind selects which events a calendar feed contains
+type CalendarFeedKind string
+
+const (
+	// CalendarFeedEvents lists every upcoming meal event open to employees
+	CalendarFeedEvents CalendarFeedKind = "events"
+	// CalendarFeedPersonal lists the meals the feed owner requested
+	CalendarFeedPersonal CalendarFeedKind = "personal"
+)
+
+// IsValid reports whether the kind is a known feed kind
+func (k CalendarFeedKind) IsValid() bool {
+	return k == CalendarFeedEvents || k == CalendarFeedPersonal
+}
+
+// CalendarFeed is a secret, revocable URL from which calendar clients fetch meal events
+type CalendarFeed struct {
+	Base
+	UserID         uint             `json:"user_id" gorm:"not null;index"`
+	Kind           CalendarFeedKind `json:"kind" gorm:"not null" enums:"events,personal"`
+	Token          string           `json:"-" gorm:"not null;uniqueIndex"`
+	URL            string           `json:"url" gorm:"-" example:"http://localhost:8080/api/calendar/3q2-7w.ics"`
+	RevokedAt      *time.Time       `json:"revoked_at"`
+	LastAccessedAt *time.Time       `json:"last_accessed_at"`
+	User           User             `json:"-" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// The allergens EU Regulation 1169/2011 requires food businesses to declare
+const (
+	AllergenGluten      = "gluten" // cereals containing gluten
+	AllergenCrustaceans = "crustaceans"
+	AllergenEggs        = "eggs"
+	AllergenFish        = "fish"
+	AllergenPeanuts     = "peanuts"
+	AllergenSoybeans    = "soybeans"
+	AllergenMilk        = "milk"
+	AllergenNuts        = "nuts" // tree nuts
+	AllergenCelery      = "celery"
+	AllergenMustard     = "mustard"
+	AllergenSesame      = "sesame"
+	AllergenSulphites   = "sulphites"
+	AllergenLupin       = "lupin"
+	AllergenMolluscs    = "molluscs"
+)
+
+// EUAllergens lists the allergens that must be declared in the EU. Items may declare others as well.
+var EUAllergens = []string{
+	AllergenGluten, AllergenCrustaceans, AllergenEggs, AllergenFish, AllergenPeanuts,
+	AllergenSoybeans, AllergenMilk, AllergenNuts, AllergenCelery, AllergenMustard,
+	AllergenSesame, AllergenSulphites, AllergenLupin, AllergenMolluscs,
+}
+
+// Dietary tags a menu item can carry
+const (
+	DietaryVegetarian = "vegetarian"
+	DietaryVegan      = "vegan"
+	DietaryHalal      = "halal"
+	DietaryKosher     = "kosher"
+	DietaryGlutenFree = "gluten_free"
+	DietaryDairyFree  = "dairy_free"
+	DietaryNutFree    = "nut_free"
+)
+
+// KnownDietaryTags lists the dietary tags a menu item can carry
+var KnownDietaryTags = []string{
+	DietaryVegetarian, DietaryVegan, DietaryHalal, DietaryKosher,
+	DietaryGlutenFree, DietaryDairyFree, DietaryNutFree,
+}
+
+// DietaryConflicts lists the allergens an item carrying a dietary tag cannot contain
+var DietaryConflicts = map[string][]string{
+	DietaryVegetarian: {AllergenFish, AllergenCrustaceans, AllergenMolluscs},
+	DietaryVegan:      {AllergenFish, AllergenCrustaceans, AllergenMolluscs, AllergenMilk, AllergenEggs},
+	DietaryGlutenFree: {AllergenGluten},
+	DietaryDairyFree:  {AllergenMilk},
+	DietaryNutFree:    {AllergenNuts, AllergenPeanuts},
+}
+
+// DietaryImplications lists the dietary tags that follow from a tag
+var DietaryImplications = map[string][]string{
+	DietaryVegan: {DietaryVegetarian, DietaryDairyFree},
+}
+
+// IsDietaryTag reports whether a tag is one of the known dietary tags
+func IsDietaryTag(tag string) bool {
+	return StringList(KnownDietaryTags).Contains(tag)
+}
+
+// StringList is a list of strings stored as JSON
+type StringList []string
+
+// Contains reports whether the list holds the value
+func (l StringList) Contains(value string) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+}
@@ -4,6 +4,7 @@ package model
 type EventAddress struct {
 	Base
 	Address       string `json:"address" gorm:"not null"`
+	TimeZone      string `json:"time_zone" example:"Asia/Dhaka"` // IANA zone; empty uses the organization's
 	IsActive      bool   `json:"is_active" gorm:"default:true"`
 	CreatedBy     uint   `json:"created_by"`
 	UpdatedBy     uint   `json:"updated_by"`
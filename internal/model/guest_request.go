@@ -0,0 +1,61 @@
+package model
+
+// GuestRequestStatus represents the status of a guest meal request
+type GuestRequestStatus string
+
+const (
+	// GuestRequestStatusAwaitingApproval marks a guest that exceeds the host department's quota
+	GuestRequestStatusAwaitingApproval GuestRequestStatus = "awaiting_approval"
+	GuestRequestStatusApproved         GuestRequestStatus = "approved"
+	GuestRequestStatusRejected         GuestRequestStatus = "rejected"
+	GuestRequestStatusCancelled        GuestRequestStatus = "cancelled"
+)
+
+// IsActive reports whether the guest still holds a seat and counts towards estimates
+func (s GuestRequestStatus) IsActive() bool {
+	return s == GuestRequestStatusAwaitingApproval || s == GuestRequestStatusApproved
+}
+
+// GuestRequest is a meal requested by an employee for a visitor without an account
+type GuestRequest struct {
+	Base
+	MealEventID    uint               `json:"meal_event_id" gorm:"not null;index"`
+	HostUserID     uint               `json:"host_user_id" gorm:"not null;index"`
+	GuestName      string             `json:"guest_name" gorm:"not null" example:"Jane Doe"`
+	Organization   string             `json:"organization" example:"Acme Ltd."`
+	MenuSetID      uint               `json:"menu_set_id" gorm:"not null"`
+	EventAddressID uint               `json:"event_address_id" gorm:"not null"`
+	DietaryNotes   string             `json:"dietary_notes" example:"Vegetarian, no nuts"`
+	Status         GuestRequestStatus `json:"status" gorm:"not null;default:'approved'" enums:"awaiting_approval,approved,rejected,cancelled"`
+	ReviewedBy     *uint              `json:"reviewed_by"`
+	ReviewNote     string             `json:"review_note"`
+	Version        int                `json:"version" gorm:"not null;default:1"` // optimistic locking version, raised on every change
+	CreatedBy      uint               `json:"created_by"`
+	UpdatedBy      uint               `json:"updated_by"`
+	Host           User               `json:"host" gorm:"foreignKey:HostUserID"`
+	MealEvent      MealEvent          `json:"-" gorm:"foreignKey:MealEventID"`
+	MenuSet        MenuSet            `json:"menu_set" gorm:"foreignKey:MenuSetID"`
+	EventAddress   EventAddress       `json:"event_address" gorm:"foreignKey:EventAddressID"`
+	Items          []GuestRequestItem `json:"items" gorm:"foreignKey:GuestRequestID"`
+}
+
+// GuestRequestItem represents a menu item chosen for a guest
+type GuestRequestItem struct {
+	Base
+	GuestRequestID uint     `json:"guest_request_id" gorm:"not null;index"`
+	MenuItemID     uint     `json:"menu_item_id" gorm:"not null"`
+	IsSelected     bool     `json:"is_selected" gorm:"not null;default:true"`
+	Quantity       int      `json:"quantity" gorm:"not null;default:1"`
+	Notes          string   `json:"notes"`
+	MenuItem       MenuItem `json:"menu_item" gorm:"foreignKey:MenuItemID"`
+}
+
+// DepartmentGuestQuota limits how many guest meals a department may host per calendar month
+// before each further guest needs an admin's approval
+type DepartmentGuestQuota struct {
+	Base
+	Department   string `json:"department" gorm:"not null;uniqueIndex" example:"Sales"`
+	MonthlyLimit int    `json:"monthly_limit" gorm:"not null" example:"20"`
+	CreatedBy    uint   `json:"created_by"`
+	UpdatedBy    uint   `json:"updated_by"`
+}
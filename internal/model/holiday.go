@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Holiday represents a date on which the organization or one of its addresses is closed.
+// Closures without an address apply to every address.
+type Holiday struct {
+	Base
+	Date           time.Time     `json:"date" gorm:"not null" example:"2025-12-16T00:00:00Z"`
+	Name           string        `json:"name" gorm:"not null" example:"Victory Day"`
+	EventAddressID *uint         `json:"event_address_id" gorm:"index"` // nil for organization-wide holidays
+	EventAddress   *EventAddress `json:"event_address,omitempty" gorm:"foreignKey:EventAddressID"`
+	ImportUID      string        `json:"import_uid,omitempty"` // UID of the imported iCalendar event
+	CreatedBy      uint          `json:"created_by"`
+	UpdatedBy      uint          `json:"updated_by"`
+	CreatedByUser  User          `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser  User          `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	AffectedEvents []MealEvent   `json:"affected_events,omitempty" gorm:"-"` // scheduled events falling on the closure
+}
+
+// AppliesTo reports whether the closure closes the given address
+func (h *Holiday) AppliesTo(addressID uint) bool {
+	return h.EventAddressID == nil || *h.EventAddressID == addressID
+}
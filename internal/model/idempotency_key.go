@@ -0,0 +1,57 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// IdempotencyKey remembers the response to a mutating request sent with an Idempotency-Key header,
+// so that a retry with the same key gets the same response instead of repeating the change
+type IdempotencyKey struct {
+	UserID      uint            `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Key         string          `json:"key" gorm:"primaryKey;size:255"`
+	Fingerprint string          `json:"fingerprint" gorm:"not null"`           // hash of the method, path and body
+	StatusCode  int             `json:"status_code" gorm:"not null;default:0"` // 0 while the request is in flight
+	ContentType string          `json:"content_type"`
+	Headers     ResponseHeaders `json:"headers" gorm:"type:jsonb;not null;default:'{}'"` // such as ETag and Location
+	Body        []byte          `json:"-"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt   time.Time       `json:"expires_at" gorm:"not null;index"`
+}
+
+// IsComplete reports whether the response of the request was stored
+func (k *IdempotencyKey) IsComplete() bool {
+	return k.StatusCode != 0
+}
+
+// ResponseHeaders are the headers of a stored response, stored as JSON
+type ResponseHeaders map[string][]string
+
+// Value implements driver.Valuer
+func (h ResponseHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (h *ResponseHeaders) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, h)
+	case string:
+		return json.Unmarshal([]byte(v), h)
+	default:
+		return fmt.Errorf("cannot scan %T into ResponseHeaders", value)
+	}
+}
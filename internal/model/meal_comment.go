@@ -8,7 +8,8 @@ type MenuItemComment struct {
 	MenuItemID    uint              `json:"menu_item_id" gorm:"not null"`
 	Comment       string            `json:"comment" gorm:"not null"`
 	Rating        int               `json:"rating" gorm:"check:rating >= 1 AND rating <= 5;not null"`
-	ParentID      *uint             `json:"parent_id" gorm:"default:null"` // Added to support replies
+	ParentID      *uint             `json:"parent_id" gorm:"default:null"`     // Added to support replies
+	Version       int               `json:"version" gorm:"not null;default:1"` // optimistic locking version, raised on every change
 	CreatedBy     uint              `json:"created_by"`
 	UpdatedBy     uint              `json:"updated_by"`
 	User          User              `json:"user" gorm:"foreignKey:UserID"`
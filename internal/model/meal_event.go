@@ -5,21 +5,31 @@ import "time"
 // MealEvent represents a meal event in the system
 type MealEvent struct {
 	Base
-	Name             string             `json:"name" gorm:"not null"`
-	Description      string             `json:"description"`
-	EventDate        time.Time          `json:"event_date" gorm:"not null"`
-	EventDuration    int                `json:"event_duration" gorm:"not null"` // in minutes
-	CutoffTime       time.Time          `json:"cutoff_time" gorm:"not null"`
-	IsActive         bool               `json:"is_active" gorm:"default:true"`
-	ConfirmedAt      *time.Time         `json:"confirmed_at"`
-	CreatedBy        uint               `json:"created_by"`
-	UpdatedBy        uint               `json:"updated_by"`
-	CreatedByUser    User               `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
-	UpdatedByUser    User               `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
-	MenuSets         []MealEventSet     `json:"menu_sets" gorm:"foreignKey:MealEventID"`
-	Addresses        []MealEventAddress `json:"addresses" gorm:"foreignKey:MealEventID"`
-	MealRequests     []MealRequest      `json:"meal_requests" gorm:"foreignKey:MealEventID"`
-	MenuItemComments []MenuItemComment  `json:"menu_item_comments" gorm:"foreignKey:MealEventID"`
+	Name              string             `json:"name" gorm:"not null"`
+	Description       string             `json:"description"`
+	MealType          MealType           `json:"meal_type" gorm:"not null;default:'lunch'" enums:"breakfast,lunch,snacks"`
+	EventDate         time.Time          `json:"event_date" gorm:"not null"`
+	EventDuration     int                `json:"event_duration" gorm:"not null"` // in minutes
+	CutoffTime        time.Time          `json:"cutoff_time" gorm:"not null"`
+	IsActive          bool               `json:"is_active" gorm:"default:true"`
+	Status            MealEventStatus    `json:"status" gorm:"not null;default:'draft'" enums:"draft,published,closed,confirmed,completed,cancelled"`
+	ConfirmedAt       *time.Time         `json:"confirmed_at"`
+	SeriesID          *uint              `json:"series_id" gorm:"index"`
+	SeriesOccurrence  *time.Time         `json:"series_occurrence"`                                 // originally scheduled start within the series
+	IsException       bool               `json:"is_exception" gorm:"default:false"`                 // edited independently of its series
+	Sequence          int                `json:"sequence" gorm:"not null;default:0"`                // iCalendar revision, raised on every change
+	AllowLateRequests bool               `json:"allow_late_requests" gorm:"not null;default:false"` // employees may ask for spare food after the cutoff
+	LateRequestCap    int                `json:"late_request_cap" gorm:"not null;default:0"`        // late requests that may be approved; 0 is unlimited
+	Version           int                `json:"version" gorm:"not null;default:1"`                 // optimistic locking version, raised on every change
+	CreatedBy         uint               `json:"created_by"`
+	UpdatedBy         uint               `json:"updated_by"`
+	CreatedByUser     User               `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser     User               `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	MenuSets          []MealEventSet     `json:"menu_sets" gorm:"foreignKey:MealEventID"`
+	Addresses         []MealEventAddress `json:"addresses" gorm:"foreignKey:MealEventID"`
+	MealRequests      []MealRequest      `json:"meal_requests" gorm:"foreignKey:MealEventID"`
+	MenuItemComments  []MenuItemComment  `json:"menu_item_comments" gorm:"foreignKey:MealEventID"`
+	Warnings          []string           `json:"warnings,omitempty" gorm:"-"` // non-blocking issues found while saving
 }
 
 // MealEventSet represents a junction table between meal events and menu sets
@@ -28,6 +38,7 @@ type MealEventSet struct {
 	MenuSetID     uint       `json:"menu_set_id" gorm:"primaryKey;not null"`
 	Label         string     `json:"label"`
 	Note          string     `json:"note"`
+	Surplus       int        `json:"surplus" gorm:"not null;default:0"` // spare portions the kitchen can hand out to late requests
 	DeletedAt     *time.Time `json:"deleted_at" gorm:"index"`
 	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
@@ -44,6 +55,7 @@ type MealEventAddress struct {
 	Base
 	MealEventID   uint         `json:"meal_event_id" gorm:"not null"`
 	AddressID     uint         `json:"address_id" gorm:"not null"`
+	Capacity      int          `json:"capacity"` // seats for employees and guests; 0 is unlimited
 	MealEvent     MealEvent    `json:"meal_event" gorm:"foreignKey:MealEventID"`
 	Address       EventAddress `json:"address" gorm:"foreignKey:AddressID"`
 	CreatedBy     uint         `json:"created_by"`
@@ -51,3 +63,60 @@ type MealEventAddress struct {
 	CreatedByUser User         `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
 	UpdatedByUser User         `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
 }
+
+// MealEventStatus represents the lifecycle stage of a meal event
+type MealEventStatus string
+
+const (
+	MealEventStatusDraft     MealEventStatus = "draft"
+	MealEventStatusPublished MealEventStatus = "published"
+	MealEventStatusClosed    MealEventStatus = "closed"
+	MealEventStatusConfirmed MealEventStatus = "confirmed"
+	MealEventStatusCompleted MealEventStatus = "completed"
+	MealEventStatusCancelled MealEventStatus = "cancelled"
+)
+
+// mealEventTransitions lists the statuses each status may move to
+var mealEventTransitions = map[MealEventStatus][]MealEventStatus{
+	MealEventStatusDraft:     {MealEventStatusPublished, MealEventStatusCancelled},
+	MealEventStatusPublished: {MealEventStatusClosed, MealEventStatusCancelled},
+	MealEventStatusClosed:    {MealEventStatusConfirmed, MealEventStatusCancelled},
+	MealEventStatusConfirmed: {MealEventStatusCompleted, MealEventStatusCancelled},
+}
+
+// IsValid reports whether the status is a known lifecycle stage
+func (s MealEventStatus) IsValid() bool {
+	switch s {
+	case MealEventStatusDraft, MealEventStatusPublished, MealEventStatusClosed,
+		MealEventStatusConfirmed, MealEventStatusCompleted, MealEventStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo reports whether an event may move from this status to the next one
+func (s MealEventStatus) CanTransitionTo(next MealEventStatus) bool {
+	for _, allowed := range mealEventTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsRequests reports whether employees may still create or change meal requests
+func (s MealEventStatus) AcceptsRequests() bool {
+	return s == MealEventStatusPublished
+}
+
+// MealEventTransition records a status change of a meal event
+type MealEventTransition struct {
+	Base
+	MealEventID uint            `json:"meal_event_id" gorm:"not null;index"`
+	FromStatus  MealEventStatus `json:"from_status" gorm:"not null"`
+	ToStatus    MealEventStatus `json:"to_status" gorm:"not null"`
+	Reason      string          `json:"reason"`
+	ActorID     *uint           `json:"actor_id"` // nil when the system made the change
+	Actor       *User           `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+}
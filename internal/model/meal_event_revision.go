@@ -0,0 +1,55 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// FieldChange describes how one field changed in an edit
+type FieldChange struct {
+	Field string `json:"field" example:"event_date"`
+	From  string `json:"from" example:"Mon, 02 Jun 2025 13:00 +06"`
+	To    string `json:"to" example:"Mon, 02 Jun 2025 13:30 +06"`
+}
+
+// FieldChanges is a list of field changes stored as JSON
+type FieldChanges []FieldChange
+
+// Value implements driver.Valuer
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (c *FieldChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("cannot scan %T into FieldChanges", value)
+	}
+}
+
+// MealEventRevision records an edit of a meal event, its menu sets or its addresses
+type MealEventRevision struct {
+	Base
+	MealEventID uint         `json:"meal_event_id" gorm:"not null;index"`
+	Sequence    int          `json:"sequence" gorm:"not null"` // the event's sequence after the edit
+	Changes     FieldChanges `json:"changes" gorm:"type:jsonb;not null"`
+	Material    bool         `json:"material" gorm:"not null;default:false"` // date, cutoff, menu sets or addresses changed
+	ActorID     *uint        `json:"actor_id"`
+	Actor       *User        `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+}
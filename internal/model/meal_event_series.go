@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// RecurrenceFrequency represents how often a meal event series repeats
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily    RecurrenceFrequency = "daily"
+	RecurrenceWeekdays RecurrenceFrequency = "weekdays"
+	RecurrenceWeekly   RecurrenceFrequency = "weekly"
+)
+
+// MealEventSeries represents a recurring schedule from which concrete meal events are generated
+type MealEventSeries struct {
+	Base
+	Name           string                   `json:"name" gorm:"not null"`
+	Description    string                   `json:"description"`
+	MealType       MealType                 `json:"meal_type" gorm:"not null;default:'lunch'" enums:"breakfast,lunch,snacks"`
+	Frequency      RecurrenceFrequency      `json:"frequency" gorm:"not null" enums:"daily,weekdays,weekly"`
+	Weekdays       string                   `json:"weekdays" example:"mon,wed,fri"` // only used by weekly series
+	StartDate      time.Time                `json:"start_date" gorm:"not null"`
+	StartTime      string                   `json:"start_time" gorm:"not null" example:"13:00"`
+	Until          *time.Time               `json:"until"`
+	Count          *int                     `json:"count"`
+	EventDuration  int                      `json:"event_duration" gorm:"not null"` // in minutes
+	CutoffOffset   int                      `json:"cutoff_offset" gorm:"not null"`  // in minutes before the event starts
+	IsActive       bool                     `json:"is_active" gorm:"default:true"`
+	GeneratedUntil *time.Time               `json:"generated_until"`
+	Version        int                      `json:"version" gorm:"not null;default:1"` // optimistic locking version, raised on every change
+	CreatedBy      uint                     `json:"created_by"`
+	UpdatedBy      uint                     `json:"updated_by"`
+	CreatedByUser  User                     `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser  User                     `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	MenuSets       []MealEventSeriesSet     `json:"menu_sets" gorm:"foreignKey:SeriesID"`
+	Addresses      []MealEventSeriesAddress `json:"addresses" gorm:"foreignKey:SeriesID"`
+}
+
+// MealEventSeriesSet represents a menu set offered at every occurrence of a series
+type MealEventSeriesSet struct {
+	SeriesID      uint      `json:"series_id" gorm:"primaryKey;not null"`
+	MenuSetID     uint      `json:"menu_set_id" gorm:"primaryKey;not null"`
+	Label         string    `json:"label"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy     uint      `json:"created_by"`
+	UpdatedBy     uint      `json:"updated_by"`
+	MenuSet       MenuSet   `json:"menu_set" gorm:"foreignKey:MenuSetID"`
+	CreatedByUser User      `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User      `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+}
+
+// MealEventSeriesAddress represents an address served at every occurrence of a series
+type MealEventSeriesAddress struct {
+	SeriesID      uint         `json:"series_id" gorm:"primaryKey;not null"`
+	AddressID     uint         `json:"address_id" gorm:"primaryKey;not null"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy     uint         `json:"created_by"`
+	UpdatedBy     uint         `json:"updated_by"`
+	Address       EventAddress `json:"address" gorm:"foreignKey:AddressID"`
+	CreatedByUser User         `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User         `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+}
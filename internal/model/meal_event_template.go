@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// MealEventTemplate represents a named set of defaults from which meal events can be created
+type MealEventTemplate struct {
+	Base
+	Name          string                     `json:"name" gorm:"not null;uniqueIndex" example:"Weekday lunch"`
+	EventName     string                     `json:"event_name" gorm:"not null" example:"Lunch"`
+	Description   string                     `json:"description"`
+	MealType      MealType                   `json:"meal_type" gorm:"not null;default:'lunch'" enums:"breakfast,lunch,snacks"`
+	StartTime     string                     `json:"start_time" gorm:"not null" example:"13:00"`
+	EventDuration int                        `json:"event_duration" gorm:"not null"` // in minutes
+	CutoffOffset  int                        `json:"cutoff_offset" gorm:"not null"`  // in minutes before the event starts
+	IsActive      bool                       `json:"is_active" gorm:"default:true"`
+	Version       int                        `json:"version" gorm:"not null;default:1"` // optimistic locking version, raised on every change
+	CreatedBy     uint                       `json:"created_by"`
+	UpdatedBy     uint                       `json:"updated_by"`
+	CreatedByUser User                       `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User                       `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	MenuSets      []MealEventTemplateSet     `json:"menu_sets" gorm:"foreignKey:TemplateID"`
+	Addresses     []MealEventTemplateAddress `json:"addresses" gorm:"foreignKey:TemplateID"`
+}
+
+// MealEventTemplateSet represents a menu set offered by events created from a template
+type MealEventTemplateSet struct {
+	TemplateID    uint      `json:"template_id" gorm:"primaryKey;not null"`
+	MenuSetID     uint      `json:"menu_set_id" gorm:"primaryKey;not null"`
+	Label         string    `json:"label"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy     uint      `json:"created_by"`
+	UpdatedBy     uint      `json:"updated_by"`
+	MenuSet       MenuSet   `json:"menu_set" gorm:"foreignKey:MenuSetID"`
+	CreatedByUser User      `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User      `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+}
+
+// MealEventTemplateAddress represents an address served by events created from a template
+type MealEventTemplateAddress struct {
+	TemplateID    uint         `json:"template_id" gorm:"primaryKey;not null"`
+	AddressID     uint         `json:"address_id" gorm:"primaryKey;not null"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy     uint         `json:"created_by"`
+	UpdatedBy     uint         `json:"updated_by"`
+	Address       EventAddress `json:"address" gorm:"foreignKey:AddressID"`
+	CreatedByUser User         `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User         `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+}
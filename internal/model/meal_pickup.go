@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// MealPickup records that an approved meal request was scanned at a pickup kiosk
+type MealPickup struct {
+	Base
+	MealRequestID uint         `json:"meal_request_id" gorm:"not null;uniqueIndex"` // a meal is picked up once
+	MealEventID   uint         `json:"meal_event_id" gorm:"not null;index"`
+	UserID        uint         `json:"user_id" gorm:"not null;index"`
+	AddressID     uint         `json:"address_id" gorm:"not null"` // where the meal was picked up
+	PickedUpAt    time.Time    `json:"picked_up_at" gorm:"not null"`
+	WrongLocation bool         `json:"wrong_location" gorm:"not null;default:false"` // picked up away from the requested address
+	ScannedBy     uint         `json:"scanned_by" gorm:"not null"`
+	User          User         `json:"user" gorm:"foreignKey:UserID"`
+	Address       EventAddress `json:"address" gorm:"foreignKey:AddressID"`
+	MealRequest   MealRequest  `json:"-" gorm:"foreignKey:MealRequestID"`
+}
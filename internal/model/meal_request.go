@@ -5,20 +5,32 @@ import "time"
 // MealRequest represents a meal request entity in the system
 type MealRequest struct {
 	Base
-	UserID         uint              `json:"user_id" gorm:"not null"`
-	MealEventID    uint              `json:"meal_event_id" gorm:"not null"`
-	MenuSetID      uint              `json:"menu_set_id"`
-	EventAddressID uint              `json:"event_address_id"`
-	ConfirmedAt    *time.Time        `json:"confirmed_at"`
-	CreatedBy      uint              `json:"created_by"`
-	UpdatedBy      uint              `json:"updated_by"`
-	User           User              `json:"user" gorm:"foreignKey:UserID"`
-	MealEvent      MealEvent         `json:"meal_event" gorm:"foreignKey:MealEventID"`
-	MenuSet        MenuSet           `json:"menu_set" gorm:"foreignKey:MenuSetID"`
-	EventAddress   EventAddress      `json:"event_address" gorm:"foreignKey:EventAddressID"`
-	CreatedByUser  User              `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
-	UpdatedByUser  User              `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
-	RequestItems   []MealRequestItem `json:"request_items" gorm:"foreignKey:MealRequestID"`
+	UserID           uint              `json:"user_id" gorm:"not null"`
+	MealEventID      uint              `json:"meal_event_id" gorm:"not null"`
+	MenuSetID        uint              `json:"menu_set_id"`
+	EventAddressID   uint              `json:"event_address_id"`
+	Status           RequestStatus     `json:"status" gorm:"not null;default:'pending'" enums:"pending,approved,rejected,completed,cancelled,no_show"`
+	ConfirmedAt      *time.Time        `json:"confirmed_at"`
+	NeedsAttention   bool              `json:"needs_attention" gorm:"not null;default:false"` // the chosen menu set or address was removed from the event
+	AttentionNote    string            `json:"attention_note"`
+	AdminReason      string            `json:"admin_reason,omitempty"`                          // why an admin last assigned or changed the request for the user
+	StandingOrderID  *uint             `json:"standing_order_id"`                               // set when a standing order created the request
+	AwaitingApproval bool              `json:"awaiting_approval" gorm:"not null;default:false"` // held for the line manager after repeated no-shows
+	NoShowExcused    bool              `json:"no_show_excused" gorm:"not null;default:false"`   // the no-show does not count towards no-show policies
+	IsLate           bool              `json:"is_late" gorm:"not null;default:false"`           // submitted after the cutoff; approved by the kitchen from surplus
+	LateReason       string            `json:"late_reason,omitempty"`                           // the employee's justification for a late request
+	Sequence         int               `json:"sequence" gorm:"not null;default:0"`              // iCalendar revision, raised on every change
+	Version          int               `json:"version" gorm:"not null;default:1"`               // optimistic locking version, raised on every change
+	CreatedBy        uint              `json:"created_by"`
+	UpdatedBy        uint              `json:"updated_by"`
+	User             User              `json:"user" gorm:"foreignKey:UserID"`
+	MealEvent        MealEvent         `json:"meal_event" gorm:"foreignKey:MealEventID"`
+	MenuSet          MenuSet           `json:"menu_set" gorm:"foreignKey:MenuSetID"`
+	EventAddress     EventAddress      `json:"event_address" gorm:"foreignKey:EventAddressID"`
+	CreatedByUser    User              `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser    User              `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	RequestItems     []MealRequestItem `json:"request_items" gorm:"foreignKey:MealRequestID"`
+	Warnings         []string          `json:"warnings,omitempty" gorm:"-"` // dietary issues with the chosen menu set, found while saving
 }
 
 // MealRequestItem represents an item in a meal request
@@ -48,4 +60,52 @@ const (
 	RequestStatusRejected  RequestStatus = "rejected"
 	RequestStatusCompleted RequestStatus = "completed"
 	RequestStatusCancelled RequestStatus = "cancelled"
+	// RequestStatusNoShow marks an approved meal that was not picked up
+	RequestStatusNoShow RequestStatus = "no_show"
 )
+
+// requestTransitions lists the statuses each request status may move to
+var requestTransitions = map[RequestStatus][]RequestStatus{
+	RequestStatusPending:  {RequestStatusApproved, RequestStatusRejected, RequestStatusCancelled},
+	RequestStatusApproved: {RequestStatusCompleted, RequestStatusCancelled, RequestStatusNoShow},
+	// Offline kiosks may upload a pickup after the meal was marked as a no-show
+	RequestStatusNoShow: {RequestStatusCompleted},
+}
+
+// IsValid reports whether the status is a known request status
+func (s RequestStatus) IsValid() bool {
+	switch s {
+	case RequestStatusPending, RequestStatusApproved, RequestStatusRejected,
+		RequestStatusCompleted, RequestStatusCancelled, RequestStatusNoShow:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo reports whether a request may move from this status to the next one
+func (s RequestStatus) CanTransitionTo(next RequestStatus) bool {
+	for _, allowed := range requestTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the request still counts towards its meal event
+func (s RequestStatus) IsActive() bool {
+	return s == RequestStatusPending || s == RequestStatusApproved || s == RequestStatusCompleted ||
+		s == RequestStatusNoShow
+}
+
+// MealRequestTransition records a status change of a meal request
+type MealRequestTransition struct {
+	Base
+	MealRequestID uint          `json:"meal_request_id" gorm:"not null;index"`
+	FromStatus    RequestStatus `json:"from_status" gorm:"not null"`
+	ToStatus      RequestStatus `json:"to_status" gorm:"not null"`
+	Reason        string        `json:"reason"`
+	ActorID       *uint         `json:"actor_id"` // nil when the system made the change
+	Actor         *User         `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// MealTransferStatus represents the status of a meal transfer
+type MealTransferStatus string
+
+const (
+	// MealTransferStatusOffered waits for the colleague to accept, or for anyone to claim it from the surplus pool
+	MealTransferStatusOffered   MealTransferStatus = "offered"
+	MealTransferStatusAccepted  MealTransferStatus = "accepted"
+	MealTransferStatusDeclined  MealTransferStatus = "declined"
+	MealTransferStatusWithdrawn MealTransferStatus = "withdrawn"
+	MealTransferStatusClaimed   MealTransferStatus = "claimed"
+)
+
+// MealTransfer hands a confirmed meal request over to another employee after the cutoff. Accepted and
+// claimed transfers are the audit trail of the request's owners.
+type MealTransfer struct {
+	Base
+	MealRequestID uint               `json:"meal_request_id" gorm:"not null;index"`
+	MealEventID   uint               `json:"meal_event_id" gorm:"not null;index"`
+	FromUserID    uint               `json:"from_user_id" gorm:"not null;index"`
+	ToUserID      *uint              `json:"to_user_id" gorm:"index"` // nil while offered to the surplus pool
+	Pool          bool               `json:"pool" gorm:"not null;default:false"`
+	Status        MealTransferStatus `json:"status" gorm:"not null;default:'offered'" enums:"offered,accepted,declined,withdrawn,claimed"`
+	Note          string             `json:"note"`
+	RespondedAt   *time.Time         `json:"responded_at"`
+	FromUser      User               `json:"from_user" gorm:"foreignKey:FromUserID"`
+	ToUser        *User              `json:"to_user,omitempty" gorm:"foreignKey:ToUserID"`
+	MealEvent     MealEvent          `json:"meal_event" gorm:"foreignKey:MealEventID"`
+	MealRequest   MealRequest        `json:"meal_request" gorm:"foreignKey:MealRequestID"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// MealTypeDefault holds the organization's usual settings for events of one meal type
+type MealTypeDefault struct {
+	Base
+	MealType      MealType                 `json:"meal_type" gorm:"not null;uniqueIndex" enums:"breakfast,lunch,snacks"`
+	StartTime     string                   `json:"start_time" gorm:"not null" example:"13:00"`
+	EventDuration int                      `json:"event_duration" gorm:"not null"` // in minutes
+	CutoffOffset  int                      `json:"cutoff_offset" gorm:"not null"`  // in minutes before the event starts
+	CreatedBy     uint                     `json:"created_by"`
+	UpdatedBy     uint                     `json:"updated_by"`
+	CreatedByUser User                     `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User                     `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	MenuSets      []MealTypeDefaultSet     `json:"menu_sets" gorm:"foreignKey:DefaultID"`
+	Addresses     []MealTypeDefaultAddress `json:"addresses" gorm:"foreignKey:DefaultID"`
+}
+
+// MealTypeDefaultSet represents a menu set offered by default for a meal type
+type MealTypeDefaultSet struct {
+	DefaultID     uint      `json:"default_id" gorm:"primaryKey;not null"`
+	MenuSetID     uint      `json:"menu_set_id" gorm:"primaryKey;not null"`
+	Label         string    `json:"label"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy     uint      `json:"created_by"`
+	UpdatedBy     uint      `json:"updated_by"`
+	MenuSet       MenuSet   `json:"menu_set" gorm:"foreignKey:MenuSetID"`
+	CreatedByUser User      `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User      `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+}
+
+// MealTypeDefaultAddress represents an address served by default for a meal type
+type MealTypeDefaultAddress struct {
+	DefaultID     uint         `json:"default_id" gorm:"primaryKey;not null"`
+	AddressID     uint         `json:"address_id" gorm:"primaryKey;not null"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+	CreatedBy     uint         `json:"created_by"`
+	UpdatedBy     uint         `json:"updated_by"`
+	Address       EventAddress `json:"address" gorm:"foreignKey:AddressID"`
+	CreatedByUser User         `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User         `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+}
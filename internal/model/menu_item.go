@@ -7,6 +7,11 @@ type MenuItem struct {
 	Description      string            `json:"description"`
 	ImageURL         string            `json:"image_url"`
 	IsActive         bool              `json:"is_active" gorm:"default:true"`
+	MinQuantity      int               `json:"min_quantity" gorm:"not null;default:1" example:"1"` // fewest servings one request may ask for
+	MaxQuantity      int               `json:"max_quantity" gorm:"not null;default:0" example:"2"` // most servings one request may ask for; 0 is unlimited
+	Version          int               `json:"version" gorm:"not null;default:1"`                  // optimistic locking version, raised on every change
+	Allergens        StringList        `json:"allergens" gorm:"type:jsonb;not null;default:'[]'" swaggertype:"array,string" example:"milk,nuts"`
+	DietaryTags      StringList        `json:"dietary_tags" gorm:"type:jsonb;not null;default:'[]'" swaggertype:"array,string" example:"vegetarian,halal"`
 	CreatedBy        uint              `json:"created_by"`
 	UpdatedBy        uint              `json:"updated_by"`
 	CreatedByUser    User              `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
@@ -17,6 +22,22 @@ type MenuItem struct {
 	AverageRating    float64           `json:"average_rating" gorm:"type:numeric(3,2);default:0"`
 }
 
+// DefaultQuantity returns the number of servings requested when none is given
+func (m MenuItem) DefaultQuantity() int {
+	if m.MinQuantity > 1 {
+		return m.MinQuantity
+	}
+	return 1
+}
+
+// AllowsQuantity reports whether one request may ask for the given number of servings
+func (m MenuItem) AllowsQuantity(quantity int) bool {
+	if quantity < m.DefaultQuantity() {
+		return false
+	}
+	return m.MaxQuantity <= 0 || quantity <= m.MaxQuantity
+}
+
 // MealType represents the type of meal
 type MealType string
 
@@ -25,3 +46,13 @@ const (
 	MealTypeLunch     MealType = "lunch"
 	MealTypeSnacks    MealType = "snacks"
 )
+
+// IsValid reports whether the meal type is one of the known types
+func (t MealType) IsValid() bool {
+	switch t {
+	case MealTypeBreakfast, MealTypeLunch, MealTypeSnacks:
+		return true
+	default:
+		return false
+	}
+}
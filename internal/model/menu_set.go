@@ -6,12 +6,49 @@ type MenuSet struct {
 	MenuSetName        string         `json:"menu_set_name" gorm:"not null"`
 	MenuSetDescription string         `json:"menu_set_description"`
 	IsActive           bool           `json:"is_active" gorm:"default:true"`
+	Version            int            `json:"version" gorm:"not null;default:1"` // optimistic locking version, raised on every change
 	CreatedBy          uint           `json:"created_by"`
 	UpdatedBy          uint           `json:"updated_by"`
 	CreatedByUser      User           `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
 	UpdatedByUser      User           `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
 	MenuSetItems       []MenuSetItem  `json:"menu_set_items" gorm:"foreignKey:MenuSetID"`
 	MealEventSets      []MealEventSet `json:"meal_event_sets" gorm:"foreignKey:MenuSetID"`
+	DietaryTags        StringList     `json:"dietary_tags" gorm:"-" swaggertype:"array,string"` // tags every item of the set carries
+	Allergens          StringList     `json:"allergens" gorm:"-" swaggertype:"array,string"`    // allergens any item of the set contains
+}
+
+// DeriveDietaryInfo sets the dietary tags and allergens of the set from its loaded items.
+// A set is only vegan, for example, if all its items are; a set without items carries no tags.
+func (s *MenuSet) DeriveDietaryInfo() {
+	items := make([]MenuItem, 0, len(s.MenuSetItems))
+	for _, setItem := range s.MenuSetItems {
+		items = append(items, setItem.MenuItem)
+	}
+	s.DietaryTags, s.Allergens = DietaryInfo(items)
+}
+
+// DietaryInfo returns the dietary tags all items carry and the allergens any of them contains
+func DietaryInfo(items []MenuItem) (tags StringList, allergens StringList) {
+	tags, allergens = StringList{}, StringList{}
+	for i, item := range items {
+		if i == 0 {
+			tags = append(tags, item.DietaryTags...)
+		} else {
+			shared := StringList{}
+			for _, tag := range tags {
+				if item.DietaryTags.Contains(tag) {
+					shared = append(shared, tag)
+				}
+			}
+			tags = shared
+		}
+		for _, allergen := range item.Allergens {
+			if !allergens.Contains(allergen) {
+				allergens = append(allergens, allergen)
+			}
+		}
+	}
+	return tags, allergens
 }
 
 // MenuSetItem represents a menu item in a menu set
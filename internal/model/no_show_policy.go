@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// NoShowAction is the consequence a no-show policy has for a user
+type NoShowAction string
+
+const (
+	// NoShowActionWarn notifies the user
+	NoShowActionWarn NoShowAction = "warn"
+	// NoShowActionLimitStandingOrders stops the user's standing orders from requesting meals
+	NoShowActionLimitStandingOrders NoShowAction = "limit_standing_orders"
+	// NoShowActionRequireApproval holds the user's new requests until their line manager approves them
+	NoShowActionRequireApproval NoShowAction = "require_approval"
+)
+
+// IsValid reports whether the action is a known consequence
+func (a NoShowAction) IsValid() bool {
+	return a == NoShowActionWarn || a == NoShowActionLimitStandingOrders || a == NoShowActionRequireApproval
+}
+
+// IsRestriction reports whether the action limits the user for a while
+func (a NoShowAction) IsRestriction() bool {
+	return a == NoShowActionLimitStandingOrders || a == NoShowActionRequireApproval
+}
+
+// NoShowPolicy triggers an action for users with at least Threshold unexcused no-shows
+// at the meal events of the last WindowDays days
+type NoShowPolicy struct {
+	Base
+	Name          string       `json:"name" gorm:"not null" example:"Three strikes"`
+	Threshold     int          `json:"threshold" gorm:"not null" example:"3"`
+	WindowDays    int          `json:"window_days" gorm:"not null" example:"30"`
+	Action        NoShowAction `json:"action" gorm:"not null" enums:"warn,limit_standing_orders,require_approval"`
+	DurationDays  int          `json:"duration_days" example:"14"` // how long a restriction lasts; unused for warnings
+	IsActive      bool         `json:"is_active" gorm:"default:true"`
+	CreatedBy     uint         `json:"created_by"`
+	UpdatedBy     uint         `json:"updated_by"`
+	CreatedByUser User         `json:"-" gorm:"foreignKey:CreatedBy"`
+	UpdatedByUser User         `json:"-" gorm:"foreignKey:UpdatedBy"`
+}
+
+// NoShowPenalty records that a no-show policy was triggered for a user
+type NoShowPenalty struct {
+	Base
+	UserID     uint         `json:"user_id" gorm:"not null;index"`
+	PolicyID   uint         `json:"policy_id" gorm:"not null;index"`
+	Action     NoShowAction `json:"action" gorm:"not null" enums:"warn,limit_standing_orders,require_approval"`
+	NoShows    int          `json:"no_shows" gorm:"not null"` // unexcused no-shows within the window when triggered
+	StartsAt   time.Time    `json:"starts_at" gorm:"not null"`
+	EndsAt     *time.Time   `json:"ends_at"` // nil for warnings
+	LiftedAt   *time.Time   `json:"lifted_at"`
+	LiftedBy   *uint        `json:"lifted_by"`
+	LiftReason string       `json:"lift_reason"`
+	User       User         `json:"-" gorm:"foreignKey:UserID"`
+	Policy     NoShowPolicy `json:"policy" gorm:"foreignKey:PolicyID"`
+}
+
+// IsActiveAt reports whether the penalty restricts its user at t
+func (p *NoShowPenalty) IsActiveAt(t time.Time) bool {
+	if !p.Action.IsRestriction() || p.LiftedAt != nil || t.Before(p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || t.Before(*p.EndsAt)
+}
@@ -10,13 +10,15 @@ import (
 type Notification struct {
 	Base
 	UserID        uint             `json:"user_id" gorm:"not null" example:"1"`
-	Type          NotificationType `json:"type" gorm:"not null" example:"reminder" enums:"reminder,confirmation,admin-message,event-info"`
+	Type          NotificationType `json:"type" gorm:"not null" example:"reminder" enums:"reminder,confirmation,admin-message,event-info,event-change,digest"`
 	Payload       json.RawMessage  `json:"payload" gorm:"type:jsonb" swaggertype:"string" example:"{\"message\":\"Your meal request has been confirmed\"}"`
 	Message       string           `json:"message" gorm:"not null" example:"Your meal request has been confirmed."`
 	Read          bool             `json:"read" gorm:"not null;default:false" example:"false"`
 	Delivered     bool             `json:"delivered" gorm:"not null;default:false" example:"false"`
 	ReadAt        *time.Time       `json:"read_at" gorm:"default:null" example:"2025-04-24T10:15:00Z"`
 	DeliveredAt   *time.Time       `json:"delivered_at" gorm:"default:null" example:"2025-04-24T10:00:00Z"`
+	Archived      bool             `json:"archived" gorm:"not null;default:false" example:"false"`
+	ArchivedAt    *time.Time       `json:"archived_at" gorm:"default:null" example:"2025-04-25T09:00:00Z"`
 	CreatedBy     uint             `json:"created_by" example:"1"`
 	UpdatedBy     uint             `json:"updated_by" example:"1"`
 	User          User             `json:"user" gorm:"foreignKey:UserID" swaggerignore:"true"`
@@ -25,7 +27,7 @@ type Notification struct {
 }
 
 // NotificationType represents the type of notification
-// @Description Type of notification (reminder, confirmation, admin message, or event info)
+// @Description Type of notification (reminder, confirmation, admin message, event info, event change, or digest)
 type NotificationType string
 
 const (
@@ -33,4 +35,33 @@ const (
 	NotificationTypeConfirmation NotificationType = "confirmation"
 	NotificationTypeAdminMessage NotificationType = "admin-message"
 	NotificationTypeEventInfo    NotificationType = "event-info"
+	NotificationTypeEventChange  NotificationType = "event-change"
+	NotificationTypeDigest       NotificationType = "digest"
 )
+
+// DigestibleTypes lists the notification types that can be held back
+// and rolled up into a digest instead of being delivered individually
+var DigestibleTypes = []NotificationType{
+	NotificationTypeReminder,
+	NotificationTypeEventInfo,
+}
+
+// DigestFrequency represents how often a user receives notification digests
+type DigestFrequency string
+
+const (
+	DigestFrequencyNone   DigestFrequency = "none"
+	DigestFrequencyDaily  DigestFrequency = "daily"
+	DigestFrequencyWeekly DigestFrequency = "weekly"
+)
+
+// NotificationFilter describes the filters and cursor used to page through a user's notifications
+type NotificationFilter struct {
+	Types    []NotificationType
+	Read     *bool
+	Archived bool
+	From     *time.Time
+	To       *time.Time
+	Cursor   uint // only notifications with an ID lower than the cursor are returned
+	Limit    int  // page size
+}
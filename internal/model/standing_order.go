@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// StandingOrderFallback decides what a standing order does when its preferred menu set is not offered
+type StandingOrderFallback string
+
+const (
+	// StandingOrderFallbackSkip creates no request and tells the user to request manually
+	StandingOrderFallbackSkip StandingOrderFallback = "skip"
+	// StandingOrderFallbackAnySet requests the first menu set the event offers
+	StandingOrderFallbackAnySet StandingOrderFallback = "any_set"
+)
+
+// IsValid reports whether the fallback is a known rule
+func (f StandingOrderFallback) IsValid() bool {
+	return f == StandingOrderFallbackSkip || f == StandingOrderFallbackAnySet
+}
+
+// StandingOrder requests a meal automatically whenever a matching meal event is published
+type StandingOrder struct {
+	Base
+	UserID         uint                  `json:"user_id" gorm:"not null;index"`
+	MealType       MealType              `json:"meal_type" gorm:"not null" enums:"breakfast,lunch,snacks"`
+	Weekdays       string                `json:"weekdays" example:"mon,tue,wed,thu,fri"` // empty matches every day
+	EventAddressID uint                  `json:"event_address_id" gorm:"not null"`
+	MenuSetID      *uint                 `json:"menu_set_id"` // preferred menu set; nil takes the first one offered
+	Fallback       StandingOrderFallback `json:"fallback" gorm:"not null;default:'skip'" enums:"skip,any_set"`
+	IsActive       bool                  `json:"is_active" gorm:"default:true"`
+	Version        int                   `json:"version" gorm:"not null;default:1"` // optimistic locking version, raised on every change
+	CreatedBy      uint                  `json:"created_by"`
+	UpdatedBy      uint                  `json:"updated_by"`
+	EventAddress   EventAddress          `json:"event_address" gorm:"foreignKey:EventAddressID"`
+	MenuSet        *MenuSet              `json:"menu_set,omitempty" gorm:"foreignKey:MenuSetID"`
+	Items          []StandingOrderItem   `json:"items" gorm:"foreignKey:StandingOrderID"`
+}
+
+// StandingOrderItem deselects a menu item or adds a note to it in every request of a standing order
+type StandingOrderItem struct {
+	StandingOrderID uint      `json:"standing_order_id" gorm:"primaryKey;not null"`
+	MenuItemID      uint      `json:"menu_item_id" gorm:"primaryKey;not null"`
+	IsSelected      bool      `json:"is_selected" gorm:"not null;default:true"`
+	Notes           string    `json:"notes"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	MenuItem        MenuItem  `json:"menu_item" gorm:"foreignKey:MenuItemID"`
+}
+
+// StandingOrderPause suspends all standing orders of a user between two dates, e.g. for a vacation
+type StandingOrderPause struct {
+	Base
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	StartDate time.Time `json:"start_date" gorm:"not null" example:"2025-07-01T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" gorm:"not null" example:"2025-07-14T00:00:00Z"` // inclusive
+	Reason    string    `json:"reason" example:"Vacation"`
+}
+
+// Covers reports whether the pause includes a calendar date
+func (p *StandingOrderPause) Covers(date time.Time) bool {
+	return !date.Before(p.StartDate) && !date.After(p.EndDate)
+}
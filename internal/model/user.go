@@ -13,13 +13,20 @@ type User struct {
 	Email               string            `json:"email" gorm:"unique;not null"`
 	Department          string            `json:"department" gorm:"not null"`
 	Role                UserRole          `json:"role" gorm:"not null;default:'employee'"`
+	ManagerID           *uint             `json:"manager_id" gorm:"index"` // the employee's line manager, if any
 	IsActive            bool              `json:"is_active" gorm:"default:true"`
 	NotificationEnabled bool              `json:"notification_enabled" gorm:"default:true"`
+	DigestFrequency     DigestFrequency   `json:"digest_frequency" gorm:"not null;default:'none'"`
+	LastDigestAt        *time.Time        `json:"last_digest_at"`
+	TimeZone            string            `json:"time_zone" example:"Europe/Berlin"` // IANA zone; empty uses the organization's
+	Allergens           StringList        `json:"allergens" gorm:"type:jsonb;not null;default:'[]'" swaggertype:"array,string"`
+	DietaryTags         StringList        `json:"dietary_tags" gorm:"type:jsonb;not null;default:'[]'" swaggertype:"array,string"`
 	LastLoginAt         time.Time         `json:"last_login_at"`
 	CreatedBy           uint              `json:"created_by"`
 	UpdatedBy           uint              `json:"updated_by"`
 	CreatedByUser       *User             `json:"created_by_user" gorm:"foreignKey:CreatedBy"`
 	UpdatedByUser       *User             `json:"updated_by_user" gorm:"foreignKey:UpdatedBy"`
+	Manager             *User             `json:"manager,omitempty" gorm:"foreignKey:ManagerID"`
 	MealRequests        []MealRequest     `json:"meal_requests" gorm:"foreignKey:UserID"`
 	MenuItemComments    []MenuItemComment `json:"menu_item_comments" gorm:"foreignKey:UserID"`
 	Notifications       []Notification    `json:"notifications" gorm:"foreignKey:UserID"`
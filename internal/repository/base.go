@@ -5,11 +5,16 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Common repository errors
 var (
 	ErrNotFound = errors.New("record not found")
+	// ErrStatusChanged is returned when a record's status changed between reading and updating it
+	ErrStatusChanged = errors.New("status changed concurrently")
+	// ErrVersionConflict is returned when a record was changed or deleted since the given version was read
+	ErrVersionConflict = errors.New("version changed concurrently")
 )
 
 // baseRepository implements common CRUD operations
@@ -63,11 +68,30 @@ func (r *baseRepository[T]) FindActive(ctx context.Context, conditions map[strin
 	return entities, nil
 }
 
-// Update updates an entity
+// Update saves every field of an entity without checking its version. Repositories of
+// versioned entities override it with saveNextVersion.
 func (r *baseRepository[T]) Update(ctx context.Context, entity *T) error {
 	return r.db.WithContext(ctx).Save(entity).Error
 }
 
+// UpdateVersion saves an entity if its stored version still equals version.
+// The entity must already carry the next version.
+func (r *baseRepository[T]) UpdateVersion(ctx context.Context, entity *T, version int) error {
+	return saveVersion(r.db.WithContext(ctx), entity, version)
+}
+
+// DeleteVersion deletes an entity if its stored version still equals version
+func (r *baseRepository[T]) DeleteVersion(ctx context.Context, entity *T, version int) error {
+	result := r.db.WithContext(ctx).Where("version = ?", version).Delete(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
 // Delete soft deletes an entity
 func (r *baseRepository[T]) Delete(ctx context.Context, entity *T) error {
 	return r.db.WithContext(ctx).Delete(entity).Error
@@ -84,3 +108,41 @@ func (r *baseRepository[T]) WithTransaction(ctx context.Context, fn func(tx *gor
 		return fn(tx)
 	})
 }
+
+// saveNextVersion saves an entity that was read at *version and raises the version. It fails
+// with ErrVersionConflict, leaving the version as it was, if the stored entity changed since.
+func saveNextVersion(tx *gorm.DB, entity interface{}, version *int) error {
+	read := *version
+	*version = read + 1
+	if err := saveVersion(tx, entity, read); err != nil {
+		*version = read
+		return err
+	}
+	return nil
+}
+
+// createOrSaveNextVersion creates an entity without an ID yet, and saves any other one with
+// saveNextVersion. Associations are left alone either way.
+func createOrSaveNextVersion(tx *gorm.DB, entity interface{}, id uint, version *int) error {
+	if id == 0 {
+		return tx.Omit(clause.Associations).Create(entity).Error
+	}
+	return saveNextVersion(tx, entity, version)
+}
+
+// saveVersion writes every column of an entity, but not its associations, if its stored version
+// still equals version. Unlike Save it never falls back to an insert when no row matches.
+func saveVersion(tx *gorm.DB, entity interface{}, version int) error {
+	result := tx.Model(entity).
+		Where("version = ?", version).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
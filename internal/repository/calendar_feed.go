@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+)
+
+// calendarFeedRepository implements CalendarFeedRepository interface
+type calendarFeedRepository struct {
+	*baseRepository[model.CalendarFeed]
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new instance of CalendarFeedRepository
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{
+		baseRepository: NewBaseRepository[model.CalendarFeed](db),
+		db:             db,
+	}
+}
+
+// FindByToken finds the feed with a token unless it has been revoked
+func (r *calendarFeedRepository) FindByToken(ctx context.Context, token string) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("token = ? AND revoked_at IS NULL", token).
+		First(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindActiveByUserID finds the feeds of a user that have not been revoked
+func (r *calendarFeedRepository) FindActiveByUserID(ctx context.Context, userID uint) ([]model.CalendarFeed, error) {
+	var feeds []model.CalendarFeed
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("id ASC").
+		Find(&feeds).Error
+	if err != nil {
+		return nil, err
+	}
+	return feeds, nil
+}
+
+// Replace revokes the user's active feeds of the same kind and creates the new feed
+func (r *calendarFeedRepository) Replace(ctx context.Context, feed *model.CalendarFeed) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.CalendarFeed{}).
+			Where("user_id = ? AND kind = ? AND revoked_at IS NULL", feed.UserID, feed.Kind).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(feed).Error
+	})
+}
+
+// MarkAccessed records when a feed was last fetched
+func (r *calendarFeedRepository) MarkAccessed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.CalendarFeed{}).
+		Where("id = ?", id).
+		Update("last_accessed_at", at).Error
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// guestRequestRepository implements GuestRequestRepository interface
+type guestRequestRepository struct {
+	*baseRepository[model.GuestRequest]
+	db *gorm.DB
+}
+
+// NewGuestRequestRepository creates a new instance of GuestRequestRepository
+func NewGuestRequestRepository(db *gorm.DB) GuestRequestRepository {
+	return &guestRequestRepository{
+		baseRepository: NewBaseRepository[model.GuestRequest](db),
+		db:             db,
+	}
+}
+
+// FindByID finds a guest request with its host, choice and items
+func (r *guestRequestRepository) FindByID(ctx context.Context, id uint) (*model.GuestRequest, error) {
+	var guest model.GuestRequest
+	err := r.db.WithContext(ctx).
+		Preload("Host").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Preload("Items").
+		Preload("Items.MenuItem").
+		First(&guest, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &guest, nil
+}
+
+// FindByMealEventID finds the guest requests of a meal event
+func (r *guestRequestRepository) FindByMealEventID(ctx context.Context, mealEventID uint) ([]model.GuestRequest, error) {
+	var guests []model.GuestRequest
+	err := r.db.WithContext(ctx).
+		Preload("Host").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Preload("Items").
+		Where("meal_event_id = ?", mealEventID).
+		Order("id ASC").
+		Find(&guests).Error
+	if err != nil {
+		return nil, err
+	}
+	return guests, nil
+}
+
+// FindByHostID finds the guest requests an employee made
+func (r *guestRequestRepository) FindByHostID(ctx context.Context, hostUserID uint) ([]model.GuestRequest, error) {
+	var guests []model.GuestRequest
+	err := r.db.WithContext(ctx).
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Preload("Items").
+		Where("host_user_id = ?", hostUserID).
+		Order("id DESC").
+		Find(&guests).Error
+	if err != nil {
+		return nil, err
+	}
+	return guests, nil
+}
+
+// FindByStatus finds the guest requests with a status, oldest first
+func (r *guestRequestRepository) FindByStatus(ctx context.Context, status model.GuestRequestStatus) ([]model.GuestRequest, error) {
+	var guests []model.GuestRequest
+	err := r.db.WithContext(ctx).
+		Preload("Host").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Where("status = ?", status).
+		Order("id ASC").
+		Find(&guests).Error
+	if err != nil {
+		return nil, err
+	}
+	return guests, nil
+}
+
+// FindByDateRange finds the guest requests of the meal events within a date range
+func (r *guestRequestRepository) FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.GuestRequest, error) {
+	var guests []model.GuestRequest
+	err := r.db.WithContext(ctx).
+		Joins("MealEvent").
+		Preload("MenuSet").
+		Preload("Items").
+		Where("\"MealEvent\".event_date BETWEEN ? AND ?", startDate, endDate).
+		Find(&guests).Error
+	if err != nil {
+		return nil, err
+	}
+	return guests, nil
+}
+
+// CountApprovedForDepartment counts the approved guests hosted by a department's employees
+// at meal events within a date range
+func (r *guestRequestRepository) CountApprovedForDepartment(ctx context.Context, department string, startDate, endDate time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.GuestRequest{}).
+		Joins("JOIN users ON users.id = guest_requests.host_user_id").
+		Joins("JOIN meal_events ON meal_events.id = guest_requests.meal_event_id").
+		Where("users.department = ? AND guest_requests.status = ?", department, model.GuestRequestStatusApproved).
+		Where("meal_events.event_date BETWEEN ? AND ?", startDate, endDate).
+		Count(&count).Error
+	return count, err
+}
+
+// SaveWithItems creates or updates a guest request and replaces its items. Updates raise the
+// version and fail with ErrVersionConflict if the request changed since it was read.
+func (r *guestRequestRepository) SaveWithItems(ctx context.Context, guest *model.GuestRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		items := guest.Items
+		if err := createOrSaveNextVersion(tx, guest, guest.ID, &guest.Version); err != nil {
+			return err
+		}
+
+		if err := tx.Where("guest_request_id = ?", guest.ID).Delete(&model.GuestRequestItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		for i := range items {
+			items[i].ID = 0
+			items[i].GuestRequestID = guest.ID
+		}
+		return tx.Omit(clause.Associations).Create(&items).Error
+	})
+}
+
+// UpdateStatus changes the status of a guest request and raises its version unless it changed since it was read
+func (r *guestRequestRepository) UpdateStatus(ctx context.Context, guest *model.GuestRequest, from model.GuestRequestStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.GuestRequest{}).
+		Where("id = ? AND status = ? AND version = ?", guest.ID, from, guest.Version).
+		Updates(map[string]interface{}{
+			"status":      guest.Status,
+			"reviewed_by": guest.ReviewedBy,
+			"review_note": guest.ReviewNote,
+			"version":     gorm.Expr("version + 1"),
+			"updated_by":  guest.UpdatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+	guest.Version++
+	return nil
+}
+
+// FindQuotas finds the guest quotas of all departments
+func (r *guestRequestRepository) FindQuotas(ctx context.Context) ([]model.DepartmentGuestQuota, error) {
+	var quotas []model.DepartmentGuestQuota
+	if err := r.db.WithContext(ctx).Order("department ASC").Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	return quotas, nil
+}
+
+// FindQuota finds the guest quota of a department
+func (r *guestRequestRepository) FindQuota(ctx context.Context, department string) (*model.DepartmentGuestQuota, error) {
+	var quota model.DepartmentGuestQuota
+	if err := r.db.WithContext(ctx).Where("department = ?", department).First(&quota).Error; err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// SaveQuota creates or updates a department's guest quota
+func (r *guestRequestRepository) SaveQuota(ctx context.Context, quota *model.DepartmentGuestQuota) error {
+	return r.db.WithContext(ctx).Save(quota).Error
+}
+
+// DeleteQuota removes a department's guest quota
+func (r *guestRequestRepository) DeleteQuota(ctx context.Context, quota *model.DepartmentGuestQuota) error {
+	return r.db.WithContext(ctx).Delete(quota).Error
+}
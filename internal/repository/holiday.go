@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+)
+
+// holidayRepository implements HolidayRepository interface
+type holidayRepository struct {
+	*baseRepository[model.Holiday]
+	db *gorm.DB
+}
+
+// NewHolidayRepository creates a new instance of HolidayRepository
+func NewHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &holidayRepository{
+		baseRepository: NewBaseRepository[model.Holiday](db),
+		db:             db,
+	}
+}
+
+// FindByDateRange finds holidays falling within a date range
+func (r *holidayRepository) FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	err := r.db.WithContext(ctx).
+		Preload("EventAddress").
+		Where("date BETWEEN ? AND ?", startDate, endDate).
+		Order("date ASC").
+		Find(&holidays).Error
+	if err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// AddressExists reports whether an event address exists
+func (r *holidayRepository) AddressExists(ctx context.Context, addressID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.EventAddress{}).
+		Where("id = ?", addressID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -24,6 +24,11 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
 	FindByEmployeeID(ctx context.Context, employeeID int) (*model.User, error)
+	FindByDigestFrequency(ctx context.Context, frequency model.DigestFrequency) ([]model.User, error)
+	UpdateLastDigestAt(ctx context.Context, userID uint, at time.Time) error
+	FindReports(ctx context.Context, managerID uint) ([]model.User, error)
+	UpdateManager(ctx context.Context, userID uint, managerID *uint, updatedBy uint) error
+	UpdateRole(ctx context.Context, userID uint, role model.UserRole, updatedBy uint) error
 }
 
 // MealEventRepository defines meal event-specific operations
@@ -40,8 +45,112 @@ type MealEventRepository interface {
 	FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealEvent, error)
 	AddMenuSetToEvent(ctx context.Context, MealEventSet *model.MealEventSet) error
 	UpdateMenuSetInEvent(ctx context.Context, MealEventSet *model.MealEventSet) error
+	UpdateSurplus(ctx context.Context, mealEventID uint, menuSetID uint, surplus int, updatedBy uint) error
 	RemoveMenuSetFromEvent(ctx context.Context, mealEventID uint, menuSetID uint) error
 	FindMenuSetsByEventID(ctx context.Context, mealEventID uint) ([]model.MealEventSet, error)
+	FindBySeriesID(ctx context.Context, seriesID uint, from time.Time) ([]model.MealEvent, error)
+	UpdateStatus(ctx context.Context, meal *model.MealEvent, from model.MealEventStatus, transition *model.MealEventTransition) error
+	FindTransitions(ctx context.Context, mealEventID uint) ([]model.MealEventTransition, error)
+	UpdateWithRevision(ctx context.Context, meal *model.MealEvent, version int, revision *model.MealEventRevision) error
+	DeleteVersion(ctx context.Context, meal *model.MealEvent, version int) error
+	FindRevisions(ctx context.Context, mealEventID uint) ([]model.MealEventRevision, error)
+	FindAddressesByIDs(ctx context.Context, ids []uint) ([]model.EventAddress, error)
+	FindDueForClosing(ctx context.Context, now time.Time) ([]model.MealEvent, error)
+	FindOverlapping(ctx context.Context, mealType model.MealType, addressIDs []uint, start, end time.Time, excludeID uint) ([]model.MealEvent, error)
+	Transaction(ctx context.Context, fn func(repo MealEventRepository) error) error
+}
+
+// MealEventSeriesRepository defines recurring meal event series operations
+type MealEventSeriesRepository interface {
+	BaseRepository[model.MealEventSeries]
+	FindActiveSeries(ctx context.Context) ([]model.MealEventSeries, error)
+	UpdateGeneratedUntil(ctx context.Context, seriesID uint, until time.Time) error
+	TruncateSeries(ctx context.Context, seriesID uint, until time.Time, updatedBy uint) error
+	Deactivate(ctx context.Context, seriesID uint, version int, updatedBy uint) error
+	Transaction(ctx context.Context, fn func(seriesRepo MealEventSeriesRepository, mealRepo MealEventRepository) error) error
+}
+
+// MealEventTemplateRepository defines meal event template operations
+type MealEventTemplateRepository interface {
+	BaseRepository[model.MealEventTemplate]
+	FindByName(ctx context.Context, name string) (*model.MealEventTemplate, error)
+	DeleteVersion(ctx context.Context, template *model.MealEventTemplate, version int) error
+}
+
+// MealTypeDefaultRepository defines per meal type default operations
+type MealTypeDefaultRepository interface {
+	BaseRepository[model.MealTypeDefault]
+	FindByMealType(ctx context.Context, mealType model.MealType) (*model.MealTypeDefault, error)
+}
+
+// CalendarFeedRepository defines calendar feed operations
+type CalendarFeedRepository interface {
+	BaseRepository[model.CalendarFeed]
+	FindByToken(ctx context.Context, token string) (*model.CalendarFeed, error)
+	FindActiveByUserID(ctx context.Context, userID uint) ([]model.CalendarFeed, error)
+	Replace(ctx context.Context, feed *model.CalendarFeed) error
+	MarkAccessed(ctx context.Context, id uint, at time.Time) error
+}
+
+// StandingOrderRepository defines standing order and pause operations
+type StandingOrderRepository interface {
+	BaseRepository[model.StandingOrder]
+	FindByUserID(ctx context.Context, userID uint) ([]model.StandingOrder, error)
+	FindActiveByMealType(ctx context.Context, mealType model.MealType) ([]model.StandingOrder, error)
+	SaveWithItems(ctx context.Context, order *model.StandingOrder) error
+	DeleteVersion(ctx context.Context, order *model.StandingOrder, version int) error
+	FindPausesByUserID(ctx context.Context, userID uint) ([]model.StandingOrderPause, error)
+	FindPausesOn(ctx context.Context, date time.Time) ([]model.StandingOrderPause, error)
+	FindPauseByID(ctx context.Context, id uint) (*model.StandingOrderPause, error)
+	CreatePause(ctx context.Context, pause *model.StandingOrderPause) error
+	DeletePause(ctx context.Context, pause *model.StandingOrderPause) error
+}
+
+// NoShowPolicyRepository defines no-show policy and penalty operations
+type NoShowPolicyRepository interface {
+	BaseRepository[model.NoShowPolicy]
+	CountNoShowsSince(ctx context.Context, since time.Time) (map[uint]int, error)
+	FindNoShowsByUserID(ctx context.Context, userID uint) ([]model.MealRequest, error)
+	CreatePenalty(ctx context.Context, penalty *model.NoShowPenalty) error
+	UpdatePenalty(ctx context.Context, penalty *model.NoShowPenalty) error
+	FindPenaltyByID(ctx context.Context, id uint) (*model.NoShowPenalty, error)
+	FindPenaltiesByUserID(ctx context.Context, userID uint) ([]model.NoShowPenalty, error)
+	HasPenaltySince(ctx context.Context, policyID, userID uint, since time.Time) (bool, error)
+	FindRestrictedUserIDs(ctx context.Context, action model.NoShowAction, at time.Time) ([]uint, error)
+}
+
+// MealTransferRepository defines meal transfer and surplus pool operations
+type MealTransferRepository interface {
+	BaseRepository[model.MealTransfer]
+	FindByRequestID(ctx context.Context, requestID uint) ([]model.MealTransfer, error)
+	FindOpenByRequestID(ctx context.Context, requestID uint) (*model.MealTransfer, error)
+	FindByUserID(ctx context.Context, userID uint) ([]model.MealTransfer, error)
+	FindOpenPool(ctx context.Context, endingAfter time.Time) ([]model.MealTransfer, error)
+	UpdateStatus(ctx context.Context, transfer *model.MealTransfer, from model.MealTransferStatus) error
+	CompleteTransfer(ctx context.Context, transfer *model.MealTransfer, toUserID uint) error
+}
+
+// GuestRequestRepository defines guest meal request and department quota operations
+type GuestRequestRepository interface {
+	BaseRepository[model.GuestRequest]
+	FindByMealEventID(ctx context.Context, mealEventID uint) ([]model.GuestRequest, error)
+	FindByHostID(ctx context.Context, hostUserID uint) ([]model.GuestRequest, error)
+	FindByStatus(ctx context.Context, status model.GuestRequestStatus) ([]model.GuestRequest, error)
+	FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.GuestRequest, error)
+	CountApprovedForDepartment(ctx context.Context, department string, startDate, endDate time.Time) (int64, error)
+	SaveWithItems(ctx context.Context, guest *model.GuestRequest) error
+	UpdateStatus(ctx context.Context, guest *model.GuestRequest, from model.GuestRequestStatus) error
+	FindQuotas(ctx context.Context) ([]model.DepartmentGuestQuota, error)
+	FindQuota(ctx context.Context, department string) (*model.DepartmentGuestQuota, error)
+	SaveQuota(ctx context.Context, quota *model.DepartmentGuestQuota) error
+	DeleteQuota(ctx context.Context, quota *model.DepartmentGuestQuota) error
+}
+
+// HolidayRepository defines holiday calendar operations
+type HolidayRepository interface {
+	BaseRepository[model.Holiday]
+	FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error)
+	AddressExists(ctx context.Context, addressID uint) (bool, error)
 }
 
 // EventAddressRepository defines the interface for event address repository
@@ -69,8 +178,12 @@ type MenuSetRepository interface {
 	FindByID(ctx context.Context, id uint) (*model.MenuSet, error)
 	FindAll(ctx context.Context) ([]model.MenuSet, error)
 	FindActive(ctx context.Context, conditions map[string]interface{}) ([]model.MenuSet, error)
+	FindWithItems(ctx context.Context, id uint) (*model.MenuSet, error)
+	FindActiveWithItems(ctx context.Context) ([]model.MenuSet, error)
 	Update(ctx context.Context, menuSet *model.MenuSet) error
+	UpdateVersion(ctx context.Context, menuSet *model.MenuSet, version int) error
 	Delete(ctx context.Context, menuSet *model.MenuSet) error
+	DeleteVersion(ctx context.Context, menuSet *model.MenuSet, version int) error
 	HardDelete(ctx context.Context, menuSet *model.MenuSet) error
 	AddMenuItem(ctx context.Context, setItem *model.MenuSetItem) error
 	RemoveMenuItem(ctx context.Context, setItem *model.MenuSetItem) error
@@ -84,7 +197,9 @@ type MenuItemRepository interface {
 	FindAll(ctx context.Context) ([]model.MenuItem, error)
 	FindActive(ctx context.Context, conditions map[string]interface{}) ([]model.MenuItem, error)
 	Update(ctx context.Context, item *model.MenuItem) error
+	UpdateVersion(ctx context.Context, item *model.MenuItem, version int) error
 	Delete(ctx context.Context, item *model.MenuItem) error
+	DeleteVersion(ctx context.Context, item *model.MenuItem, version int) error
 	HardDelete(ctx context.Context, item *model.MenuItem) error
 }
 
@@ -98,6 +213,13 @@ type NotificationRepository interface {
 	MarkAsDelivered(ctx context.Context, id uint) error
 	FindUnreadByUserID(ctx context.Context, userID uint) ([]model.Notification, error)
 	FindByType(ctx context.Context, userID uint, notificationType model.NotificationType) ([]model.Notification, error)
+	FindUndeliveredByTypes(ctx context.Context, userID uint, types []model.NotificationType, before time.Time) ([]model.Notification, error)
+	MarkManyAsDelivered(ctx context.Context, ids []uint) error
+	FindPageByUserID(ctx context.Context, userID uint, filter model.NotificationFilter) ([]model.Notification, error)
+	MarkAllAsRead(ctx context.Context, userID uint) (int64, error)
+	DeleteByIDs(ctx context.Context, userID uint, ids []uint) (int64, error)
+	SetArchived(ctx context.Context, userID uint, ids []uint, archived bool) (int64, error)
+	PurgeReadBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 // MealRequestRepository handles meal request related database operations
@@ -114,15 +236,30 @@ type MealRequestRepository interface {
 	AddRequestItem(ctx context.Context, item *model.MealRequestItem) error
 	RemoveRequestItem(ctx context.Context, item *model.MealRequestItem) error
 	FindRequestItems(ctx context.Context, requestID uint) ([]model.MealRequestItem, error)
+	SaveWithItems(ctx context.Context, request *model.MealRequest, items []model.MealRequestItem) error
 	// Additional methods
 	FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealRequest, error)
 	FindPendingRequests(ctx context.Context) ([]model.MealRequest, error)
 	FindApprovedRequests(ctx context.Context) ([]model.MealRequest, error)
 	FindRejectedRequests(ctx context.Context) ([]model.MealRequest, error)
-	UpdateRequestStatus(ctx context.Context, requestID uint, status model.RequestStatus) error
+	UpdateRequestStatus(ctx context.Context, request *model.MealRequest, from model.RequestStatus, transition *model.MealRequestTransition) error
+	FindTransitions(ctx context.Context, requestID uint) ([]model.MealRequestTransition, error)
 	CountByMealEventID(ctx context.Context, mealEventID uint) (int64, error)
 	FindByMenuSetID(ctx context.Context, menuSetID uint) ([]model.MealRequest, error)
 	FindWithDetails(ctx context.Context, requestID uint) (*model.MealRequest, error)
+	FindByUserIDAndEventDateRange(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.MealRequest, error)
+	MarkNeedsAttention(ctx context.Context, requestID uint, note string) error
+	Transaction(ctx context.Context, fn func(repo MealRequestRepository) error) error
+	LockMealEvent(ctx context.Context, mealEventID uint) (*model.MealEvent, error)
+	CreatePickup(ctx context.Context, pickup *model.MealPickup) error
+	FindPickup(ctx context.Context, requestID uint) (*model.MealPickup, error)
+	FindPickupsByMealEventID(ctx context.Context, mealEventID uint) ([]model.MealPickup, error)
+	HasPickups(ctx context.Context, mealEventID uint) (bool, error)
+	FindUnclaimed(ctx context.Context, endedBefore time.Time) ([]model.MealRequest, error)
+	FindCheckedInByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealRequest, error)
+	FindAwaitingApproval(ctx context.Context, userIDs []uint) ([]model.MealRequest, error)
+	ClearAwaitingApproval(ctx context.Context, requestID uint, updatedBy uint) error
+	ExcuseNoShow(ctx context.Context, requestID uint, reason string, updatedBy uint) error
 }
 
 // MenuItemCommentRepository handles menu item comment related database operations
@@ -132,7 +269,9 @@ type MenuItemCommentRepository interface {
 	FindAll(ctx context.Context) ([]model.MenuItemComment, error)
 	FindActive(ctx context.Context, conditions map[string]interface{}) ([]model.MenuItemComment, error)
 	Update(ctx context.Context, comment *model.MenuItemComment) error
+	UpdateVersion(ctx context.Context, comment *model.MenuItemComment, version int) error
 	Delete(ctx context.Context, comment *model.MenuItemComment) error
+	DeleteVersion(ctx context.Context, comment *model.MenuItemComment, version int) error
 	HardDelete(ctx context.Context, comment *model.MenuItemComment) error
 	FindByMealEventID(ctx context.Context, mealEventID uint) ([]model.MenuItemComment, error)
 	FindByUserID(ctx context.Context, userID uint) ([]model.MenuItemComment, error)
@@ -143,4 +282,5 @@ type MenuItemCommentRepository interface {
 	CountByMealEventID(ctx context.Context, mealEventID uint) (int64, error)
 	FindWithUserDetails(ctx context.Context, commentID uint) (*model.MenuItemComment, error)
 	FindReplies(ctx context.Context, parentID uint) ([]model.MenuItemComment, error) // Added for replies
+	FindRepliesToUserSince(ctx context.Context, userID uint, since time.Time) ([]model.MenuItemComment, error)
 }
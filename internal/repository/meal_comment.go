@@ -42,9 +42,15 @@ func (r *menuItemCommentRepository) FindActive(ctx context.Context, conditions m
 	return r.baseRepository.FindActive(ctx, conditions)
 }
 
-// Update updates a menu item comment
+// Update saves a menu item comment and raises its version. It fails with ErrVersionConflict if the
+// comment changed since it was read.
 func (r *menuItemCommentRepository) Update(ctx context.Context, comment *model.MenuItemComment) error {
-	return r.baseRepository.Update(ctx, comment)
+	return saveNextVersion(r.db.WithContext(ctx), comment, &comment.Version)
+}
+
+// UpdateVersion updates a menu item comment if it is still at the given version
+func (r *menuItemCommentRepository) UpdateVersion(ctx context.Context, comment *model.MenuItemComment, version int) error {
+	return r.baseRepository.UpdateVersion(ctx, comment, version)
 }
 
 // Delete soft deletes a menu item comment
@@ -52,6 +58,11 @@ func (r *menuItemCommentRepository) Delete(ctx context.Context, comment *model.M
 	return r.baseRepository.Delete(ctx, comment)
 }
 
+// DeleteVersion soft deletes a menu item comment if it is still at the given version
+func (r *menuItemCommentRepository) DeleteVersion(ctx context.Context, comment *model.MenuItemComment, version int) error {
+	return r.baseRepository.DeleteVersion(ctx, comment, version)
+}
+
 // HardDelete permanently deletes a menu item comment
 func (r *menuItemCommentRepository) HardDelete(ctx context.Context, comment *model.MenuItemComment) error {
 	return r.baseRepository.HardDelete(ctx, comment)
@@ -146,3 +157,20 @@ func (r *menuItemCommentRepository) FindReplies(ctx context.Context, parentID ui
 	}
 	return replies, nil
 }
+
+// FindRepliesToUserSince finds replies posted by others to a user's comments after a point in time
+func (r *menuItemCommentRepository) FindRepliesToUserSince(ctx context.Context, userID uint, since time.Time) ([]model.MenuItemComment, error) {
+	var replies []model.MenuItemComment
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("MenuItem").
+		Joins("JOIN menu_item_comments AS parents ON parents.id = menu_item_comments.parent_id").
+		Where("parents.user_id = ? AND menu_item_comments.user_id <> ?", userID, userID).
+		Where("menu_item_comments.created_at > ?", since).
+		Order("menu_item_comments.created_at ASC").
+		Find(&replies).Error
+	if err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
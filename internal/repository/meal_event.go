@@ -6,6 +6,7 @@ import (
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // mealEventRepository implements MealEventRepository interface
@@ -121,9 +122,10 @@ func (r *mealEventRepository) FindActive(ctx context.Context, conditions map[str
 	return r.baseRepository.FindActive(ctx, conditions)
 }
 
-// Update updates a meal event
+// Update saves a meal event and raises its version. It fails with ErrVersionConflict if the
+// event changed since it was read.
 func (r *mealEventRepository) Update(ctx context.Context, meal *model.MealEvent) error {
-	return r.baseRepository.Update(ctx, meal)
+	return saveNextVersion(r.db.WithContext(ctx), meal, &meal.Version)
 }
 
 // Delete deletes a meal event (soft delete)
@@ -131,6 +133,11 @@ func (r *mealEventRepository) Delete(ctx context.Context, meal *model.MealEvent)
 	return r.baseRepository.Delete(ctx, meal)
 }
 
+// DeleteVersion deletes a meal event if it is still at the given version
+func (r *mealEventRepository) DeleteVersion(ctx context.Context, meal *model.MealEvent, version int) error {
+	return r.baseRepository.DeleteVersion(ctx, meal, version)
+}
+
 // HardDelete permanently deletes a meal event
 func (r *mealEventRepository) HardDelete(ctx context.Context, meal *model.MealEvent) error {
 	return r.baseRepository.HardDelete(ctx, meal)
@@ -156,29 +163,69 @@ func (r *mealEventRepository) FindUpcomingAndActive(ctx context.Context) ([]mode
 	return meals, nil
 }
 
+// Transaction runs fn with a repository whose operations all belong to one database transaction.
+// The transaction is rolled back if fn returns an error.
+func (r *mealEventRepository) Transaction(ctx context.Context, fn func(repo MealEventRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewMealEventRepository(tx))
+	})
+}
+
 // AddMenuSetToEvent associates a menu set with a meal event
 func (r *mealEventRepository) AddMenuSetToEvent(ctx context.Context, MealEventSet *model.MealEventSet) error {
-	return r.db.WithContext(ctx).Create(MealEventSet).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(MealEventSet).Error; err != nil {
+			return err
+		}
+		return raiseVersion(tx, MealEventSet.MealEventID)
+	})
 }
 
 // UpdateMenuSetInEvent updates the menu set association details in a meal event
 func (r *mealEventRepository) UpdateMenuSetInEvent(ctx context.Context, MealEventSet *model.MealEventSet) error {
-	return r.db.WithContext(ctx).
-		Model(&model.MealEventSet{}).
-		Where("meal_event_id = ? AND menu_set_id = ?", MealEventSet.MealEventID, MealEventSet.MenuSetID).
-		Updates(map[string]interface{}{
-			"label":      MealEventSet.Label,
-			"note":       MealEventSet.Note,
-			"updated_by": MealEventSet.UpdatedBy,
-			"updated_at": time.Now(),
-		}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.MealEventSet{}).
+			Where("meal_event_id = ? AND menu_set_id = ?", MealEventSet.MealEventID, MealEventSet.MenuSetID).
+			Updates(map[string]interface{}{
+				"label":      MealEventSet.Label,
+				"note":       MealEventSet.Note,
+				"updated_by": MealEventSet.UpdatedBy,
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return raiseVersion(tx, MealEventSet.MealEventID)
+	})
+}
+
+// UpdateSurplus records the spare portions of a menu set in a meal event
+func (r *mealEventRepository) UpdateSurplus(ctx context.Context, mealEventID uint, menuSetID uint, surplus int, updatedBy uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.MealEventSet{}).
+			Where("meal_event_id = ? AND menu_set_id = ?", mealEventID, menuSetID).
+			Updates(map[string]interface{}{
+				"surplus":    surplus,
+				"updated_by": updatedBy,
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return raiseVersion(tx, mealEventID)
+	})
 }
 
 // RemoveMenuSetFromEvent removes a menu set association from a meal event
 func (r *mealEventRepository) RemoveMenuSetFromEvent(ctx context.Context, mealEventID uint, menuSetID uint) error {
-	return r.db.WithContext(ctx).
-		Where("meal_event_id = ? AND menu_set_id = ?", mealEventID, menuSetID).
-		Delete(&model.MealEventSet{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("meal_event_id = ? AND menu_set_id = ?", mealEventID, menuSetID).
+			Delete(&model.MealEventSet{}).Error
+		if err != nil {
+			return err
+		}
+		return raiseVersion(tx, mealEventID)
+	})
 }
 
 // FindMenuSetsByEventID finds all menu sets associated with a meal event
@@ -210,3 +257,179 @@ func (r *mealEventRepository) FindByDateRange(ctx context.Context, startDate, en
 	}
 	return meals, nil
 }
+
+// FindBySeriesID finds the events of a series whose scheduled occurrence is at or after the given time
+func (r *mealEventRepository) FindBySeriesID(ctx context.Context, seriesID uint, from time.Time) ([]model.MealEvent, error) {
+	var meals []model.MealEvent
+	err := r.db.WithContext(ctx).
+		Preload("MealRequests").
+		Where("series_id = ? AND series_occurrence >= ?", seriesID, from).
+		Order("series_occurrence ASC").
+		Find(&meals).Error
+	if err != nil {
+		return nil, err
+	}
+	return meals, nil
+}
+
+// UpdateStatus moves a meal event from one status to another and records the transition.
+// It fails with ErrStatusChanged if the event is no longer in the expected status.
+func (r *mealEventRepository) UpdateStatus(ctx context.Context, meal *model.MealEvent, from model.MealEventStatus, transition *model.MealEventTransition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.MealEvent{}).
+			Where("id = ? AND status = ?", meal.ID, from).
+			Updates(map[string]interface{}{
+				"status":       meal.Status,
+				"is_active":    meal.IsActive,
+				"confirmed_at": meal.ConfirmedAt,
+				"sequence":     meal.Sequence,
+				"version":      gorm.Expr("version + 1"),
+				"updated_by":   meal.UpdatedBy,
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+		meal.Version++
+
+		return tx.Create(transition).Error
+	})
+}
+
+// FindTransitions finds the status history of a meal event, oldest first
+func (r *mealEventRepository) FindTransitions(ctx context.Context, mealEventID uint) ([]model.MealEventTransition, error) {
+	var transitions []model.MealEventTransition
+	err := r.db.WithContext(ctx).
+		Preload("Actor").
+		Where("meal_event_id = ?", mealEventID).
+		Order("id ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// UpdateWithRevision saves a meal event, replaces its menu sets and addresses and records the revision.
+// Menu sets that stay on the event keep their surplus, which is only changed through UpdateSurplus.
+// A nil revision saves the event without adding to its history. It fails with ErrVersionConflict
+// unless the event is still at the given version, and raises the version otherwise.
+func (r *mealEventRepository) UpdateWithRevision(ctx context.Context, meal *model.MealEvent, version int, revision *model.MealEventRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		meal.Version = version + 1
+		if err := saveVersion(tx, meal, version); err != nil {
+			meal.Version = version
+			return err
+		}
+
+		var existing []model.MealEventSet
+		if err := tx.Where("meal_event_id = ?", meal.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		surplus := make(map[uint]int, len(existing))
+		for _, set := range existing {
+			surplus[set.MenuSetID] = set.Surplus
+		}
+
+		if err := tx.Where("meal_event_id = ?", meal.ID).Delete(&model.MealEventSet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("meal_event_id = ?", meal.ID).Delete(&model.MealEventAddress{}).Error; err != nil {
+			return err
+		}
+
+		for i := range meal.MenuSets {
+			meal.MenuSets[i].MealEventID = meal.ID
+			meal.MenuSets[i].Surplus = surplus[meal.MenuSets[i].MenuSetID]
+		}
+		for i := range meal.Addresses {
+			meal.Addresses[i].ID = 0
+			meal.Addresses[i].MealEventID = meal.ID
+		}
+
+		if len(meal.MenuSets) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&meal.MenuSets).Error; err != nil {
+				return err
+			}
+		}
+		if len(meal.Addresses) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&meal.Addresses).Error; err != nil {
+				return err
+			}
+		}
+
+		if revision == nil {
+			return nil
+		}
+		return tx.Create(revision).Error
+	})
+}
+
+// FindRevisions finds the edit history of a meal event, oldest first
+func (r *mealEventRepository) FindRevisions(ctx context.Context, mealEventID uint) ([]model.MealEventRevision, error) {
+	var revisions []model.MealEventRevision
+	err := r.db.WithContext(ctx).
+		Preload("Actor").
+		Where("meal_event_id = ?", mealEventID).
+		Order("id ASC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// FindAddressesByIDs finds the event addresses with the given IDs
+func (r *mealEventRepository) FindAddressesByIDs(ctx context.Context, ids []uint) ([]model.EventAddress, error) {
+	var addresses []model.EventAddress
+	if len(ids) == 0 {
+		return addresses, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&addresses).Error
+	if err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// FindDueForClosing finds published meal events whose cutoff has passed
+func (r *mealEventRepository) FindDueForClosing(ctx context.Context, now time.Time) ([]model.MealEvent, error) {
+	var meals []model.MealEvent
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND cutoff_time <= ?", model.MealEventStatusPublished, now).
+		Find(&meals).Error
+	if err != nil {
+		return nil, err
+	}
+	return meals, nil
+}
+
+// FindOverlapping finds meal events of a meal type at any of the given addresses whose time slot overlaps [start, end).
+// Cancelled events and the event with excludeID are ignored.
+func (r *mealEventRepository) FindOverlapping(ctx context.Context, mealType model.MealType, addressIDs []uint, start, end time.Time, excludeID uint) ([]model.MealEvent, error) {
+	var meals []model.MealEvent
+	err := r.db.WithContext(ctx).
+		Distinct("meal_events.*").
+		Joins("JOIN meal_event_addresses ON meal_event_addresses.meal_event_id = meal_events.id").
+		Where("meal_events.meal_type = ?", mealType).
+		Where("meal_event_addresses.address_id IN ?", addressIDs).
+		Where("meal_events.id <> ? AND meal_events.status <> ?", excludeID, model.MealEventStatusCancelled).
+		Where("meal_events.event_date < ?", end).
+		Where("meal_events.event_date + meal_events.event_duration * INTERVAL '1 minute' > ?", start).
+		Find(&meals).Error
+	if err != nil {
+		return nil, err
+	}
+	return meals, nil
+}
+
+// raiseVersion raises the version of a meal event whose menu sets changed,
+// since they are saved together with the event
+func raiseVersion(tx *gorm.DB, mealEventID uint) error {
+	return tx.Model(&model.MealEvent{}).
+		Where("id = ?", mealEventID).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
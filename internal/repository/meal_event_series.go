@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+)
+
+// mealEventSeriesRepository implements MealEventSeriesRepository interface
+type mealEventSeriesRepository struct {
+	*baseRepository[model.MealEventSeries]
+	db *gorm.DB
+}
+
+// NewMealEventSeriesRepository creates a new instance of MealEventSeriesRepository
+func NewMealEventSeriesRepository(db *gorm.DB) MealEventSeriesRepository {
+	return &mealEventSeriesRepository{
+		baseRepository: NewBaseRepository[model.MealEventSeries](db),
+		db:             db,
+	}
+}
+
+// FindByID finds a meal event series by ID with its menu sets and addresses
+func (r *mealEventSeriesRepository) FindByID(ctx context.Context, id uint) (*model.MealEventSeries, error) {
+	var series model.MealEventSeries
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("MenuSets.MenuSet").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		First(&series, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindAll finds all meal event series with their menu sets and addresses
+func (r *mealEventSeriesRepository) FindAll(ctx context.Context) ([]model.MealEventSeries, error) {
+	var series []model.MealEventSeries
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("MenuSets.MenuSet").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		Order("id ASC").
+		Find(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// FindActiveSeries finds all active series with the menu sets and addresses needed to generate events
+func (r *mealEventSeriesRepository) FindActiveSeries(ctx context.Context) ([]model.MealEventSeries, error) {
+	var series []model.MealEventSeries
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		Where("is_active = ?", true).
+		Find(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// UpdateGeneratedUntil records how far ahead events have been generated for a series
+func (r *mealEventSeriesRepository) UpdateGeneratedUntil(ctx context.Context, seriesID uint, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.MealEventSeries{}).
+		Where("id = ?", seriesID).
+		Update("generated_until", until).Error
+}
+
+// TruncateSeries ends a series' schedule at the given date, replacing any occurrence count
+func (r *mealEventSeriesRepository) TruncateSeries(ctx context.Context, seriesID uint, until time.Time, updatedBy uint) error {
+	return r.db.WithContext(ctx).Model(&model.MealEventSeries{}).
+		Where("id = ?", seriesID).
+		Updates(map[string]interface{}{
+			"until":      until,
+			"count":      nil,
+			"version":    gorm.Expr("version + 1"),
+			"updated_by": updatedBy,
+		}).Error
+}
+
+// Deactivate stops a series from generating further events. It fails with ErrVersionConflict
+// unless the series is still at the given version, and raises the version otherwise.
+func (r *mealEventSeriesRepository) Deactivate(ctx context.Context, seriesID uint, version int, updatedBy uint) error {
+	result := r.db.WithContext(ctx).Model(&model.MealEventSeries{}).
+		Where("id = ? AND version = ?", seriesID, version).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"version":    gorm.Expr("version + 1"),
+			"updated_by": updatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Transaction runs fn with series and meal event repositories whose operations all belong to one
+// database transaction, so that a series and its occurrences change together.
+// The transaction is rolled back if fn returns an error.
+func (r *mealEventSeriesRepository) Transaction(ctx context.Context, fn func(seriesRepo MealEventSeriesRepository, mealRepo MealEventRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewMealEventSeriesRepository(tx), NewMealEventRepository(tx))
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mealEventTemplateRepository implements MealEventTemplateRepository interface
+type mealEventTemplateRepository struct {
+	*baseRepository[model.MealEventTemplate]
+	db *gorm.DB
+}
+
+// NewMealEventTemplateRepository creates a new instance of MealEventTemplateRepository
+func NewMealEventTemplateRepository(db *gorm.DB) MealEventTemplateRepository {
+	return &mealEventTemplateRepository{
+		baseRepository: NewBaseRepository[model.MealEventTemplate](db),
+		db:             db,
+	}
+}
+
+// FindByID finds a meal event template by ID with its menu sets and addresses
+func (r *mealEventTemplateRepository) FindByID(ctx context.Context, id uint) (*model.MealEventTemplate, error) {
+	var template model.MealEventTemplate
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("MenuSets.MenuSet").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindAll finds all meal event templates with their menu sets and addresses
+func (r *mealEventTemplateRepository) FindAll(ctx context.Context) ([]model.MealEventTemplate, error) {
+	var templates []model.MealEventTemplate
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("MenuSets.MenuSet").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		Order("name ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// FindByName finds a meal event template by name
+func (r *mealEventTemplateRepository) FindByName(ctx context.Context, name string) (*model.MealEventTemplate, error) {
+	var template model.MealEventTemplate
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Update updates a meal event template, replaces its menu sets and addresses and raises its version.
+// It fails with ErrVersionConflict if the template changed since it was read.
+func (r *mealEventTemplateRepository) Update(ctx context.Context, template *model.MealEventTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveNextVersion(tx, template, &template.Version); err != nil {
+			return err
+		}
+
+		if err := tx.Where("template_id = ?", template.ID).Delete(&model.MealEventTemplateSet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&model.MealEventTemplateAddress{}).Error; err != nil {
+			return err
+		}
+
+		for i := range template.MenuSets {
+			template.MenuSets[i].TemplateID = template.ID
+		}
+		for i := range template.Addresses {
+			template.Addresses[i].TemplateID = template.ID
+		}
+
+		if len(template.MenuSets) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&template.MenuSets).Error; err != nil {
+				return err
+			}
+		}
+		if len(template.Addresses) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&template.Addresses).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
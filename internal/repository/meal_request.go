@@ -6,6 +6,7 @@ import (
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // mealRequestRepository implements MealRequestRepository interface
@@ -42,9 +43,10 @@ func (r *mealRequestRepository) FindActive(ctx context.Context, conditions map[s
 	return r.baseRepository.FindActive(ctx, conditions)
 }
 
-// Update updates a meal request
+// Update saves a meal request and raises its version. It fails with ErrVersionConflict if the
+// request changed since it was read.
 func (r *mealRequestRepository) Update(ctx context.Context, request *model.MealRequest) error {
-	return r.baseRepository.Update(ctx, request)
+	return saveNextVersion(r.db.WithContext(ctx), request, &request.Version)
 }
 
 // Delete soft deletes a meal request
@@ -70,7 +72,12 @@ func (r *mealRequestRepository) FindByUserID(ctx context.Context, userID uint) (
 // FindByMealEventID finds meal requests by meal event ID
 func (r *mealRequestRepository) FindByMealEventID(ctx context.Context, mealEventID uint) ([]model.MealRequest, error) {
 	var requests []model.MealRequest
-	err := r.db.WithContext(ctx).Where("meal_event_id = ?", mealEventID).Find(&requests).Error
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Where("meal_event_id = ?", mealEventID).
+		Find(&requests).Error
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +94,61 @@ func (r *mealRequestRepository) RemoveRequestItem(ctx context.Context, item *mod
 	return r.db.WithContext(ctx).Delete(item).Error
 }
 
+// SaveWithItems creates or updates a meal request and replaces its items. Updates raise the
+// version and fail with ErrVersionConflict if the request changed since it was read.
+func (r *mealRequestRepository) SaveWithItems(ctx context.Context, request *model.MealRequest, items []model.MealRequestItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createOrSaveNextVersion(tx, request, request.ID, &request.Version); err != nil {
+			return err
+		}
+
+		if err := tx.Where("meal_request_id = ?", request.ID).Delete(&model.MealRequestItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			request.RequestItems = nil
+			return nil
+		}
+
+		for i := range items {
+			items[i].ID = 0
+			items[i].MealRequestID = request.ID
+		}
+		if err := tx.Omit(clause.Associations).Create(&items).Error; err != nil {
+			return err
+		}
+		request.RequestItems = items
+		return nil
+	})
+}
+
+// Transaction runs fn with a repository whose operations all belong to one database transaction.
+// The transaction is rolled back if fn returns an error.
+func (r *mealRequestRepository) Transaction(ctx context.Context, fn func(repo MealRequestRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&mealRequestRepository{
+			baseRepository: NewBaseRepository[model.MealRequest](tx),
+			db:             tx,
+		})
+	})
+}
+
+// LockMealEvent locks a meal event until the end of the transaction and returns it with its menu sets.
+// Decisions that depend on the other requests of the event, such as approving late requests against
+// the surplus, take this lock so that they are made one at a time. Surplus changes raise the event's
+// version and therefore wait for the lock as well.
+func (r *mealRequestRepository) LockMealEvent(ctx context.Context, mealEventID uint) (*model.MealEvent, error) {
+	var meal model.MealEvent
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("MenuSets").
+		First(&meal, mealEventID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &meal, nil
+}
+
 // FindRequestItems finds all items for a meal request
 func (r *mealRequestRepository) FindRequestItems(ctx context.Context, requestID uint) ([]model.MealRequestItem, error) {
 	var items []model.MealRequestItem
@@ -117,11 +179,14 @@ func (r *mealRequestRepository) FindApprovedRequests(ctx context.Context) ([]mod
 	return requests, nil
 }
 
-// FindByDateRange finds meal requests within a date range
+// FindByDateRange finds meal requests for the meal events held within a date range
 func (r *mealRequestRepository) FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealRequest, error) {
 	var requests []model.MealRequest
 	err := r.db.WithContext(ctx).
-		Where("requested_for BETWEEN ? AND ?", startDate, endDate).
+		Joins("MealEvent").
+		Preload("MenuSet").
+		Preload("RequestItems").
+		Where("\"MealEvent\".event_date BETWEEN ? AND ?", startDate, endDate).
 		Find(&requests).Error
 	if err != nil {
 		return nil, err
@@ -171,8 +236,10 @@ func (r *mealRequestRepository) FindWithDetails(ctx context.Context, id uint) (*
 	err := r.db.WithContext(ctx).
 		Preload("User").
 		Preload("MealEvent").
-		Preload("MealEvent.Menu").
-		Preload("MealEvent.Menu.CreatedBy").
+		Preload("MealEvent.Addresses").
+		Preload("MealEvent.Addresses.Address").
+		Preload("MenuSet").
+		Preload("EventAddress").
 		First(&request, id).Error
 	if err != nil {
 		return nil, err
@@ -180,10 +247,209 @@ func (r *mealRequestRepository) FindWithDetails(ctx context.Context, id uint) (*
 	return &request, nil
 }
 
-// UpdateRequestStatus updates the status of a meal request
-func (r *mealRequestRepository) UpdateRequestStatus(ctx context.Context, id uint, status model.RequestStatus) error {
+// UpdateRequestStatus moves a meal request from one status to another and records the transition.
+// It fails with ErrStatusChanged if the request is no longer in the expected status.
+func (r *mealRequestRepository) UpdateRequestStatus(ctx context.Context, request *model.MealRequest, from model.RequestStatus, transition *model.MealRequestTransition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.MealRequest{}).
+			Where("id = ? AND status = ?", request.ID, from).
+			Updates(map[string]interface{}{
+				"status":            request.Status,
+				"confirmed_at":      request.ConfirmedAt,
+				"awaiting_approval": request.AwaitingApproval,
+				"sequence":          request.Sequence,
+				"version":           gorm.Expr("version + 1"),
+				"updated_by":        request.UpdatedBy,
+				"updated_at":        time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+		request.Version++
+
+		return tx.Create(transition).Error
+	})
+}
+
+// FindTransitions finds the status history of a meal request, oldest first
+func (r *mealRequestRepository) FindTransitions(ctx context.Context, requestID uint) ([]model.MealRequestTransition, error) {
+	var transitions []model.MealRequestTransition
+	err := r.db.WithContext(ctx).
+		Preload("Actor").
+		Where("meal_request_id = ?", requestID).
+		Order("id ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// FindByUserIDAndEventDateRange finds a user's meal requests for events scheduled within a date range
+func (r *mealRequestRepository) FindByUserIDAndEventDateRange(ctx context.Context, userID uint, startDate, endDate time.Time) ([]model.MealRequest, error) {
+	var requests []model.MealRequest
+	err := r.db.WithContext(ctx).
+		Preload("MealEvent").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Joins("JOIN meal_events ON meal_events.id = meal_requests.meal_event_id").
+		Where("meal_requests.user_id = ?", userID).
+		Where("meal_events.event_date BETWEEN ? AND ?", startDate, endDate).
+		Order("meal_events.event_date ASC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// MarkNeedsAttention flags a meal request that its requester has to revisit
+func (r *mealRequestRepository) MarkNeedsAttention(ctx context.Context, requestID uint, note string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.MealRequest{}).
+		Where("id = ?", requestID).
+		Updates(map[string]interface{}{
+			"needs_attention": true,
+			"attention_note":  note,
+			"version":         gorm.Expr("version + 1"),
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// CreatePickup records that a meal request was picked up
+func (r *mealRequestRepository) CreatePickup(ctx context.Context, pickup *model.MealPickup) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(pickup).Error
+}
+
+// FindPickup finds the pickup of a meal request
+func (r *mealRequestRepository) FindPickup(ctx context.Context, requestID uint) (*model.MealPickup, error) {
+	var pickup model.MealPickup
+	err := r.db.WithContext(ctx).
+		Preload("Address").
+		Where("meal_request_id = ?", requestID).
+		First(&pickup).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pickup, nil
+}
+
+// FindPickupsByMealEventID finds the pickups of a meal event, earliest first
+func (r *mealRequestRepository) FindPickupsByMealEventID(ctx context.Context, mealEventID uint) ([]model.MealPickup, error) {
+	var pickups []model.MealPickup
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Address").
+		Where("meal_event_id = ?", mealEventID).
+		Order("picked_up_at ASC").
+		Find(&pickups).Error
+	if err != nil {
+		return nil, err
+	}
+	return pickups, nil
+}
+
+// HasPickups reports whether any meal of a meal event was picked up at a kiosk
+func (r *mealRequestRepository) HasPickups(ctx context.Context, mealEventID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.MealPickup{}).
+		Where("meal_event_id = ?", mealEventID).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindUnclaimed finds the approved requests without a pickup of confirmed or completed meal events
+// that ended before a time. Only events whose meals are checked in at a kiosk are considered.
+func (r *mealRequestRepository) FindUnclaimed(ctx context.Context, endedBefore time.Time) ([]model.MealRequest, error) {
+	var requests []model.MealRequest
+	err := r.db.WithContext(ctx).
+		Joins("JOIN meal_events ON meal_events.id = meal_requests.meal_event_id").
+		Where("meal_requests.status = ?", model.RequestStatusApproved).
+		Where("meal_events.status IN ?", []model.MealEventStatus{model.MealEventStatusConfirmed, model.MealEventStatusCompleted}).
+		Where("meal_events.event_date + meal_events.event_duration * INTERVAL '1 minute' < ?", endedBefore).
+		Where("EXISTS (SELECT 1 FROM meal_pickups WHERE meal_pickups.meal_event_id = meal_requests.meal_event_id AND meal_pickups.deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM meal_pickups WHERE meal_pickups.meal_request_id = meal_requests.id AND meal_pickups.deleted_at IS NULL)").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// FindCheckedInByDateRange finds the picked up and no-show requests of the meal events within a date range
+// whose meals are checked in at a kiosk
+func (r *mealRequestRepository) FindCheckedInByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealRequest, error) {
+	var requests []model.MealRequest
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Joins("JOIN meal_events ON meal_events.id = meal_requests.meal_event_id").
+		Where("meal_requests.status IN ?", []model.RequestStatus{model.RequestStatusCompleted, model.RequestStatusNoShow}).
+		Where("meal_events.event_date BETWEEN ? AND ?", startDate, endDate).
+		Where("EXISTS (SELECT 1 FROM meal_pickups WHERE meal_pickups.meal_event_id = meal_requests.meal_event_id AND meal_pickups.deleted_at IS NULL)").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// FindAwaitingApproval finds the pending requests of some users that wait for their line manager's approval
+func (r *mealRequestRepository) FindAwaitingApproval(ctx context.Context, userIDs []uint) ([]model.MealRequest, error) {
+	var requests []model.MealRequest
+	if len(userIDs) == 0 {
+		return requests, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("MealEvent").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Joins("JOIN meal_events ON meal_events.id = meal_requests.meal_event_id").
+		Where("meal_requests.user_id IN ? AND meal_requests.status = ? AND meal_requests.awaiting_approval = ?",
+			userIDs, model.RequestStatusPending, true).
+		Order("meal_events.event_date ASC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ClearAwaitingApproval releases a pending request that its requester's line manager approved
+func (r *mealRequestRepository) ClearAwaitingApproval(ctx context.Context, requestID uint, updatedBy uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.MealRequest{}).
+		Where("id = ? AND status = ? AND awaiting_approval = ?", requestID, model.RequestStatusPending, true).
+		Updates(map[string]interface{}{
+			"awaiting_approval": false,
+			"version":           gorm.Expr("version + 1"),
+			"updated_by":        updatedBy,
+			"updated_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+	return nil
+}
+
+// ExcuseNoShow stops a no-show from counting towards no-show policies
+func (r *mealRequestRepository) ExcuseNoShow(ctx context.Context, requestID uint, reason string, updatedBy uint) error {
 	return r.db.WithContext(ctx).
 		Model(&model.MealRequest{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+		Where("id = ?", requestID).
+		Updates(map[string]interface{}{
+			"no_show_excused": true,
+			"admin_reason":    reason,
+			"version":         gorm.Expr("version + 1"),
+			"updated_by":      updatedBy,
+			"updated_at":      time.Now(),
+		}).Error
 }
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mealTransferRepository implements MealTransferRepository interface
+type mealTransferRepository struct {
+	*baseRepository[model.MealTransfer]
+	db *gorm.DB
+}
+
+// NewMealTransferRepository creates a new instance of MealTransferRepository
+func NewMealTransferRepository(db *gorm.DB) MealTransferRepository {
+	return &mealTransferRepository{
+		baseRepository: NewBaseRepository[model.MealTransfer](db),
+		db:             db,
+	}
+}
+
+// Create creates a meal transfer without touching its associations
+func (r *mealTransferRepository) Create(ctx context.Context, transfer *model.MealTransfer) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(transfer).Error
+}
+
+// FindByID finds a meal transfer with its parties, meal event and meal request
+func (r *mealTransferRepository) FindByID(ctx context.Context, id uint) (*model.MealTransfer, error) {
+	var transfer model.MealTransfer
+	err := r.withDetails(r.db.WithContext(ctx)).First(&transfer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByRequestID finds every transfer of a meal request, oldest first
+func (r *mealTransferRepository) FindByRequestID(ctx context.Context, requestID uint) ([]model.MealTransfer, error) {
+	var transfers []model.MealTransfer
+	err := r.db.WithContext(ctx).
+		Preload("FromUser").
+		Preload("ToUser").
+		Where("meal_request_id = ?", requestID).
+		Order("id ASC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// FindOpenByRequestID finds the open offer of a meal request
+func (r *mealTransferRepository) FindOpenByRequestID(ctx context.Context, requestID uint) (*model.MealTransfer, error) {
+	var transfer model.MealTransfer
+	err := r.db.WithContext(ctx).
+		Where("meal_request_id = ? AND status = ?", requestID, model.MealTransferStatusOffered).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByUserID finds the transfers a user offered or was offered, latest first
+func (r *mealTransferRepository) FindByUserID(ctx context.Context, userID uint) ([]model.MealTransfer, error) {
+	var transfers []model.MealTransfer
+	err := r.withDetails(r.db.WithContext(ctx)).
+		Where("from_user_id = ? OR to_user_id = ?", userID, userID).
+		Order("id DESC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// FindOpenPool finds the confirmed meals offered to the surplus pool whose meal events end after a time,
+// soonest first
+func (r *mealTransferRepository) FindOpenPool(ctx context.Context, endingAfter time.Time) ([]model.MealTransfer, error) {
+	var transfers []model.MealTransfer
+	err := r.withDetails(r.db.WithContext(ctx)).
+		Joins("JOIN meal_events ON meal_events.id = meal_transfers.meal_event_id").
+		Joins("JOIN meal_requests ON meal_requests.id = meal_transfers.meal_request_id").
+		Where("meal_transfers.pool = ? AND meal_transfers.status = ?", true, model.MealTransferStatusOffered).
+		Where("meal_requests.status = ?", model.RequestStatusApproved).
+		Where("meal_events.event_date + meal_events.event_duration * INTERVAL '1 minute' > ?", endingAfter).
+		Order("meal_events.event_date ASC, meal_transfers.id ASC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// UpdateStatus closes an offer unless it changed since it was read
+func (r *mealTransferRepository) UpdateStatus(ctx context.Context, transfer *model.MealTransfer, from model.MealTransferStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.MealTransfer{}).
+		Where("id = ? AND status = ?", transfer.ID, from).
+		Updates(map[string]interface{}{
+			"status":       transfer.Status,
+			"responded_at": transfer.RespondedAt,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+	return nil
+}
+
+// CompleteTransfer closes an open offer and makes the recipient the owner of the meal request in one
+// transaction. It fails with ErrStatusChanged if the offer was closed, or the meal request changed
+// hands or status, since they were read; of two concurrent claims only one succeeds.
+func (r *mealTransferRepository) CompleteTransfer(ctx context.Context, transfer *model.MealTransfer, toUserID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.MealTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, model.MealTransferStatusOffered).
+			Updates(map[string]interface{}{
+				"status":       transfer.Status,
+				"to_user_id":   toUserID,
+				"responded_at": now,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+
+		result = tx.Model(&model.MealRequest{}).
+			Where("id = ? AND user_id = ? AND status = ?", transfer.MealRequestID, transfer.FromUserID, model.RequestStatusApproved).
+			Updates(map[string]interface{}{
+				"user_id":    toUserID,
+				"sequence":   gorm.Expr("sequence + 1"),
+				"version":    gorm.Expr("version + 1"),
+				"updated_by": toUserID,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+
+		transfer.ToUserID = &toUserID
+		transfer.RespondedAt = &now
+		return nil
+	})
+}
+
+// withDetails preloads what is shown for a meal transfer
+func (r *mealTransferRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("FromUser").
+		Preload("ToUser").
+		Preload("MealEvent").
+		Preload("MealEvent.Addresses").
+		Preload("MealEvent.Addresses.Address").
+		Preload("MealRequest").
+		Preload("MealRequest.MenuSet").
+		Preload("MealRequest.EventAddress")
+}
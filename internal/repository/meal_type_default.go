@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mealTypeDefaultRepository implements MealTypeDefaultRepository interface
+type mealTypeDefaultRepository struct {
+	*baseRepository[model.MealTypeDefault]
+	db *gorm.DB
+}
+
+// NewMealTypeDefaultRepository creates a new instance of MealTypeDefaultRepository
+func NewMealTypeDefaultRepository(db *gorm.DB) MealTypeDefaultRepository {
+	return &mealTypeDefaultRepository{
+		baseRepository: NewBaseRepository[model.MealTypeDefault](db),
+		db:             db,
+	}
+}
+
+// FindAll finds the defaults of every meal type with their menu sets and addresses
+func (r *mealTypeDefaultRepository) FindAll(ctx context.Context) ([]model.MealTypeDefault, error) {
+	var defaults []model.MealTypeDefault
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("MenuSets.MenuSet").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		Order("meal_type ASC").
+		Find(&defaults).Error
+	if err != nil {
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// FindByMealType finds the defaults of a meal type with their menu sets and addresses
+func (r *mealTypeDefaultRepository) FindByMealType(ctx context.Context, mealType model.MealType) (*model.MealTypeDefault, error) {
+	var defaults model.MealTypeDefault
+	err := r.db.WithContext(ctx).
+		Preload("MenuSets").
+		Preload("MenuSets.MenuSet").
+		Preload("Addresses").
+		Preload("Addresses.Address").
+		Where("meal_type = ?", mealType).
+		First(&defaults).Error
+	if err != nil {
+		return nil, err
+	}
+	return &defaults, nil
+}
+
+// Update updates the defaults of a meal type and replaces their menu sets and addresses
+func (r *mealTypeDefaultRepository) Update(ctx context.Context, defaults *model.MealTypeDefault) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(defaults).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("default_id = ?", defaults.ID).Delete(&model.MealTypeDefaultSet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("default_id = ?", defaults.ID).Delete(&model.MealTypeDefaultAddress{}).Error; err != nil {
+			return err
+		}
+
+		for i := range defaults.MenuSets {
+			defaults.MenuSets[i].DefaultID = defaults.ID
+		}
+		for i := range defaults.Addresses {
+			defaults.Addresses[i].DefaultID = defaults.ID
+		}
+
+		if len(defaults.MenuSets) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&defaults.MenuSets).Error; err != nil {
+				return err
+			}
+		}
+		if len(defaults.Addresses) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&defaults.Addresses).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -41,9 +41,15 @@ func (r *menuItemRepository) FindActive(ctx context.Context, conditions map[stri
 	return r.baseRepository.FindActive(ctx, conditions)
 }
 
-// Update updates a menu item
+// Update saves a menu item and raises its version. It fails with ErrVersionConflict if the
+// item changed since it was read.
 func (r *menuItemRepository) Update(ctx context.Context, item *model.MenuItem) error {
-	return r.baseRepository.Update(ctx, item)
+	return saveNextVersion(r.db.WithContext(ctx), item, &item.Version)
+}
+
+// UpdateVersion updates a menu item if it is still at the given version
+func (r *menuItemRepository) UpdateVersion(ctx context.Context, item *model.MenuItem, version int) error {
+	return r.baseRepository.UpdateVersion(ctx, item, version)
 }
 
 // Delete soft deletes a menu item
@@ -51,6 +57,11 @@ func (r *menuItemRepository) Delete(ctx context.Context, item *model.MenuItem) e
 	return r.baseRepository.Delete(ctx, item)
 }
 
+// DeleteVersion deletes a menu item if it is still at the given version
+func (r *menuItemRepository) DeleteVersion(ctx context.Context, item *model.MenuItem, version int) error {
+	return r.baseRepository.DeleteVersion(ctx, item, version)
+}
+
 // HardDelete permanently deletes a menu item
 func (r *menuItemRepository) HardDelete(ctx context.Context, item *model.MenuItem) error {
 	return r.baseRepository.HardDelete(ctx, item)
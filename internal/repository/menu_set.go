@@ -41,9 +41,40 @@ func (r *menuSetRepository) FindActive(ctx context.Context, conditions map[strin
 	return r.baseRepository.FindActive(ctx, conditions)
 }
 
-// Update updates a menu set
+// FindWithItems finds a menu set by ID with its menu items
+func (r *menuSetRepository) FindWithItems(ctx context.Context, id uint) (*model.MenuSet, error) {
+	var menuSet model.MenuSet
+	err := r.db.WithContext(ctx).
+		Preload("MenuSetItems.MenuItem").
+		First(&menuSet, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &menuSet, nil
+}
+
+// FindActiveWithItems finds the active menu sets with their menu items
+func (r *menuSetRepository) FindActiveWithItems(ctx context.Context) ([]model.MenuSet, error) {
+	var menuSets []model.MenuSet
+	err := r.db.WithContext(ctx).
+		Preload("MenuSetItems.MenuItem").
+		Where("is_active = ?", true).
+		Find(&menuSets).Error
+	if err != nil {
+		return nil, err
+	}
+	return menuSets, nil
+}
+
+// Update saves a menu set and raises its version. It fails with ErrVersionConflict if the
+// menu set changed since it was read.
 func (r *menuSetRepository) Update(ctx context.Context, menuSet *model.MenuSet) error {
-	return r.baseRepository.Update(ctx, menuSet)
+	return saveNextVersion(r.db.WithContext(ctx), menuSet, &menuSet.Version)
+}
+
+// UpdateVersion updates a menu set if it is still at the given version
+func (r *menuSetRepository) UpdateVersion(ctx context.Context, menuSet *model.MenuSet, version int) error {
+	return r.baseRepository.UpdateVersion(ctx, menuSet, version)
 }
 
 // Delete soft deletes a menu set
@@ -51,6 +82,11 @@ func (r *menuSetRepository) Delete(ctx context.Context, menuSet *model.MenuSet)
 	return r.baseRepository.Delete(ctx, menuSet)
 }
 
+// DeleteVersion deletes a menu set if it is still at the given version
+func (r *menuSetRepository) DeleteVersion(ctx context.Context, menuSet *model.MenuSet, version int) error {
+	return r.baseRepository.DeleteVersion(ctx, menuSet, version)
+}
+
 // HardDelete permanently deletes a menu set
 func (r *menuSetRepository) HardDelete(ctx context.Context, menuSet *model.MenuSet) error {
 	return r.baseRepository.HardDelete(ctx, menuSet)
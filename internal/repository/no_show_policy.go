@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noShowPolicyRepository implements NoShowPolicyRepository interface
+type noShowPolicyRepository struct {
+	*baseRepository[model.NoShowPolicy]
+	db *gorm.DB
+}
+
+// NewNoShowPolicyRepository creates a new instance of NoShowPolicyRepository
+func NewNoShowPolicyRepository(db *gorm.DB) NoShowPolicyRepository {
+	return &noShowPolicyRepository{
+		baseRepository: NewBaseRepository[model.NoShowPolicy](db),
+		db:             db,
+	}
+}
+
+// CountNoShowsSince counts the unexcused no-shows per user at meal events held since a time
+func (r *noShowPolicyRepository) CountNoShowsSince(ctx context.Context, since time.Time) (map[uint]int, error) {
+	var rows []struct {
+		UserID  uint
+		NoShows int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.MealRequest{}).
+		Select("meal_requests.user_id, COUNT(*) AS no_shows").
+		Joins("JOIN meal_events ON meal_events.id = meal_requests.meal_event_id").
+		Where("meal_requests.status = ? AND meal_requests.no_show_excused = ?", model.RequestStatusNoShow, false).
+		Where("meal_requests.deleted_at IS NULL").
+		Where("meal_events.event_date >= ?", since).
+		Group("meal_requests.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = row.NoShows
+	}
+	return counts, nil
+}
+
+// FindNoShowsByUserID finds a user's no-shows, excused ones included, latest meal event first
+func (r *noShowPolicyRepository) FindNoShowsByUserID(ctx context.Context, userID uint) ([]model.MealRequest, error) {
+	var requests []model.MealRequest
+	err := r.db.WithContext(ctx).
+		Preload("MealEvent").
+		Preload("MenuSet").
+		Preload("EventAddress").
+		Joins("JOIN meal_events ON meal_events.id = meal_requests.meal_event_id").
+		Where("meal_requests.user_id = ? AND meal_requests.status = ?", userID, model.RequestStatusNoShow).
+		Order("meal_events.event_date DESC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// CreatePenalty records a triggered no-show policy
+func (r *noShowPolicyRepository) CreatePenalty(ctx context.Context, penalty *model.NoShowPenalty) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(penalty).Error
+}
+
+// UpdatePenalty saves a no-show penalty
+func (r *noShowPolicyRepository) UpdatePenalty(ctx context.Context, penalty *model.NoShowPenalty) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(penalty).Error
+}
+
+// FindPenaltyByID finds a no-show penalty with its policy
+func (r *noShowPolicyRepository) FindPenaltyByID(ctx context.Context, id uint) (*model.NoShowPenalty, error) {
+	var penalty model.NoShowPenalty
+	if err := r.db.WithContext(ctx).Preload("Policy").First(&penalty, id).Error; err != nil {
+		return nil, err
+	}
+	return &penalty, nil
+}
+
+// FindPenaltiesByUserID finds a user's no-show penalties, latest first
+func (r *noShowPolicyRepository) FindPenaltiesByUserID(ctx context.Context, userID uint) ([]model.NoShowPenalty, error) {
+	var penalties []model.NoShowPenalty
+	err := r.db.WithContext(ctx).
+		Preload("Policy").
+		Where("user_id = ?", userID).
+		Order("starts_at DESC").
+		Find(&penalties).Error
+	if err != nil {
+		return nil, err
+	}
+	return penalties, nil
+}
+
+// HasPenaltySince reports whether a policy was triggered for a user since a time
+func (r *noShowPolicyRepository) HasPenaltySince(ctx context.Context, policyID, userID uint, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.NoShowPenalty{}).
+		Where("policy_id = ? AND user_id = ? AND starts_at >= ?", policyID, userID, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindRestrictedUserIDs finds the users under a restriction that is in effect at a time
+func (r *noShowPolicyRepository) FindRestrictedUserIDs(ctx context.Context, action model.NoShowAction, at time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.WithContext(ctx).
+		Model(&model.NoShowPenalty{}).
+		Distinct("user_id").
+		Where("action = ? AND lifted_at IS NULL AND starts_at <= ?", action, at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"gorm.io/gorm"
@@ -55,14 +56,13 @@ func (r *notificationRepository) CountUndeliveredByUserID(ctx context.Context, u
 	return count, nil
 }
 
-// MarkAsRead marks a notification as read
+// MarkAsRead marks a notification as read, keeping the original read time if it was already read
 func (r *notificationRepository) MarkAsRead(ctx context.Context, id uint) error {
-	now := gorm.Expr("NOW()")
 	return r.db.WithContext(ctx).Model(&model.Notification{}).
-		Where("id = ?", id).
+		Where("id = ? AND read = ?", id, false).
 		Updates(map[string]interface{}{
 			"read":    true,
-			"read_at": now,
+			"read_at": time.Now(),
 		}).Error
 }
 
@@ -96,3 +96,111 @@ func (r *notificationRepository) FindByType(ctx context.Context, userID uint, no
 	}
 	return notifications, nil
 }
+
+// FindUndeliveredByTypes finds undelivered notifications of the given types created before a point in time
+func (r *notificationRepository) FindUndeliveredByTypes(ctx context.Context, userID uint, types []model.NotificationType, before time.Time) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND delivered = ? AND type IN ? AND created_at < ?", userID, false, types, before).
+		Order("created_at ASC").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkManyAsDelivered marks several notifications as delivered in one statement
+func (r *notificationRepository) MarkManyAsDelivered(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	now := gorm.Expr("NOW()")
+	return r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"delivered":    true,
+			"delivered_at": now,
+		}).Error
+}
+
+// FindPageByUserID finds one page of a user's notifications, newest first
+func (r *notificationRepository) FindPageByUserID(ctx context.Context, userID uint, filter model.NotificationFilter) ([]model.Notification, error) {
+	query := r.db.WithContext(ctx).
+		Where("user_id = ? AND archived = ?", userID, filter.Archived)
+
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	if filter.Read != nil {
+		query = query.Where("read = ?", *filter.Read)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.Cursor > 0 {
+		query = query.Where("id < ?", filter.Cursor)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var notifications []model.Notification
+	err := query.Order("id DESC").Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkAllAsRead marks every unread notification of a user as read
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND read = ?", userID, false).
+		Updates(map[string]interface{}{
+			"read":    true,
+			"read_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByIDs deletes the given notifications that belong to a user
+func (r *notificationRepository) DeleteByIDs(ctx context.Context, userID uint, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Delete(&model.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// SetArchived archives or unarchives the given notifications that belong to a user
+func (r *notificationRepository) SetArchived(ctx context.Context, userID uint, ids []uint, archived bool) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	var archivedAt interface{}
+	if archived {
+		archivedAt = time.Now()
+	}
+	result := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Updates(map[string]interface{}{
+			"archived":    archived,
+			"archived_at": archivedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeReadBefore permanently deletes read notifications created before a point in time
+func (r *notificationRepository) PurgeReadBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("read = ? AND created_at < ?", true, before).
+		Delete(&model.Notification{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// standingOrderRepository implements StandingOrderRepository interface
+type standingOrderRepository struct {
+	*baseRepository[model.StandingOrder]
+	db *gorm.DB
+}
+
+// NewStandingOrderRepository creates a new instance of StandingOrderRepository
+func NewStandingOrderRepository(db *gorm.DB) StandingOrderRepository {
+	return &standingOrderRepository{
+		baseRepository: NewBaseRepository[model.StandingOrder](db),
+		db:             db,
+	}
+}
+
+// FindByID finds a standing order with its items
+func (r *standingOrderRepository) FindByID(ctx context.Context, id uint) (*model.StandingOrder, error) {
+	var order model.StandingOrder
+	err := r.db.WithContext(ctx).
+		Preload("EventAddress").
+		Preload("MenuSet").
+		Preload("Items").
+		Preload("Items.MenuItem").
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindByUserID finds the standing orders of a user
+func (r *standingOrderRepository) FindByUserID(ctx context.Context, userID uint) ([]model.StandingOrder, error) {
+	var orders []model.StandingOrder
+	err := r.db.WithContext(ctx).
+		Preload("EventAddress").
+		Preload("MenuSet").
+		Preload("Items").
+		Preload("Items.MenuItem").
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// FindActiveByMealType finds the active standing orders for a meal type, oldest first
+func (r *standingOrderRepository) FindActiveByMealType(ctx context.Context, mealType model.MealType) ([]model.StandingOrder, error) {
+	var orders []model.StandingOrder
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("meal_type = ? AND is_active = ?", mealType, true).
+		Order("id ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// SaveWithItems creates or updates a standing order and replaces its items. Updates raise the
+// version and fail with ErrVersionConflict if the order changed since it was read.
+func (r *standingOrderRepository) SaveWithItems(ctx context.Context, order *model.StandingOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		items := order.Items
+		if err := createOrSaveNextVersion(tx, order, order.ID, &order.Version); err != nil {
+			return err
+		}
+
+		if err := tx.Where("standing_order_id = ?", order.ID).Delete(&model.StandingOrderItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		for i := range items {
+			items[i].StandingOrderID = order.ID
+		}
+		return tx.Omit(clause.Associations).Create(&items).Error
+	})
+}
+
+// Delete removes a standing order and its items
+func (r *standingOrderRepository) Delete(ctx context.Context, order *model.StandingOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("standing_order_id = ?", order.ID).Delete(&model.StandingOrderItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(order).Error
+	})
+}
+
+// DeleteVersion removes a standing order and its items if the order is still at the given version
+func (r *standingOrderRepository) DeleteVersion(ctx context.Context, order *model.StandingOrder, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", version).Delete(order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		return tx.Where("standing_order_id = ?", order.ID).Delete(&model.StandingOrderItem{}).Error
+	})
+}
+
+// FindPausesByUserID finds the pauses of a user, latest first
+func (r *standingOrderRepository) FindPausesByUserID(ctx context.Context, userID uint) ([]model.StandingOrderPause, error) {
+	var pauses []model.StandingOrderPause
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("start_date DESC").
+		Find(&pauses).Error
+	if err != nil {
+		return nil, err
+	}
+	return pauses, nil
+}
+
+// FindPausesOn finds the pauses that include a date
+func (r *standingOrderRepository) FindPausesOn(ctx context.Context, date time.Time) ([]model.StandingOrderPause, error) {
+	var pauses []model.StandingOrderPause
+	err := r.db.WithContext(ctx).
+		Where("start_date <= ? AND end_date >= ?", date, date).
+		Find(&pauses).Error
+	if err != nil {
+		return nil, err
+	}
+	return pauses, nil
+}
+
+// FindPauseByID finds a pause by ID
+func (r *standingOrderRepository) FindPauseByID(ctx context.Context, id uint) (*model.StandingOrderPause, error) {
+	var pause model.StandingOrderPause
+	if err := r.db.WithContext(ctx).First(&pause, id).Error; err != nil {
+		return nil, err
+	}
+	return &pause, nil
+}
+
+// CreatePause creates a pause
+func (r *standingOrderRepository) CreatePause(ctx context.Context, pause *model.StandingOrderPause) error {
+	return r.db.WithContext(ctx).Create(pause).Error
+}
+
+// DeletePause removes a pause
+func (r *standingOrderRepository) DeletePause(ctx context.Context, pause *model.StandingOrderPause) error {
+	return r.db.WithContext(ctx).Delete(pause).Error
+}
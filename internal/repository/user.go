@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"gorm.io/gorm"
@@ -85,3 +86,55 @@ func (r *userRepository) FindByEmployeeID(ctx context.Context, employeeID int) (
 	}
 	return &user, nil
 }
+
+// FindByDigestFrequency finds active users with notifications enabled who opted into the given digest frequency
+func (r *userRepository) FindByDigestFrequency(ctx context.Context, frequency model.DigestFrequency) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("digest_frequency = ? AND is_active = ? AND notification_enabled = ?", frequency, true, true).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateLastDigestAt records when the user last received a digest
+func (r *userRepository) UpdateLastDigestAt(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("last_digest_at", at).Error
+}
+
+// FindReports finds the employees who report directly to a manager
+func (r *userRepository) FindReports(ctx context.Context, managerID uint) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("manager_id = ?", managerID).
+		Order("name ASC").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateManager sets or clears the line manager of an employee
+func (r *userRepository) UpdateManager(ctx context.Context, userID uint, managerID *uint, updatedBy uint) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"manager_id": managerID,
+			"updated_by": updatedBy,
+		}).Error
+}
+
+// UpdateRole changes the role of a user
+func (r *userRepository) UpdateRole(ctx context.Context, userID uint, role model.UserRole, updatedBy uint) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_by": updatedBy,
+		}).Error
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job represents a unit of background work that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// Scheduler runs registered jobs periodically until its context is cancelled
+type Scheduler struct {
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+// New creates a new Scheduler
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job to the scheduler. Jobs must be registered before Start is called.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job in its own goroutine
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait blocks until all jobs have stopped
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop runs a single job on every tick of its interval
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := job.Run(ctx, now); err != nil {
+				log.Printf("scheduler: job %s failed: %v", job.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/ical"
+	"github.com/arafat-hasan/mealsync/internal/mailer"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+const (
+	// calendarFeedTokenBytes is the amount of randomness in a feed token
+	calendarFeedTokenBytes = 24
+	// personalFeedHistoryDays keeps recent meals in personal feeds so they don't vanish right after lunch
+	personalFeedHistoryDays = 30
+	// personalFeedHorizonDays limits how far ahead personal feeds look
+	personalFeedHorizonDays = 365
+	// cutoffAlarmLead is how long before the request cutoff the feed alarm goes off
+	cutoffAlarmLead = 30 * time.Minute
+)
+
+// calendarService handles iCalendar feeds and calendar emails
+type calendarService struct {
+	feedRepo    repository.CalendarFeedRepository
+	mealRepo    repository.MealEventRepository
+	requestRepo repository.MealRequestRepository
+	mailer      mailer.Mailer
+	publicURL   string
+	mailFrom    string
+	timeZone    string
+}
+
+// NewCalendarService creates a new instance of CalendarService
+func NewCalendarService(
+	feedRepo repository.CalendarFeedRepository,
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	mailer mailer.Mailer,
+	publicURL string,
+	mailFrom string,
+	timeZone string,
+) CalendarService {
+	return &calendarService{
+		feedRepo:    feedRepo,
+		mealRepo:    mealRepo,
+		requestRepo: requestRepo,
+		mailer:      mailer,
+		publicURL:   publicURL,
+		mailFrom:    mailFrom,
+		timeZone:    timeZone,
+	}
+}
+
+// ListFeeds retrieves the calendar feeds of a user that have not been revoked
+func (s *calendarService) ListFeeds(ctx context.Context, userID uint) ([]model.CalendarFeed, error) {
+	feeds, err := s.feedRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch calendar feeds", err)
+	}
+	for i := range feeds {
+		feeds[i].URL = s.feedURL(&feeds[i])
+	}
+	return feeds, nil
+}
+
+// CreateFeed issues a new feed URL for a user. A previous feed of the same kind stops working.
+func (s *calendarService) CreateFeed(ctx context.Context, userID uint, kind model.CalendarFeedKind) (*model.CalendarFeed, error) {
+	if !kind.IsValid() {
+		return nil, errors.NewValidationError("kind must be events or personal", nil)
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, errors.NewInternalError("failed to generate feed token", err)
+	}
+
+	feed := &model.CalendarFeed{
+		UserID: userID,
+		Kind:   kind,
+		Token:  token,
+	}
+	if err := s.feedRepo.Replace(ctx, feed); err != nil {
+		return nil, errors.NewInternalError("failed to create calendar feed", err)
+	}
+
+	feed.URL = s.feedURL(feed)
+	return feed, nil
+}
+
+// RevokeFeed stops a feed URL from working
+func (s *calendarService) RevokeFeed(ctx context.Context, id uint, userID uint) error {
+	feed, err := s.feedRepo.FindByID(ctx, id)
+	if err != nil || feed.RevokedAt != nil {
+		return errors.NewNotFoundError("calendar feed not found", err)
+	}
+	if feed.UserID != userID {
+		return errors.NewForbiddenError("unauthorized to revoke this calendar feed", nil)
+	}
+
+	now := time.Now()
+	feed.RevokedAt = &now
+	if err := s.feedRepo.Update(ctx, feed); err != nil {
+		return errors.NewInternalError("failed to revoke calendar feed", err)
+	}
+	return nil
+}
+
+// RenderFeed renders the iCalendar document served at a feed URL
+func (s *calendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.feedRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, errors.NewNotFoundError("calendar feed not found", err)
+	}
+	if !feed.User.IsActive {
+		return nil, errors.NewNotFoundError("calendar feed not found", nil)
+	}
+
+	now := time.Now()
+	var calendar *ical.Calendar
+	if feed.Kind == model.CalendarFeedPersonal {
+		calendar, err = s.personalCalendar(ctx, feed.UserID, now)
+	} else {
+		calendar, err = s.eventsCalendar(ctx)
+	}
+	if err != nil {
+		return nil, errors.NewInternalError("failed to build calendar feed", err)
+	}
+
+	// Feeds are polled often; losing an access timestamp is harmless
+	_ = s.feedRepo.MarkAccessed(ctx, feed.ID, now)
+
+	return calendar.Encode(), nil
+}
+
+// SendRequestConfirmation emails a requester an invitation for their confirmed meal
+func (s *calendarService) SendRequestConfirmation(ctx context.Context, meal *model.MealEvent, request *model.MealRequest) {
+	event := s.requestEvent(meal, request)
+	event.Status = ical.StatusConfirmed
+
+	subject := fmt.Sprintf("Meal confirmed: %s on %s", meal.Name, formatMealTime(meal.EventDate, s.requestLocation(meal, request)))
+	body := fmt.Sprintf("Your meal request for %s is confirmed.\n\n%s", meal.Name, event.Description)
+	s.deliver(ctx, request.User, ical.MethodRequest, subject, body, event)
+}
+
+// SendRequestCancellation emails a requester that their meal was cancelled, removing it from their calendar
+func (s *calendarService) SendRequestCancellation(ctx context.Context, meal *model.MealEvent, request *model.MealRequest, reason string) {
+	event := s.requestEvent(meal, request)
+	event.Status = ical.StatusCancelled
+
+	subject := fmt.Sprintf("Meal cancelled: %s on %s", meal.Name, formatMealTime(meal.EventDate, s.requestLocation(meal, request)))
+	body := fmt.Sprintf("%s has been cancelled.", meal.Name)
+	if reason != "" {
+		body += "\nReason: " + reason
+	}
+	s.deliver(ctx, request.User, ical.MethodCancel, subject, body, event)
+}
+
+// SendRequestWithdrawal removes a meal from the requester's calendar when their approved request of a
+// confirmed meal event was cancelled or rejected on its own. Any other status change sends nothing.
+func (s *calendarService) SendRequestWithdrawal(ctx context.Context, requestID uint, from model.RequestStatus, reason string) {
+	if from != model.RequestStatusApproved {
+		return
+	}
+	request, ok := s.invitedRequest(ctx, requestID)
+	if !ok || (request.Status != model.RequestStatusCancelled && request.Status != model.RequestStatusRejected) {
+		return
+	}
+	s.SendRequestCancellation(ctx, &request.MealEvent, request, reason)
+}
+
+// SendRequestTransfer moves the invitation for a meal of a confirmed meal event from the calendar
+// of its previous owner to that of its new owner
+func (s *calendarService) SendRequestTransfer(ctx context.Context, requestID uint, previous model.User) {
+	request, ok := s.invitedRequest(ctx, requestID)
+	if !ok {
+		return
+	}
+	handedOver := *request
+	handedOver.User = previous
+	s.SendRequestCancellation(ctx, &request.MealEvent, &handedOver, "the meal was handed over to "+request.User.Name)
+	s.SendRequestConfirmation(ctx, &request.MealEvent, request)
+}
+
+// invitedRequest loads a meal request with its meal event and reports whether its owner holds an
+// invitation for it, which is sent once the meal event is confirmed
+func (s *calendarService) invitedRequest(ctx context.Context, requestID uint) (*model.MealRequest, bool) {
+	request, err := s.requestRepo.FindWithDetails(ctx, requestID)
+	if err != nil {
+		log.Printf("calendar: failed to load meal request %d: %v", requestID, err)
+		return nil, false
+	}
+	return request, request.MealEvent.Status == model.MealEventStatusConfirmed
+}
+
+// deliver emails a user one calendar event. Delivery is best effort: the change it reports
+// has already been saved and shows up in the feeds, so failures are only logged.
+func (s *calendarService) deliver(ctx context.Context, user model.User, method ical.Method, subject, body string, event ical.Event) {
+	if user.Email == "" || !user.NotificationEnabled {
+		return
+	}
+
+	event.Organizer = s.mailFrom
+	event.Attendee = user.Email
+	calendar := &ical.Calendar{Method: method, Events: []ical.Event{event}}
+
+	msg := mailer.Message{
+		To:      []string{user.Email},
+		Subject: subject,
+		Body:    body,
+		Attachments: []mailer.Attachment{{
+			Filename:    "meal.ics",
+			ContentType: "text/calendar; charset=utf-8; method=" + string(method),
+			Data:        calendar.Encode(),
+		}},
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("calendar: failed to email %s: %v", user.Email, err)
+	}
+}
+
+// eventsCalendar lists every upcoming meal event employees can see
+func (s *calendarService) eventsCalendar(ctx context.Context) (*ical.Calendar, error) {
+	meals, err := s.mealRepo.FindUpcomingAndActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{Name: "Meal events", Method: ical.MethodPublish}
+	for i := range meals {
+		if meals[i].Status == model.MealEventStatusDraft || meals[i].Status == model.MealEventStatusCancelled {
+			continue
+		}
+		calendar.Events = append(calendar.Events, s.mealEvent(&meals[i]))
+	}
+	return calendar, nil
+}
+
+// personalCalendar lists the meals a user requested, including those of the last few days
+func (s *calendarService) personalCalendar(ctx context.Context, userID uint, now time.Time) (*ical.Calendar, error) {
+	requests, err := s.requestRepo.FindByUserIDAndEventDateRange(ctx, userID,
+		now.AddDate(0, 0, -personalFeedHistoryDays), now.AddDate(0, 0, personalFeedHorizonDays))
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{Name: "My meals", Method: ical.MethodPublish}
+	for i := range requests {
+		meal := &requests[i].MealEvent
+		if meal.Status == model.MealEventStatusDraft || !requests[i].Status.IsActive() {
+			continue
+		}
+		calendar.Events = append(calendar.Events, s.requestEvent(meal, &requests[i]))
+	}
+	return calendar, nil
+}
+
+// mealEvent describes a meal event, with an alarm shortly before requests close
+func (s *calendarService) mealEvent(meal *model.MealEvent) ical.Event {
+	loc := mealLocation(meal, s.timeZone)
+
+	addresses := make([]string, 0, len(meal.Addresses))
+	for _, address := range meal.Addresses {
+		addresses = append(addresses, address.Address.Address)
+	}
+	menuSets := make([]string, 0, len(meal.MenuSets))
+	for _, set := range meal.MenuSets {
+		menuSets = append(menuSets, set.MenuSet.MenuSetName)
+	}
+
+	var description []string
+	if meal.Description != "" {
+		description = append(description, meal.Description)
+	}
+	if len(menuSets) > 0 {
+		description = append(description, "Menu sets: "+strings.Join(menuSets, ", "))
+	}
+	description = append(description, "Requests close: "+formatMealTime(meal.CutoffTime, loc))
+
+	event := ical.Event{
+		UID:         s.uid("meal-event", meal.ID),
+		Sequence:    meal.Sequence,
+		Start:       meal.EventDate,
+		End:         mealEnd(meal),
+		Summary:     meal.Name,
+		Description: strings.Join(description, "\n"),
+		Location:    strings.Join(addresses, ", "),
+		Status:      mealStatus(meal),
+		Updated:     meal.UpdatedAt,
+	}
+	if meal.Status.AcceptsRequests() {
+		event.Alarm = &ical.Alarm{
+			Trigger:     meal.CutoffTime.Add(-cutoffAlarmLead),
+			Description: fmt.Sprintf("Requests for %s close at %s", meal.Name, formatMealTime(meal.CutoffTime, loc)),
+		}
+	}
+	return event
+}
+
+// requestEvent describes a user's requested meal with the chosen menu set and location.
+// Its sequence grows with changes to both the meal event and the request.
+func (s *calendarService) requestEvent(meal *model.MealEvent, request *model.MealRequest) ical.Event {
+	loc := s.requestLocation(meal, request)
+
+	description := []string{
+		"Menu set: " + request.MenuSet.MenuSetName,
+		"Location: " + request.EventAddress.Address,
+	}
+	if meal.Status.AcceptsRequests() {
+		description = append(description, "Changes possible until: "+formatMealTime(meal.CutoffTime, loc))
+	}
+
+	return ical.Event{
+		UID:         s.uid("meal-request", request.ID),
+		Sequence:    meal.Sequence + request.Sequence,
+		Start:       meal.EventDate,
+		End:         mealEnd(meal),
+		Summary:     meal.Name,
+		Description: strings.Join(description, "\n"),
+		Location:    request.EventAddress.Address,
+		Status:      mealStatus(meal),
+		Updated:     latest(meal.UpdatedAt, request.UpdatedAt),
+	}
+}
+
+// requestLocation returns the time zone of the address a meal was requested for
+func (s *calendarService) requestLocation(meal *model.MealEvent, request *model.MealRequest) *time.Location {
+	return utils.LoadLocation(request.EventAddress.TimeZone, mealLocation(meal, s.timeZone).String())
+}
+
+// uid builds a globally unique event identifier that stays the same across updates
+func (s *calendarService) uid(kind string, id uint) string {
+	domain := "mealsync"
+	if parsed, err := url.Parse(s.publicURL); err == nil && parsed.Hostname() != "" {
+		domain = parsed.Hostname()
+	}
+	return fmt.Sprintf("%s-%d@%s", kind, id, domain)
+}
+
+// feedURL returns the address calendar clients subscribe to
+func (s *calendarService) feedURL(feed *model.CalendarFeed) string {
+	return fmt.Sprintf("%s/api/calendar/%s.ics", s.publicURL, feed.Token)
+}
+
+// newFeedToken generates an unguessable, URL safe feed token
+func newFeedToken() (string, error) {
+	buf := make([]byte, calendarFeedTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// mealStatus maps a meal event's lifecycle status to an iCalendar status
+func mealStatus(meal *model.MealEvent) ical.Status {
+	switch meal.Status {
+	case model.MealEventStatusConfirmed, model.MealEventStatusCompleted:
+		return ical.StatusConfirmed
+	case model.MealEventStatusCancelled:
+		return ical.StatusCancelled
+	default:
+		return ical.StatusTentative
+	}
+}
+
+// mealEnd returns when a meal event ends
+func mealEnd(meal *model.MealEvent) time.Time {
+	return meal.EventDate.Add(time.Duration(meal.EventDuration) * time.Minute)
+}
+
+// latest returns the later of two instants
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// allergenPattern matches normalized allergen names, including custom ones such as "kiwi"
+var allergenPattern = regexp.MustCompile(`^[a-z0-9_]{1,40}$`)
+
+// MenuItemFilter narrows down the menu items listed
+type MenuItemFilter struct {
+	DietaryTags      []string // items must carry all of these tags
+	ExcludeAllergens []string // items must contain none of these allergens
+}
+
+// DietaryProfile describes what a user cannot or does not eat
+type DietaryProfile struct {
+	Allergens   []string `json:"allergens" example:"peanuts,milk"`  // allergens the user avoids
+	DietaryTags []string `json:"dietary_tags" example:"vegetarian"` // tags the user's meals should carry
+}
+
+// dietaryService manages the dietary profiles of users
+type dietaryService struct {
+	userRepo repository.UserRepository
+}
+
+// NewDietaryService creates a new instance of DietaryService
+func NewDietaryService(userRepo repository.UserRepository) DietaryService {
+	return &dietaryService{
+		userRepo: userRepo,
+	}
+}
+
+// GetDietaryProfile retrieves the allergens and dietary tags a user declared
+func (s *dietaryService) GetDietaryProfile(ctx context.Context, userID uint) (*DietaryProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+	return &DietaryProfile{
+		Allergens:   orEmpty(user.Allergens),
+		DietaryTags: orEmpty(user.DietaryTags),
+	}, nil
+}
+
+// UpdateDietaryProfile replaces the allergens and dietary tags a user declared. Meal requests
+// for items that do not fit the profile are accepted with warnings.
+func (s *dietaryService) UpdateDietaryProfile(ctx context.Context, userID uint, profile *DietaryProfile) (*DietaryProfile, error) {
+	if profile == nil {
+		return nil, errors.NewValidationError("profile cannot be nil", nil)
+	}
+
+	allergens := normalizeDietaryList(profile.Allergens)
+	tags := normalizeDietaryList(profile.DietaryTags)
+	fields := validateAllergens(allergens, "allergens")
+	fields = append(fields, validateDietaryTags(tags, "dietary_tags")...)
+	if len(fields) > 0 {
+		return nil, errors.NewValidationError("invalid dietary profile", nil).WithFields(fields...)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+	user.Allergens = allergens
+	user.DietaryTags = tags
+	user.UpdatedBy = userID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.NewInternalError("failed to update dietary profile", err)
+	}
+
+	return s.GetDietaryProfile(ctx, userID)
+}
+
+// normalizeDietaryInfo cleans up the allergens and dietary tags of a menu item, adds the tags
+// implied by others and reports unknown tags and tags that contradict the allergens
+func normalizeDietaryInfo(item *model.MenuItem) []errors.FieldError {
+	item.Allergens = normalizeDietaryList(item.Allergens)
+	item.DietaryTags = normalizeDietaryList(item.DietaryTags)
+	for _, tag := range item.DietaryTags {
+		for _, implied := range model.DietaryImplications[tag] {
+			if !item.DietaryTags.Contains(implied) {
+				item.DietaryTags = append(item.DietaryTags, implied)
+			}
+		}
+	}
+
+	fields := validateAllergens(item.Allergens, "allergens")
+	fields = append(fields, validateDietaryTags(item.DietaryTags, "dietary_tags")...)
+	for _, tag := range item.DietaryTags {
+		for _, allergen := range model.DietaryConflicts[tag] {
+			if item.Allergens.Contains(allergen) {
+				fields = append(fields, errors.FieldError{
+					Field:   "dietary_tags",
+					Message: fmt.Sprintf("%s items cannot contain %s", dietaryLabel(tag), allergen),
+				})
+			}
+		}
+	}
+	return fields
+}
+
+// validateAllergens reports allergen names that cannot be stored
+func validateAllergens(allergens model.StringList, field string) []errors.FieldError {
+	var fields []errors.FieldError
+	for i, allergen := range allergens {
+		if !allergenPattern.MatchString(allergen) {
+			fields = append(fields, errors.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Message: "allergen names use up to 40 letters, digits and underscores",
+			})
+		}
+	}
+	return fields
+}
+
+// validateDietaryTags reports dietary tags that are not known
+func validateDietaryTags(tags model.StringList, field string) []errors.FieldError {
+	var fields []errors.FieldError
+	for i, tag := range tags {
+		if !model.IsDietaryTag(tag) {
+			fields = append(fields, errors.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Message: "must be one of " + strings.Join(model.KnownDietaryTags, ", "),
+			})
+		}
+	}
+	return fields
+}
+
+// normalizeDietaryList lowercases names, writes "Gluten-free" as "gluten_free" and drops blanks and duplicates
+func normalizeDietaryList(values []string) model.StringList {
+	normalized := model.StringList{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		value = strings.NewReplacer(" ", "_", "-", "_").Replace(value)
+		if value != "" && !normalized.Contains(value) {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
+
+// filterMenuItems keeps the menu items that carry all requested tags and none of the excluded allergens
+func filterMenuItems(items []model.MenuItem, filter MenuItemFilter) []model.MenuItem {
+	tags := normalizeDietaryList(filter.DietaryTags)
+	excluded := normalizeDietaryList(filter.ExcludeAllergens)
+	if len(tags) == 0 && len(excluded) == 0 {
+		return items
+	}
+
+	filtered := make([]model.MenuItem, 0, len(items))
+	for _, item := range items {
+		if hasAllTags(item, tags) && len(sharedAllergens(item, excluded)) == 0 {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// dietaryWarnings describes how menu items clash with a user's dietary profile
+func dietaryWarnings(user *model.User, items []model.MenuItem) []string {
+	var warnings []string
+	for _, item := range items {
+		if found := sharedAllergens(item, user.Allergens); len(found) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s contains %s, which you avoid", item.Name, strings.Join(found, ", ")))
+		}
+		for _, tag := range user.DietaryTags {
+			if !item.DietaryTags.Contains(tag) {
+				warnings = append(warnings, fmt.Sprintf("%s is not marked %s", item.Name, dietaryLabel(tag)))
+			}
+		}
+	}
+	return warnings
+}
+
+// hasAllTags reports whether a menu item carries every tag
+func hasAllTags(item model.MenuItem, tags model.StringList) bool {
+	for _, tag := range tags {
+		if !item.DietaryTags.Contains(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedAllergens returns the allergens of a menu item that are in the list
+func sharedAllergens(item model.MenuItem, allergens model.StringList) []string {
+	var found []string
+	for _, allergen := range item.Allergens {
+		if allergens.Contains(allergen) {
+			found = append(found, allergen)
+		}
+	}
+	return found
+}
+
+// dietaryLabel turns a tag such as gluten_free into the words used in messages
+func dietaryLabel(tag string) string {
+	return strings.ReplaceAll(tag, "_", "-")
+}
+
+// orEmpty returns an empty list instead of nil, so that it is encoded as []
+func orEmpty(values model.StringList) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
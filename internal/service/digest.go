@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+// Digest represents the summary sent to a user instead of individual notifications
+type Digest struct {
+	UserID            uint                    `json:"user_id"`
+	Frequency         model.DigestFrequency   `json:"frequency"`
+	TimeZone          string                  `json:"time_zone"`
+	PeriodStart       time.Time               `json:"period_start"`
+	PeriodEnd         time.Time               `json:"period_end"`
+	UnrequestedEvents []DigestEvent           `json:"unrequested_events"`
+	ConfirmedMeals    []DigestMeal            `json:"confirmed_meals"`
+	CommentReplies    []DigestCommentReply    `json:"comment_replies"`
+	Notifications     []DigestNotificationRef `json:"notifications"`
+}
+
+// DigestEvent summarizes an upcoming meal event the user has not requested
+type DigestEvent struct {
+	MealEventID uint      `json:"meal_event_id"`
+	Name        string    `json:"name"`
+	EventDate   time.Time `json:"event_date"`
+	CutoffTime  time.Time `json:"cutoff_time"`
+}
+
+// DigestMeal summarizes a confirmed meal request
+type DigestMeal struct {
+	MealRequestID uint      `json:"meal_request_id"`
+	MealEventID   uint      `json:"meal_event_id"`
+	Name          string    `json:"name"`
+	EventDate     time.Time `json:"event_date"`
+	MenuSet       string    `json:"menu_set"`
+	Address       string    `json:"address"`
+}
+
+// DigestCommentReply summarizes a reply to one of the user's comments
+type DigestCommentReply struct {
+	CommentID   uint      `json:"comment_id"`
+	ParentID    uint      `json:"parent_id"`
+	MealEventID uint      `json:"meal_event_id"`
+	MenuItem    string    `json:"menu_item"`
+	Author      string    `json:"author"`
+	Comment     string    `json:"comment"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DigestNotificationRef references a notification rolled up into the digest
+type DigestNotificationRef struct {
+	NotificationID uint                   `json:"notification_id"`
+	Type           model.NotificationType `json:"type"`
+	Message        string                 `json:"message"`
+}
+
+// IsEmpty reports whether the digest has nothing worth sending
+func (d *Digest) IsEmpty() bool {
+	return len(d.UnrequestedEvents) == 0 &&
+		len(d.ConfirmedMeals) == 0 &&
+		len(d.CommentReplies) == 0 &&
+		len(d.Notifications) == 0
+}
+
+// Summary returns a short human readable description of the digest
+func (d *Digest) Summary() string {
+	return fmt.Sprintf("Your %s digest: %d upcoming meals to request, %d confirmed meals, %d new replies, %d updates",
+		d.Frequency, len(d.UnrequestedEvents), len(d.ConfirmedMeals), len(d.CommentReplies), len(d.Notifications))
+}
+
+// digestService handles business logic for notification digests
+type digestService struct {
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	mealRepo         repository.MealEventRepository
+	requestRepo      repository.MealRequestRepository
+	commentRepo      repository.MenuItemCommentRepository
+	digestHour       int
+	digestWeekday    time.Weekday
+	timeZone         string
+}
+
+// NewDigestService creates a new instance of DigestService
+func NewDigestService(
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	commentRepo repository.MenuItemCommentRepository,
+	digestHour int,
+	digestWeekday time.Weekday,
+	timeZone string,
+) DigestService {
+	return &digestService{
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		mealRepo:         mealRepo,
+		requestRepo:      requestRepo,
+		commentRepo:      commentRepo,
+		digestHour:       digestHour,
+		digestWeekday:    digestWeekday,
+		timeZone:         timeZone,
+	}
+}
+
+// UpdateDigestPreference sets how often a user receives digests
+func (s *digestService) UpdateDigestPreference(ctx context.Context, userID uint, frequency model.DigestFrequency) error {
+	switch frequency {
+	case model.DigestFrequencyNone, model.DigestFrequencyDaily, model.DigestFrequencyWeekly:
+	default:
+		return errors.NewValidationError("invalid digest frequency", nil)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.NewNotFoundError("user not found", err)
+	}
+
+	user.DigestFrequency = frequency
+	user.UpdatedBy = userID
+	return s.userRepo.Update(ctx, user)
+}
+
+// BuildDigest assembles the digest a user would receive at the given time without sending it
+func (s *digestService) BuildDigest(ctx context.Context, userID uint, now time.Time) (*Digest, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+
+	frequency := user.DigestFrequency
+	if frequency == model.DigestFrequencyNone || frequency == "" {
+		frequency = model.DigestFrequencyDaily
+	}
+
+	return s.buildDigest(ctx, user, frequency, now)
+}
+
+// SendDueDigests sends digests to every user whose digest is due at the given time.
+// Digests go out at the digest hour of each user's own time zone. A digest that fails for one
+// user is logged and does not hold back the digests of the others.
+func (s *digestService) SendDueDigests(ctx context.Context, now time.Time) error {
+	frequencies := []model.DigestFrequency{model.DigestFrequencyDaily, model.DigestFrequencyWeekly}
+
+	for _, frequency := range frequencies {
+		users, err := s.userRepo.FindByDigestFrequency(ctx, frequency)
+		if err != nil {
+			return err
+		}
+
+		for i := range users {
+			local := now.In(utils.LoadLocation(users[i].TimeZone, s.timeZone))
+			if !isDigestHour(local, frequency, s.digestHour, s.digestWeekday) {
+				continue
+			}
+			if !isDigestDue(&users[i], frequency, now) {
+				continue
+			}
+			if err := s.sendDigest(ctx, &users[i], frequency, now); err != nil {
+				log.Printf("digest: failed to send %s digest to user %d: %v", frequency, users[i].ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sendDigest builds and stores a digest notification and marks the rolled-up notifications as delivered
+func (s *digestService) sendDigest(ctx context.Context, user *model.User, frequency model.DigestFrequency, now time.Time) error {
+	digest, err := s.buildDigest(ctx, user, frequency, now)
+	if err != nil {
+		return err
+	}
+
+	if !digest.IsEmpty() {
+		payload, err := json.Marshal(digest)
+		if err != nil {
+			return err
+		}
+
+		notification := &model.Notification{
+			UserID:    user.ID,
+			Type:      model.NotificationTypeDigest,
+			Payload:   payload,
+			Message:   digest.Summary(),
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(digest.Notifications))
+		for _, ref := range digest.Notifications {
+			ids = append(ids, ref.NotificationID)
+		}
+		if err := s.notificationRepo.MarkManyAsDelivered(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	return s.userRepo.UpdateLastDigestAt(ctx, user.ID, now)
+}
+
+// buildDigest collects the digest sections for a user over the period implied by the frequency
+func (s *digestService) buildDigest(ctx context.Context, user *model.User, frequency model.DigestFrequency, now time.Time) (*Digest, error) {
+	periodEnd := now.Add(digestPeriod(frequency))
+	digest := &Digest{
+		UserID:            user.ID,
+		Frequency:         frequency,
+		TimeZone:          utils.LoadLocation(user.TimeZone, s.timeZone).String(),
+		PeriodStart:       now,
+		PeriodEnd:         periodEnd,
+		UnrequestedEvents: []DigestEvent{},
+		ConfirmedMeals:    []DigestMeal{},
+		CommentReplies:    []DigestCommentReply{},
+		Notifications:     []DigestNotificationRef{},
+	}
+
+	requests, err := s.requestRepo.FindByUserIDAndEventDateRange(ctx, user.ID, now, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[uint]bool, len(requests))
+	for _, request := range requests {
+		if !request.Status.IsActive() {
+			continue
+		}
+		requested[request.MealEventID] = true
+		if request.ConfirmedAt == nil {
+			continue
+		}
+		digest.ConfirmedMeals = append(digest.ConfirmedMeals, DigestMeal{
+			MealRequestID: request.ID,
+			MealEventID:   request.MealEventID,
+			Name:          request.MealEvent.Name,
+			EventDate:     request.MealEvent.EventDate,
+			MenuSet:       request.MenuSet.MenuSetName,
+			Address:       request.EventAddress.Address,
+		})
+	}
+
+	events, err := s.mealRepo.FindByDateRange(ctx, now, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if !event.Status.AcceptsRequests() || requested[event.ID] || now.After(event.CutoffTime) {
+			continue
+		}
+		digest.UnrequestedEvents = append(digest.UnrequestedEvents, DigestEvent{
+			MealEventID: event.ID,
+			Name:        event.Name,
+			EventDate:   event.EventDate,
+			CutoffTime:  event.CutoffTime,
+		})
+	}
+
+	since := now.Add(-digestPeriod(frequency))
+	if user.LastDigestAt != nil {
+		since = *user.LastDigestAt
+	}
+
+	replies, err := s.commentRepo.FindRepliesToUserSince(ctx, user.ID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reply := range replies {
+		var parentID uint
+		if reply.ParentID != nil {
+			parentID = *reply.ParentID
+		}
+		digest.CommentReplies = append(digest.CommentReplies, DigestCommentReply{
+			CommentID:   reply.ID,
+			ParentID:    parentID,
+			MealEventID: reply.MealEventID,
+			MenuItem:    reply.MenuItem.Name,
+			Author:      reply.User.Name,
+			Comment:     reply.Comment,
+			CreatedAt:   reply.CreatedAt,
+		})
+	}
+
+	pending, err := s.notificationRepo.FindUndeliveredByTypes(ctx, user.ID, model.DigestibleTypes, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, notification := range pending {
+		digest.Notifications = append(digest.Notifications, DigestNotificationRef{
+			NotificationID: notification.ID,
+			Type:           notification.Type,
+			Message:        notification.Message,
+		})
+	}
+
+	return digest, nil
+}
+
+// digestPeriod returns the look-ahead window covered by a digest of the given frequency
+func digestPeriod(frequency model.DigestFrequency) time.Duration {
+	if frequency == model.DigestFrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// isDigestHour reports whether a user's local time is when digests of the given frequency go out
+func isDigestHour(local time.Time, frequency model.DigestFrequency, hour int, weekday time.Weekday) bool {
+	if local.Hour() != hour {
+		return false
+	}
+	return frequency != model.DigestFrequencyWeekly || local.Weekday() == weekday
+}
+
+// isDigestDue reports whether enough time has passed since the user's last digest.
+// A small tolerance keeps hourly scheduling jitter from skipping a day.
+func isDigestDue(user *model.User, frequency model.DigestFrequency, now time.Time) bool {
+	if user.LastDigestAt == nil {
+		return true
+	}
+	return now.Sub(*user.LastDigestAt) >= digestPeriod(frequency)-time.Hour
+}
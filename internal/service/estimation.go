@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// EstimateGrouping selects how meal estimates are aggregated
+type EstimateGrouping string
+
+const (
+	EstimateByEvent    EstimateGrouping = "event"
+	EstimateByMealType EstimateGrouping = "meal_type"
+)
+
+// MenuSetEstimate counts the meals requested for one menu set
+type MenuSetEstimate struct {
+	MenuSetID   uint   `json:"menu_set_id"`
+	MenuSetName string `json:"menu_set_name"`
+	Requests    int    `json:"requests"`
+	Guests      int    `json:"guests"`
+}
+
+// AllergenEstimate counts the meals that contain an allergen
+type AllergenEstimate struct {
+	Allergen string `json:"allergen" example:"milk"`
+	Meals    int    `json:"meals"` // employee and guest meals
+}
+
+// MealEstimate summarizes the meals to prepare for one meal event or one meal type
+type MealEstimate struct {
+	MealType    model.MealType     `json:"meal_type"`
+	MealEventID *uint              `json:"meal_event_id,omitempty"`
+	EventName   string             `json:"event_name,omitempty"`
+	EventDate   *time.Time         `json:"event_date,omitempty"`
+	Events      int                `json:"events"`
+	Requests    int                `json:"requests"` // employees
+	Guests      int                `json:"guests"`
+	MenuSets    []MenuSetEstimate  `json:"menu_sets"`
+	Allergens   []AllergenEstimate `json:"allergens"` // most common first
+}
+
+// estimationService handles meal quantity estimation
+type estimationService struct {
+	mealRepo    repository.MealEventRepository
+	requestRepo repository.MealRequestRepository
+	guestRepo   repository.GuestRequestRepository
+	menuRepo    repository.MenuSetRepository
+}
+
+// NewEstimationService creates a new instance of EstimationService
+func NewEstimationService(
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	guestRepo repository.GuestRequestRepository,
+	menuRepo repository.MenuSetRepository,
+) EstimationService {
+	return &estimationService{
+		mealRepo:    mealRepo,
+		requestRepo: requestRepo,
+		guestRepo:   guestRepo,
+		menuRepo:    menuRepo,
+	}
+}
+
+// GetEstimates counts the requested meals of the events within a date range, per event or per meal type.
+// Guests are counted separately from employees. Draft and cancelled events as well as rejected
+// and cancelled requests are left out. Each meal also counts once towards every allergen among
+// the items chosen for it, or among all items of its menu set when none were chosen.
+func (s *estimationService) GetEstimates(ctx context.Context, startDate, endDate time.Time, groupBy EstimateGrouping) ([]MealEstimate, error) {
+	if groupBy != EstimateByEvent && groupBy != EstimateByMealType {
+		return nil, errors.NewValidationError("group_by must be event or meal_type", nil)
+	}
+
+	meals, err := s.mealRepo.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to load meal events", err)
+	}
+	requests, err := s.requestRepo.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to load meal requests", err)
+	}
+
+	requestsByEvent := make(map[uint][]model.MealRequest)
+	for _, request := range requests {
+		// Late requests are served from surplus, so they only count once approved
+		if !request.Status.IsActive() || isUndecidedLate(&request) {
+			continue
+		}
+		requestsByEvent[request.MealEventID] = append(requestsByEvent[request.MealEventID], request)
+	}
+
+	guests, err := s.guestRepo.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to load guest requests", err)
+	}
+	guestsByEvent := make(map[uint][]model.GuestRequest)
+	for _, guest := range guests {
+		if guest.Status.IsActive() {
+			guestsByEvent[guest.MealEventID] = append(guestsByEvent[guest.MealEventID], guest)
+		}
+	}
+
+	setItems := make(map[uint]map[uint]model.MenuItem)
+	allergenCounts := make(map[int]map[string]int)
+	countAllergens := func(index int, menuSetID uint, chosen []uint) error {
+		items, ok := setItems[menuSetID]
+		if !ok {
+			found, err := s.menuRepo.FindMenuItems(ctx, menuSetID)
+			if err != nil {
+				return errors.NewInternalError("failed to load menu items", err)
+			}
+			items = menuItemsByID(found)
+			setItems[menuSetID] = items
+		}
+		allergens := make(map[string]bool)
+		for id, item := range items {
+			if len(chosen) > 0 && !containsID(chosen, id) {
+				continue
+			}
+			for _, allergen := range item.Allergens {
+				allergens[allergen] = true
+			}
+		}
+		if allergenCounts[index] == nil {
+			allergenCounts[index] = make(map[string]int)
+		}
+		for allergen := range allergens {
+			allergenCounts[index][allergen]++
+		}
+		return nil
+	}
+
+	var estimates []MealEstimate
+	byType := make(map[model.MealType]int)
+	for _, meal := range meals {
+		if meal.Status == model.MealEventStatusDraft || meal.Status == model.MealEventStatusCancelled {
+			continue
+		}
+
+		var estimate *MealEstimate
+		index := len(estimates)
+		if groupBy == EstimateByMealType {
+			existing, ok := byType[meal.MealType]
+			if !ok {
+				byType[meal.MealType] = index
+				estimates = append(estimates, MealEstimate{MealType: meal.MealType})
+			} else {
+				index = existing
+			}
+			estimate = &estimates[index]
+		} else {
+			mealID, eventDate := meal.ID, meal.EventDate
+			estimates = append(estimates, MealEstimate{
+				MealType:    meal.MealType,
+				MealEventID: &mealID,
+				EventName:   meal.Name,
+				EventDate:   &eventDate,
+			})
+			estimate = &estimates[len(estimates)-1]
+		}
+
+		estimate.Events++
+		for _, request := range requestsByEvent[meal.ID] {
+			estimate.Requests++
+			menuSetEstimate(estimate, request.MenuSetID, request.MenuSet.MenuSetName).Requests++
+			var chosen []uint
+			for _, item := range request.RequestItems {
+				if item.IsSelected && item.MenuSetID == request.MenuSetID {
+					chosen = append(chosen, item.MenuItemID)
+				}
+			}
+			if err := countAllergens(index, request.MenuSetID, chosen); err != nil {
+				return nil, err
+			}
+		}
+		for _, guest := range guestsByEvent[meal.ID] {
+			estimate.Guests++
+			menuSetEstimate(estimate, guest.MenuSetID, guest.MenuSet.MenuSetName).Guests++
+			var chosen []uint
+			for _, item := range guest.Items {
+				if item.IsSelected {
+					chosen = append(chosen, item.MenuItemID)
+				}
+			}
+			if err := countAllergens(index, guest.MenuSetID, chosen); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range estimates {
+		estimates[i].Allergens = allergenEstimates(allergenCounts[i])
+	}
+	return estimates, nil
+}
+
+// allergenEstimates lists allergen counts, most common first
+func allergenEstimates(counts map[string]int) []AllergenEstimate {
+	allergens := make([]AllergenEstimate, 0, len(counts))
+	for allergen, meals := range counts {
+		allergens = append(allergens, AllergenEstimate{Allergen: allergen, Meals: meals})
+	}
+	sort.Slice(allergens, func(i, j int) bool {
+		if allergens[i].Meals != allergens[j].Meals {
+			return allergens[i].Meals > allergens[j].Meals
+		}
+		return allergens[i].Allergen < allergens[j].Allergen
+	})
+	return allergens
+}
+
+// containsID reports whether the list holds the ID
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// menuSetEstimate returns the count of a menu set within an estimate, adding it when missing
+func menuSetEstimate(estimate *MealEstimate, menuSetID uint, menuSetName string) *MenuSetEstimate {
+	for i := range estimate.MenuSets {
+		if estimate.MenuSets[i].MenuSetID == menuSetID {
+			return &estimate.MenuSets[i]
+		}
+	}
+	estimate.MenuSets = append(estimate.MenuSets, MenuSetEstimate{
+		MenuSetID:   menuSetID,
+		MenuSetName: menuSetName,
+	})
+	return &estimate.MenuSets[len(estimate.MenuSets)-1]
+}
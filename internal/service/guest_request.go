@@ -0,0 +1,480 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+// Attendee is one row of a meal event's attendee export
+type Attendee struct {
+	Type         string `json:"type" enums:"employee,guest"`
+	Name         string `json:"name"`
+	EmployeeID   string `json:"employee_id,omitempty"`
+	Department   string `json:"department"`
+	Host         string `json:"host,omitempty"` // the employee who invited a guest
+	Organization string `json:"organization,omitempty"`
+	MenuSet      string `json:"menu_set"`
+	Address      string `json:"address"`
+	Status       string `json:"status"`
+	Notes        string `json:"notes,omitempty"`
+}
+
+// EventAttendees lists everyone expected at a meal event, employees and guests separately
+type EventAttendees struct {
+	MealEventID uint       `json:"meal_event_id"`
+	EventName   string     `json:"event_name"`
+	EventDate   time.Time  `json:"event_date"`
+	Employees   []Attendee `json:"employees"`
+	Guests      []Attendee `json:"guests"`
+}
+
+// guestRequestService handles meals requested for visitors without accounts
+type guestRequestService struct {
+	guestRepo    repository.GuestRequestRepository
+	mealRepo     repository.MealEventRepository
+	requestRepo  repository.MealRequestRepository
+	menuRepo     repository.MenuSetRepository
+	userRepo     repository.UserRepository
+	notifService NotificationService
+	timeZone     string
+}
+
+// NewGuestRequestService creates a new instance of GuestRequestService
+func NewGuestRequestService(
+	guestRepo repository.GuestRequestRepository,
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	menuRepo repository.MenuSetRepository,
+	userRepo repository.UserRepository,
+	notifService NotificationService,
+	timeZone string,
+) GuestRequestService {
+	return &guestRequestService{
+		guestRepo:    guestRepo,
+		mealRepo:     mealRepo,
+		requestRepo:  requestRepo,
+		menuRepo:     menuRepo,
+		userRepo:     userRepo,
+		notifService: notifService,
+		timeZone:     timeZone,
+	}
+}
+
+// GetGuestRequests retrieves the guests an employee invited
+func (s *guestRequestService) GetGuestRequests(ctx context.Context, userID uint) ([]model.GuestRequest, error) {
+	return s.guestRepo.FindByHostID(ctx, userID)
+}
+
+// GetEventGuests retrieves the guests of a meal event
+func (s *guestRequestService) GetEventGuests(ctx context.Context, mealEventID uint) ([]model.GuestRequest, error) {
+	if _, err := s.mealRepo.FindByID(ctx, mealEventID); err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	return s.guestRepo.FindByMealEventID(ctx, mealEventID)
+}
+
+// GetPendingGuestRequests retrieves the guests waiting for an admin's approval
+func (s *guestRequestService) GetPendingGuestRequests(ctx context.Context) ([]model.GuestRequest, error) {
+	return s.guestRepo.FindByStatus(ctx, model.GuestRequestStatusAwaitingApproval)
+}
+
+// GetGuestRequest retrieves a guest request of the host, or any for admins
+func (s *guestRequestService) GetGuestRequest(ctx context.Context, id uint, userID uint, isAdmin bool) (*model.GuestRequest, error) {
+	guest, err := s.guestRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("guest request not found", err)
+	}
+	if !isAdmin && guest.HostUserID != userID {
+		return nil, errors.NewForbiddenError("unauthorized to access this guest request", nil)
+	}
+	return guest, nil
+}
+
+// CreateGuestRequest requests a meal for a guest. The caller hosts the guest; admins may name another host.
+// Guests beyond the host department's monthly quota wait for an admin's approval.
+func (s *guestRequestService) CreateGuestRequest(ctx context.Context, guest *model.GuestRequest, userID uint, isAdmin bool) error {
+	if !isAdmin || guest.HostUserID == 0 {
+		guest.HostUserID = userID
+	}
+	host, err := s.userRepo.FindByID(ctx, guest.HostUserID)
+	if err != nil || !host.IsActive {
+		return errors.NewValidationError("invalid guest request", err).
+			WithFields(errors.FieldError{Field: "host_user_id", Message: "host must be an active employee"})
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, guest.MealEventID)
+	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+	if err := s.validateGuestRequest(ctx, meal, guest, 0, isAdmin); err != nil {
+		return err
+	}
+
+	guest.ID = 0
+	guest.Status = model.GuestRequestStatusApproved
+	overQuota, err := s.exceedsQuota(ctx, host.Department, meal)
+	if err != nil {
+		return errors.NewInternalError("failed to check the department's guest quota", err)
+	}
+	if overQuota {
+		guest.Status = model.GuestRequestStatusAwaitingApproval
+	}
+	guest.ReviewedBy = nil
+	guest.ReviewNote = ""
+	guest.CreatedBy = userID
+	guest.UpdatedBy = userID
+	if err := s.guestRepo.SaveWithItems(ctx, guest); err != nil {
+		return errors.NewInternalError("failed to save guest request", err)
+	}
+
+	if overQuota {
+		s.notifyAdmins(ctx, fmt.Sprintf("%s (%s) invited %s to %s on %s beyond the department's guest quota. Please approve or reject the request.",
+			host.Name, host.Department, guest.GuestName, meal.Name, formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone))))
+	}
+	return nil
+}
+
+// UpdateGuestRequest changes a guest's details or meal if the request is still at the version the
+// client read. Hosts may do so until the cutoff.
+func (s *guestRequestService) UpdateGuestRequest(ctx context.Context, id uint, version int, guest *model.GuestRequest, userID uint, isAdmin bool) error {
+	existing, err := s.GetGuestRequest(ctx, id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("guest request", existing.Version, version); err != nil {
+		return err
+	}
+	if !existing.Status.IsActive() {
+		return errors.NewValidationError("cannot edit a "+string(existing.Status)+" guest request", nil)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, existing.MealEventID)
+	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+	guest.MealEventID = existing.MealEventID
+	if err := s.validateGuestRequest(ctx, meal, guest, existing.ID, isAdmin); err != nil {
+		return err
+	}
+
+	guest.ID = existing.ID
+	guest.HostUserID = existing.HostUserID
+	guest.Status = existing.Status
+	guest.ReviewedBy = existing.ReviewedBy
+	guest.ReviewNote = existing.ReviewNote
+	guest.CreatedBy = existing.CreatedBy
+	guest.CreatedAt = existing.CreatedAt
+	guest.UpdatedBy = userID
+	guest.Version = version
+	if err := s.guestRepo.SaveWithItems(ctx, guest); err != nil {
+		return versionError("guest request", "save", err)
+	}
+	return nil
+}
+
+// CancelGuestRequest cancels a guest's meal if the request is still at the version the client read.
+// Hosts may do so until the cutoff.
+func (s *guestRequestService) CancelGuestRequest(ctx context.Context, id uint, version int, userID uint, isAdmin bool) error {
+	guest, err := s.GetGuestRequest(ctx, id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("guest request", guest.Version, version); err != nil {
+		return err
+	}
+	if !guest.Status.IsActive() {
+		return errors.NewValidationError("cannot cancel a "+string(guest.Status)+" guest request", nil)
+	}
+
+	if !isAdmin {
+		meal, err := s.mealRepo.FindByID(ctx, guest.MealEventID)
+		if err != nil {
+			return errors.NewNotFoundError("meal event not found", err)
+		}
+		if time.Now().After(meal.CutoffTime) {
+			return errors.NewValidationError("cutoff time has passed", nil)
+		}
+	}
+
+	from := guest.Status
+	guest.Status = model.GuestRequestStatusCancelled
+	guest.UpdatedBy = userID
+	if err := s.guestRepo.UpdateStatus(ctx, guest, from); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return staleVersionError("guest request")
+		}
+		return errors.NewInternalError("failed to cancel guest request", err)
+	}
+	return nil
+}
+
+// ReviewGuestRequest approves or rejects a guest that exceeds the host department's quota and tells the host
+func (s *guestRequestService) ReviewGuestRequest(ctx context.Context, id uint, approve bool, note string, adminID uint) (*model.GuestRequest, error) {
+	guest, err := s.guestRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("guest request not found", err)
+	}
+	if guest.Status != model.GuestRequestStatusAwaitingApproval {
+		return nil, errors.NewValidationError("guest request is not awaiting approval", nil)
+	}
+
+	guest.Status = model.GuestRequestStatusRejected
+	if approve {
+		guest.Status = model.GuestRequestStatusApproved
+	}
+	guest.ReviewedBy = &adminID
+	guest.ReviewNote = note
+	guest.UpdatedBy = adminID
+	if err := s.guestRepo.UpdateStatus(ctx, guest, model.GuestRequestStatusAwaitingApproval); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return nil, errors.NewConflictError("guest request was changed by someone else", err)
+		}
+		return nil, errors.NewInternalError("failed to review guest request", err)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, guest.MealEventID)
+	if err != nil {
+		return guest, nil
+	}
+	message := fmt.Sprintf("Your guest %s for %s on %s was %s.",
+		guest.GuestName, meal.Name, formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone)), guest.Status)
+	if note != "" {
+		message += " Note: " + note
+	}
+	if err := s.notifService.CreateMealConfirmationNotification(ctx, guest.HostUserID, meal.ID, message); err != nil {
+		return nil, err
+	}
+	return guest, nil
+}
+
+// ListGuestQuotas retrieves the guest quotas of all departments
+func (s *guestRequestService) ListGuestQuotas(ctx context.Context) ([]model.DepartmentGuestQuota, error) {
+	return s.guestRepo.FindQuotas(ctx)
+}
+
+// SetGuestQuota creates or replaces a department's monthly guest quota
+func (s *guestRequestService) SetGuestQuota(ctx context.Context, department string, monthlyLimit int, userID uint) (*model.DepartmentGuestQuota, error) {
+	department = strings.TrimSpace(department)
+	if department == "" {
+		return nil, errors.NewValidationError("department is required", nil)
+	}
+	if monthlyLimit < 0 {
+		return nil, errors.NewValidationError("monthly limit must not be negative", nil)
+	}
+
+	quota, err := s.guestRepo.FindQuota(ctx, department)
+	if err != nil {
+		quota = &model.DepartmentGuestQuota{Department: department, CreatedBy: userID}
+	}
+	quota.MonthlyLimit = monthlyLimit
+	quota.UpdatedBy = userID
+	if err := s.guestRepo.SaveQuota(ctx, quota); err != nil {
+		return nil, errors.NewInternalError("failed to save guest quota", err)
+	}
+	return quota, nil
+}
+
+// DeleteGuestQuota removes a department's guest quota, so that its guests no longer need approval
+func (s *guestRequestService) DeleteGuestQuota(ctx context.Context, department string) error {
+	quota, err := s.guestRepo.FindQuota(ctx, department)
+	if err != nil {
+		return errors.NewNotFoundError("no guest quota for this department", err)
+	}
+	return s.guestRepo.DeleteQuota(ctx, quota)
+}
+
+// GetAttendees lists the employees and guests expected at a meal event
+func (s *guestRequestService) GetAttendees(ctx context.Context, mealEventID uint) (*EventAttendees, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	requests, err := s.requestRepo.FindByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	guests, err := s.guestRepo.FindByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch guest requests", err)
+	}
+
+	attendees := &EventAttendees{
+		MealEventID: meal.ID,
+		EventName:   meal.Name,
+		EventDate:   meal.EventDate,
+		Employees:   []Attendee{},
+		Guests:      []Attendee{},
+	}
+	for _, request := range requests {
+		if !request.Status.IsActive() {
+			continue
+		}
+		attendees.Employees = append(attendees.Employees, Attendee{
+			Type:       "employee",
+			Name:       request.User.Name,
+			EmployeeID: request.User.EmployeeID,
+			Department: request.User.Department,
+			MenuSet:    request.MenuSet.MenuSetName,
+			Address:    request.EventAddress.Address,
+			Status:     string(request.Status),
+		})
+	}
+	for _, guest := range guests {
+		if !guest.Status.IsActive() {
+			continue
+		}
+		attendees.Guests = append(attendees.Guests, Attendee{
+			Type:         "guest",
+			Name:         guest.GuestName,
+			Department:   guest.Host.Department,
+			Host:         guest.Host.Name,
+			Organization: guest.Organization,
+			MenuSet:      guest.MenuSet.MenuSetName,
+			Address:      guest.EventAddress.Address,
+			Status:       string(guest.Status),
+			Notes:        guest.DietaryNotes,
+		})
+	}
+	return attendees, nil
+}
+
+// validateGuestRequest checks a guest's meal against the event and fills in the set's items if none were chosen.
+// excludeID leaves the guest's own seat out of the capacity check when it is edited.
+func (s *guestRequestService) validateGuestRequest(ctx context.Context, meal *model.MealEvent, guest *model.GuestRequest, excludeID uint, isAdmin bool) error {
+	if !meal.IsActive || !meal.Status.AcceptsRequests() {
+		return errors.NewValidationError("meal event is not open for requests", nil)
+	}
+	if !isAdmin && time.Now().After(meal.CutoffTime) {
+		return errors.NewValidationError("cutoff time has passed", nil)
+	}
+
+	var fields []errors.FieldError
+	guest.GuestName = strings.TrimSpace(guest.GuestName)
+	if guest.GuestName == "" {
+		fields = append(fields, errors.FieldError{Field: "guest_name", Message: "guest name is required"})
+	}
+	fields = append(fields, validateRequestChoice(meal, guest.MenuSetID, guest.EventAddressID)...)
+	if len(fields) > 0 {
+		return errors.NewValidationError("invalid guest request", nil).WithFields(fields...)
+	}
+
+	menuItems, err := s.menuRepo.FindMenuItems(ctx, guest.MenuSetID)
+	if err != nil {
+		return errors.NewInternalError("failed to fetch menu items", err)
+	}
+	setItems := menuItemsByID(menuItems)
+	if len(guest.Items) == 0 {
+		for _, item := range menuItems {
+			guest.Items = append(guest.Items, model.GuestRequestItem{MenuItemID: item.ID, IsSelected: true, Quantity: item.DefaultQuantity()})
+		}
+	}
+	for i := range guest.Items {
+		item := &guest.Items[i]
+		menuItem, ok := setItems[item.MenuItemID]
+		if !ok {
+			fields = append(fields, errors.FieldError{Field: fmt.Sprintf("items[%d].menu_item_id", i), Message: "menu item is not part of the menu set"})
+			continue
+		}
+		if item.Quantity <= 0 {
+			item.Quantity = menuItem.DefaultQuantity()
+		}
+		if message := quantityProblem(menuItem, item.Quantity); message != "" {
+			fields = append(fields, errors.FieldError{Field: fmt.Sprintf("items[%d].quantity", i), Message: message})
+		}
+	}
+	if len(fields) > 0 {
+		return errors.NewValidationError("invalid guest request", nil).WithFields(fields...)
+	}
+
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	guests, err := s.guestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return errors.NewInternalError("failed to fetch guest requests", err)
+	}
+	for i := range guests {
+		if guests[i].ID == excludeID {
+			guests = append(guests[:i], guests[i+1:]...)
+			break
+		}
+	}
+	if isFull(meal, guest.EventAddressID, requests, guests) {
+		return errors.NewConflictError("no seats are left at this location", nil)
+	}
+	return nil
+}
+
+// exceedsQuota reports whether a department has used up its guest quota for the month of a meal event
+func (s *guestRequestService) exceedsQuota(ctx context.Context, department string, meal *model.MealEvent) (bool, error) {
+	quota, err := s.guestRepo.FindQuota(ctx, department)
+	if err != nil {
+		// Departments without a quota may host guests freely
+		return false, nil
+	}
+
+	loc := mealLocation(meal, s.timeZone)
+	local := meal.EventDate.In(loc)
+	monthStart := utils.StartOfDay(local.AddDate(0, 0, 1-local.Day()), loc)
+	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	count, err := s.guestRepo.CountApprovedForDepartment(ctx, department, monthStart, monthEnd)
+	if err != nil {
+		return false, err
+	}
+	return count >= int64(quota.MonthlyLimit), nil
+}
+
+// notifyAdmins sends a message to every active admin. Delivery is best effort.
+func (s *guestRequestService) notifyAdmins(ctx context.Context, message string) {
+	admins, err := s.userRepo.FindActive(ctx, map[string]interface{}{"role": model.UserRoleAdmin})
+	if err != nil {
+		return
+	}
+	for _, admin := range admins {
+		_ = s.notifService.CreateAdminNotification(ctx, admin.ID, message, "high")
+	}
+}
+
+// offersMenuSet reports whether a meal event offers a menu set
+func offersMenuSet(meal *model.MealEvent, menuSetID uint) bool {
+	for _, set := range meal.MenuSets {
+		if set.MenuSetID == menuSetID {
+			return true
+		}
+	}
+	return false
+}
+
+// isFull reports whether the active employee requests and guests at an address have taken all its seats
+func isFull(meal *model.MealEvent, addressID uint, requests []model.MealRequest, guests []model.GuestRequest) bool {
+	capacity := 0
+	for _, address := range meal.Addresses {
+		if address.AddressID == addressID {
+			capacity = address.Capacity
+		}
+	}
+	if capacity <= 0 {
+		return false
+	}
+
+	taken := 0
+	for _, request := range requests {
+		if request.EventAddressID == addressID && request.Status.IsActive() {
+			taken++
+		}
+	}
+	for _, guest := range guests {
+		if guest.EventAddressID == addressID && guest.Status.IsActive() {
+			taken++
+		}
+	}
+	return taken >= capacity
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/ical"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// maxImportedClosureDays limits how many closure dates one iCalendar import may create
+const maxImportedClosureDays = 1000
+
+// ClosurePolicy decides what happens to new meal events scheduled on closed dates
+type ClosurePolicy string
+
+const (
+	// ClosurePolicyReject refuses to schedule events on closed dates
+	ClosurePolicyReject ClosurePolicy = "reject"
+	// ClosurePolicyFlag schedules the events but returns a warning
+	ClosurePolicyFlag ClosurePolicy = "flag"
+)
+
+// HolidayImport summarizes an iCalendar import into the closure calendar
+type HolidayImport struct {
+	Created []model.Holiday `json:"created"`
+	Skipped int             `json:"skipped"` // dates that were already closed
+}
+
+// holidayService handles business logic for the holiday and closure calendar
+type holidayService struct {
+	holidayRepo repository.HolidayRepository
+	mealRepo    repository.MealEventRepository
+	mealService MealEventService
+	timeZone    string
+}
+
+// NewHolidayService creates a new instance of HolidayService
+func NewHolidayService(
+	holidayRepo repository.HolidayRepository,
+	mealRepo repository.MealEventRepository,
+	mealService MealEventService,
+	timeZone string,
+) HolidayService {
+	return &holidayService{
+		holidayRepo: holidayRepo,
+		mealRepo:    mealRepo,
+		mealService: mealService,
+		timeZone:    timeZone,
+	}
+}
+
+// CreateHoliday adds a closure to the calendar, for the whole organization or a single address.
+// Meal events already scheduled on the date are returned as AffectedEvents.
+func (s *holidayService) CreateHoliday(ctx context.Context, holiday *model.Holiday, userID uint) error {
+	if holiday == nil {
+		return errors.NewValidationError("holiday cannot be nil", nil)
+	}
+	if holiday.Date.IsZero() {
+		return errors.NewValidationError("holiday date is required", nil)
+	}
+	if strings.TrimSpace(holiday.Name) == "" {
+		return errors.NewValidationError("holiday name is required", nil)
+	}
+	if err := s.validateAddress(ctx, holiday.EventAddressID); err != nil {
+		return err
+	}
+
+	created, err := s.createClosure(ctx, holiday, userID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.NewConflictError("this date is already closed", nil)
+	}
+	return nil
+}
+
+// ImportHolidays adds the dates of every event in an iCalendar file to the closure calendar.
+// Dates that are already closed are skipped, so a file can be imported again after it was updated.
+func (s *holidayService) ImportHolidays(ctx context.Context, r io.Reader, addressID *uint, userID uint) (*HolidayImport, error) {
+	if err := s.validateAddress(ctx, addressID); err != nil {
+		return nil, err
+	}
+
+	events, err := ical.Parse(r)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid iCalendar file", err)
+	}
+
+	var closures []model.Holiday
+	for _, event := range events {
+		if event.Status == ical.StatusCancelled {
+			continue
+		}
+		name := strings.TrimSpace(event.Summary)
+		if name == "" {
+			name = "Closed"
+		}
+		for _, date := range closureDates(event) {
+			closures = append(closures, model.Holiday{
+				Date:           date,
+				Name:           name,
+				EventAddressID: addressID,
+				ImportUID:      event.UID,
+			})
+		}
+	}
+	if len(closures) > maxImportedClosureDays {
+		return nil, errors.NewValidationError(fmt.Sprintf("the file closes more than %d days", maxImportedClosureDays), nil)
+	}
+
+	result := &HolidayImport{Created: []model.Holiday{}}
+	for i := range closures {
+		created, err := s.createClosure(ctx, &closures[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			result.Skipped++
+			continue
+		}
+		result.Created = append(result.Created, closures[i])
+	}
+	return result, nil
+}
+
+// ListHolidays retrieves the closures within a date range
+func (s *holidayService) ListHolidays(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error) {
+	if startDate.After(endDate) {
+		return nil, errors.NewValidationError("start date must be before end date", nil)
+	}
+	return s.holidayRepo.FindByDateRange(ctx, startDate, endDate)
+}
+
+// DeleteHoliday removes a closure from the calendar. Cancelled events stay cancelled.
+func (s *holidayService) DeleteHoliday(ctx context.Context, id uint) error {
+	holiday, err := s.holidayRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("holiday not found", err)
+	}
+	return s.holidayRepo.Delete(ctx, holiday)
+}
+
+// FindAffectedEvents retrieves the meal events still scheduled on a closure. Events that stay open
+// at some of their addresses are flagged with a warning.
+func (s *holidayService) FindAffectedEvents(ctx context.Context, id uint) ([]model.MealEvent, error) {
+	holiday, err := s.holidayRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("holiday not found", err)
+	}
+
+	closed, flagged, err := s.affectedEvents(ctx, holiday)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to find affected meal events", err)
+	}
+	return append(closed, flagged...), nil
+}
+
+// CancelAffectedEvents cancels meal events scheduled on a closure and notifies their requesters.
+// Without meal event IDs every event closed at all of its addresses is cancelled. Events that stay
+// open at some addresses are never cancelled here; an admin edits their addresses instead.
+func (s *holidayService) CancelAffectedEvents(ctx context.Context, id uint, mealEventIDs []uint, reason string, userID uint) ([]model.MealEvent, error) {
+	holiday, err := s.holidayRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("holiday not found", err)
+	}
+	closed, flagged, err := s.affectedEvents(ctx, holiday)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to find affected meal events", err)
+	}
+
+	selected := closed
+	if len(mealEventIDs) > 0 {
+		requested := make(map[uint]bool, len(mealEventIDs))
+		for _, mealID := range mealEventIDs {
+			requested[mealID] = true
+		}
+		selected = make([]model.MealEvent, 0, len(mealEventIDs))
+		for _, meal := range closed {
+			if requested[meal.ID] {
+				selected = append(selected, meal)
+				delete(requested, meal.ID)
+			}
+		}
+		for _, meal := range flagged {
+			if requested[meal.ID] {
+				return nil, errors.NewValidationError(fmt.Sprintf("meal event %d is still open at some of its addresses", meal.ID), nil)
+			}
+		}
+		for mealID := range requested {
+			return nil, errors.NewValidationError(fmt.Sprintf("meal event %d is not affected by this closure", mealID), nil)
+		}
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		reason = "Closed for " + holiday.Name
+	}
+
+	cancelled := make([]model.MealEvent, 0, len(selected))
+	for _, meal := range selected {
+		updated, err := s.mealService.TransitionStatus(ctx, meal.ID, model.MealEventStatusCancelled, reason, userID)
+		if err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, *updated)
+	}
+	return cancelled, nil
+}
+
+// createClosure stores a closure unless the same date is already closed for the same scope,
+// and looks up the meal events it affects
+func (s *holidayService) createClosure(ctx context.Context, holiday *model.Holiday, userID uint) (bool, error) {
+	day := calendarDate(holiday.Date)
+	existing, err := s.holidayRepo.FindByDateRange(ctx, day, day)
+	if err != nil {
+		return false, errors.NewInternalError("failed to check holiday calendar", err)
+	}
+	for _, other := range existing {
+		if sameClosureScope(other.EventAddressID, holiday.EventAddressID) {
+			return false, nil
+		}
+	}
+
+	holiday.ID = 0
+	holiday.Date = day
+	holiday.CreatedBy = userID
+	holiday.UpdatedBy = userID
+
+	if err := s.holidayRepo.Create(ctx, holiday); err != nil {
+		return false, errors.NewInternalError("failed to create holiday", err)
+	}
+
+	closed, flagged, err := s.affectedEvents(ctx, holiday)
+	if err != nil {
+		return false, errors.NewInternalError("failed to find affected meal events", err)
+	}
+	holiday.AffectedEvents = append(closed, flagged...)
+	return true, nil
+}
+
+// validateAddress checks that the address a closure is limited to exists
+func (s *holidayService) validateAddress(ctx context.Context, addressID *uint) error {
+	if addressID == nil {
+		return nil
+	}
+	exists, err := s.holidayRepo.AddressExists(ctx, *addressID)
+	if err != nil {
+		return errors.NewInternalError("failed to check event address", err)
+	}
+	if !exists {
+		return errors.NewValidationError("event address not found", nil).
+			WithFields(errors.FieldError{Field: "event_address_id", Message: "event address does not exist"})
+	}
+	return nil
+}
+
+// affectedEvents finds the meal events that are neither cancelled nor completed and take place on
+// the closure's date at a closed address. Events closed at all of their addresses, by this closure
+// and any other on the date, are returned as closed; events still open at some addresses are
+// returned as flagged, with a warning.
+func (s *holidayService) affectedEvents(ctx context.Context, holiday *model.Holiday) ([]model.MealEvent, []model.MealEvent, error) {
+	// Events are matched on their local date, which may differ from their UTC date by a day
+	meals, err := s.mealRepo.FindByDateRange(ctx, holiday.Date.AddDate(0, 0, -1), holiday.Date.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, nil, err
+	}
+	closures, err := s.holidayRepo.FindByDateRange(ctx, holiday.Date, holiday.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closed := []model.MealEvent{}
+	flagged := []model.MealEvent{}
+	for _, meal := range meals {
+		if meal.Status == model.MealEventStatusCancelled || meal.Status == model.MealEventStatusCompleted {
+			continue
+		}
+		if !calendarDate(meal.EventDate.In(mealLocation(&meal, s.timeZone))).Equal(holiday.Date) {
+			continue
+		}
+		addressIDs := eventAddressIDs(meal.Addresses)
+		if applicable, _ := closuresFor([]model.Holiday{*holiday}, addressIDs); len(applicable) == 0 {
+			continue
+		}
+		if _, allClosed := closuresFor(closures, addressIDs); allClosed {
+			closed = append(closed, meal)
+			continue
+		}
+		meal.Warnings = append(meal.Warnings, "other addresses of the meal event stay open, so it is not cancelled with the closure")
+		flagged = append(flagged, meal)
+	}
+	return closed, flagged, nil
+}
+
+// checkClosures looks up the closures on a meal event's local date and applies the closure policy.
+// Events closed at every address are rejected or flagged; events closed at only some addresses are flagged.
+func checkClosures(ctx context.Context, holidayRepo repository.HolidayRepository, policy ClosurePolicy, meal *model.MealEvent, loc *time.Location) ([]errors.FieldError, []string, error) {
+	if meal.EventDate.IsZero() {
+		return nil, nil, nil
+	}
+
+	day := calendarDate(meal.EventDate.In(loc))
+	closures, err := holidayRepo.FindByDateRange(ctx, day, day)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applicable, closed := closuresFor(closures, eventAddressIDs(meal.Addresses))
+	if len(applicable) == 0 {
+		return nil, nil, nil
+	}
+
+	names := make([]string, 0, len(applicable))
+	for _, closure := range applicable {
+		name := closure.Name
+		if closure.EventAddress != nil {
+			name += " at " + closure.EventAddress.Address
+		}
+		names = append(names, name)
+	}
+	message := fmt.Sprintf("%s is closed: %s", day.Format(dateLayout), strings.Join(names, ", "))
+
+	if closed && policy == ClosurePolicyReject {
+		return []errors.FieldError{{Field: "event_date", Message: message}}, nil, nil
+	}
+	return nil, []string{message}, nil
+}
+
+// closuresFor returns the closures that apply to at least one of the given addresses, and whether
+// they close all of them. Events without addresses are only closed by organization-wide closures.
+func closuresFor(closures []model.Holiday, addressIDs []uint) ([]model.Holiday, bool) {
+	var applicable []model.Holiday
+	orgClosed := false
+	closedAddresses := make(map[uint]bool, len(addressIDs))
+	for _, closure := range closures {
+		if closure.EventAddressID == nil {
+			applicable = append(applicable, closure)
+			orgClosed = true
+			continue
+		}
+		for _, addressID := range addressIDs {
+			if closure.AppliesTo(addressID) {
+				applicable = append(applicable, closure)
+				closedAddresses[addressID] = true
+				break
+			}
+		}
+	}
+
+	allClosed := len(addressIDs) > 0
+	for _, addressID := range addressIDs {
+		allClosed = allClosed && closedAddresses[addressID]
+	}
+	return applicable, orgClosed || allClosed
+}
+
+// closureDates returns the calendar dates an imported event covers.
+// All-day events end on the day before their exclusive end date; timed events cover every day they touch.
+func closureDates(event ical.Event) []time.Time {
+	first := calendarDate(event.Start)
+	last := first
+	if event.AllDay {
+		if end := calendarDate(event.End).AddDate(0, 0, -1); end.After(first) {
+			last = end
+		}
+	} else if event.End.After(event.Start) {
+		last = calendarDate(event.End.Add(-time.Nanosecond))
+	}
+
+	var dates []time.Time
+	for day := first; !day.After(last) && len(dates) <= maxImportedClosureDays; day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day)
+	}
+	return dates
+}
+
+// sameClosureScope reports whether two closures apply to the same address, or both to the whole organization
+func sameClosureScope(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
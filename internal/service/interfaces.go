@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/arafat-hasan/mealsync/internal/model"
@@ -30,8 +31,8 @@ type MealEventService interface {
 	// Methods used by the API handlers
 	GetMealByID(ctx context.Context, id uint, userID uint, isAdmin bool) (*model.MealEvent, error)
 	CreateMeal(ctx context.Context, meal *model.MealEvent, userID uint) error
-	UpdateMeal(ctx context.Context, id uint, meal *model.MealEvent, userID uint) error
-	DeleteMeal(ctx context.Context, id uint, userID uint) error
+	UpdateMeal(ctx context.Context, id uint, version int, meal *model.MealEvent, userID uint) error
+	DeleteMeal(ctx context.Context, id uint, version int, userID uint) error
 
 	// MealEventSet management with label and note
 	AddMenuSetToEvent(ctx context.Context, MealEventSet *model.MealEventSet) error
@@ -56,6 +57,15 @@ type MealEventService interface {
 
 	FindUpcomingAndActive(ctx context.Context) ([]model.MealEvent, error)
 	FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealEvent, error)
+	CloneMeal(ctx context.Context, id uint, dates []time.Time, userID uint) ([]model.MealEvent, error)
+	GetMealsByDateRange(ctx context.Context, startDate, endDate time.Time, mealType model.MealType, isAdmin bool) ([]model.MealEvent, error)
+
+	// Lifecycle operations
+	TransitionStatus(ctx context.Context, id uint, status model.MealEventStatus, reason string, userID uint) (*model.MealEvent, error)
+	PublishScheduled(ctx context.Context, meal *model.MealEvent) error
+	FindTransitions(ctx context.Context, id uint) ([]model.MealEventTransition, error)
+	FindRevisions(ctx context.Context, id uint) ([]model.MealEventRevision, error)
+	CloseDueEvents(ctx context.Context, now time.Time) error
 }
 
 // MenuSetService defines menu set-related business operations
@@ -63,13 +73,13 @@ type MenuSetService interface {
 	GetMenuSets(ctx context.Context) ([]model.MenuSet, error)
 	GetMenuSetByID(ctx context.Context, id uint) (*model.MenuSet, error)
 	CreateMenuSet(ctx context.Context, menuSet *model.MenuSet, userID uint) error
-	UpdateMenuSet(ctx context.Context, id uint, menuSet *model.MenuSet, userID uint) error
-	DeleteMenuSet(ctx context.Context, id uint, userID uint) error
+	UpdateMenuSet(ctx context.Context, id uint, version int, menuSet *model.MenuSet, userID uint) error
+	DeleteMenuSet(ctx context.Context, id uint, version int, userID uint) error
 	GetMenuItems(ctx context.Context) ([]model.MenuItem, error)
 	GetMenuItemByID(ctx context.Context, id uint) (*model.MenuItem, error)
 	CreateMenuItem(ctx context.Context, menuItem *model.MenuItem, userID uint) error
-	UpdateMenuItem(ctx context.Context, id uint, menuItem *model.MenuItem, userID uint) error
-	DeleteMenuItem(ctx context.Context, id uint, userID uint) error
+	UpdateMenuItem(ctx context.Context, id uint, version int, menuItem *model.MenuItem, userID uint) error
+	DeleteMenuItem(ctx context.Context, id uint, version int, userID uint) error
 	AddItemToMenuSet(ctx context.Context, menuSetID uint, menuItemID uint, userID uint) error
 	RemoveItemFromMenuSet(ctx context.Context, menuSetID uint, menuItemID uint, userID uint) error
 	GetMenuSetItems(ctx context.Context, menuSetID uint) ([]model.MenuItem, error)
@@ -77,18 +87,18 @@ type MenuSetService interface {
 
 // MenuItemService defines the interface for menu item operations
 type MenuItemService interface {
-	GetMenuItems(ctx context.Context) ([]model.MenuItem, error)
+	GetMenuItems(ctx context.Context, filter MenuItemFilter) ([]model.MenuItem, error)
 	GetMenuItemByID(ctx context.Context, id uint) (*model.MenuItem, error)
 	CreateMenuItem(ctx context.Context, menuItem *model.MenuItem, userID uint) error
-	UpdateMenuItem(ctx context.Context, id uint, menuItem *model.MenuItem, userID uint) error
-	DeleteMenuItem(ctx context.Context, id uint, userID uint) error
+	UpdateMenuItem(ctx context.Context, id uint, version int, menuItem *model.MenuItem, userID uint) error
+	DeleteMenuItem(ctx context.Context, id uint, version int, userID uint) error
 	GetMenuItemsByCategory(ctx context.Context, category string) ([]model.MenuItem, error)
 	GetMenuItemsByMenuSet(ctx context.Context, menuSetID uint) ([]model.MenuItem, error)
 }
 
 // NotificationService defines notification-related business operations
 type NotificationService interface {
-	GetNotifications(ctx context.Context, userID uint) ([]model.Notification, error)
+	GetNotifications(ctx context.Context, userID uint, filter model.NotificationFilter) (*NotificationPage, error)
 	CreateNotification(ctx context.Context, notification *model.Notification, userID uint) error
 	MarkNotificationAsRead(ctx context.Context, notificationID uint, userID uint) error
 	MarkNotificationAsDelivered(ctx context.Context, notificationID uint, userID uint) error
@@ -100,7 +110,172 @@ type NotificationService interface {
 	CreateMealConfirmationNotification(ctx context.Context, userID uint, mealEventID uint, message string) error
 	CreateMealReminderNotification(ctx context.Context, userID uint, mealEventID uint, message string, deadline time.Time) error
 	CreateMealCancellationNotification(ctx context.Context, userID uint, mealEventID uint, message string) error
+	CreateEventAnnouncementNotification(ctx context.Context, userID uint, mealEventID uint, message string, deadline time.Time) error
+	CreateMealChangeNotification(ctx context.Context, userID uint, mealEventID uint, message string, changes model.FieldChanges) error
 	CreateAdminNotification(ctx context.Context, userID uint, message string, importance string) error
+	MarkAllNotificationsAsRead(ctx context.Context, userID uint) (int64, error)
+	DeleteNotifications(ctx context.Context, notificationIDs []uint, userID uint) (int64, error)
+	ArchiveNotifications(ctx context.Context, notificationIDs []uint, userID uint, archived bool) (int64, error)
+	PurgeReadNotifications(ctx context.Context, now time.Time) error
+}
+
+// DigestService defines notification digest operations
+type DigestService interface {
+	UpdateDigestPreference(ctx context.Context, userID uint, frequency model.DigestFrequency) error
+	BuildDigest(ctx context.Context, userID uint, now time.Time) (*Digest, error)
+	SendDueDigests(ctx context.Context, now time.Time) error
+}
+
+// MealEventSeriesService defines recurring meal event series operations
+type MealEventSeriesService interface {
+	CreateSeries(ctx context.Context, series *model.MealEventSeries, userID uint) error
+	GetSeries(ctx context.Context, id uint) (*model.MealEventSeries, error)
+	ListSeries(ctx context.Context) ([]model.MealEventSeries, error)
+	GetOccurrence(ctx context.Context, seriesID uint, mealEventID uint) (*model.MealEvent, error)
+	EditOccurrence(ctx context.Context, seriesID uint, mealEventID uint, version int, changes *SeriesChanges, userID uint) (*model.MealEvent, error)
+	SplitSeries(ctx context.Context, seriesID uint, mealEventID uint, version int, changes *SeriesChanges, userID uint) (*model.MealEventSeries, error)
+	EndSeries(ctx context.Context, id uint, version int, userID uint) error
+	GenerateEvents(ctx context.Context, now time.Time) error
+}
+
+// MealEventTemplateService defines meal event template operations
+type MealEventTemplateService interface {
+	CreateTemplate(ctx context.Context, template *model.MealEventTemplate, userID uint) error
+	GetTemplate(ctx context.Context, id uint) (*model.MealEventTemplate, error)
+	ListTemplates(ctx context.Context) ([]model.MealEventTemplate, error)
+	UpdateTemplate(ctx context.Context, id uint, version int, template *model.MealEventTemplate, userID uint) error
+	DeleteTemplate(ctx context.Context, id uint, version int) error
+	CreateEventFromTemplate(ctx context.Context, templateID uint, date time.Time, userID uint) (*model.MealEvent, error)
+}
+
+// TimeZoneService defines time zone resolution for users and the organization
+type TimeZoneService interface {
+	OrganizationLocation() *time.Location
+	UserLocation(ctx context.Context, userID uint) (*time.Location, error)
+	GetTimeZoneSettings(ctx context.Context, userID uint) (*TimeZoneSettings, error)
+	UpdateUserTimeZone(ctx context.Context, userID uint, timeZone string) (*TimeZoneSettings, error)
+}
+
+// DietaryService defines dietary profile operations
+type DietaryService interface {
+	GetDietaryProfile(ctx context.Context, userID uint) (*DietaryProfile, error)
+	UpdateDietaryProfile(ctx context.Context, userID uint, profile *DietaryProfile) (*DietaryProfile, error)
+}
+
+// CalendarService defines iCalendar feed and calendar email operations
+type CalendarService interface {
+	ListFeeds(ctx context.Context, userID uint) ([]model.CalendarFeed, error)
+	CreateFeed(ctx context.Context, userID uint, kind model.CalendarFeedKind) (*model.CalendarFeed, error)
+	RevokeFeed(ctx context.Context, id uint, userID uint) error
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+	SendRequestConfirmation(ctx context.Context, meal *model.MealEvent, request *model.MealRequest)
+	SendRequestCancellation(ctx context.Context, meal *model.MealEvent, request *model.MealRequest, reason string)
+	SendRequestWithdrawal(ctx context.Context, requestID uint, from model.RequestStatus, reason string)
+	SendRequestTransfer(ctx context.Context, requestID uint, previous model.User)
+}
+
+// GuestRequestService defines guest meal request operations
+type GuestRequestService interface {
+	GetGuestRequests(ctx context.Context, userID uint) ([]model.GuestRequest, error)
+	GetEventGuests(ctx context.Context, mealEventID uint) ([]model.GuestRequest, error)
+	GetPendingGuestRequests(ctx context.Context) ([]model.GuestRequest, error)
+	GetGuestRequest(ctx context.Context, id uint, userID uint, isAdmin bool) (*model.GuestRequest, error)
+	CreateGuestRequest(ctx context.Context, guest *model.GuestRequest, userID uint, isAdmin bool) error
+	UpdateGuestRequest(ctx context.Context, id uint, version int, guest *model.GuestRequest, userID uint, isAdmin bool) error
+	CancelGuestRequest(ctx context.Context, id uint, version int, userID uint, isAdmin bool) error
+	ReviewGuestRequest(ctx context.Context, id uint, approve bool, note string, adminID uint) (*model.GuestRequest, error)
+	ListGuestQuotas(ctx context.Context) ([]model.DepartmentGuestQuota, error)
+	SetGuestQuota(ctx context.Context, department string, monthlyLimit int, userID uint) (*model.DepartmentGuestQuota, error)
+	DeleteGuestQuota(ctx context.Context, department string) error
+	GetAttendees(ctx context.Context, mealEventID uint) (*EventAttendees, error)
+}
+
+// MealPlanService defines week-ahead meal planning operations
+type MealPlanService interface {
+	GetMealPlan(ctx context.Context, userID uint, startDate, endDate time.Time) (*MealPlan, error)
+	ApplyMealPlan(ctx context.Context, batch *MealPlanBatch, userID uint) (*MealPlanResult, error)
+}
+
+// MealPickupService defines meal pickup check-in and no-show tracking operations
+type MealPickupService interface {
+	IssueToken(ctx context.Context, requestID uint, userID uint) (*PickupToken, error)
+	GetVerificationKey() *PickupVerificationKey
+	ScanPickup(ctx context.Context, scan *PickupScan, staffID uint) (*PickupScanResult, error)
+	GetMealPickups(ctx context.Context, mealEventID uint) (*MealPickupSummary, error)
+	GetNoShowReport(ctx context.Context, startDate, endDate time.Time) (*NoShowReport, error)
+	MarkNoShows(ctx context.Context, now time.Time) error
+}
+
+// MealTransferService defines post-cutoff meal transfer and surplus pool operations
+type MealTransferService interface {
+	OfferMeal(ctx context.Context, requestID uint, userID uint, offer *MealTransferOffer) (*model.MealTransfer, error)
+	GetMyTransfers(ctx context.Context, userID uint) ([]model.MealTransfer, error)
+	GetPool(ctx context.Context) ([]model.MealTransfer, error)
+	GetRequestTransfers(ctx context.Context, requestID uint, userID uint, isAdmin bool) ([]model.MealTransfer, error)
+	AcceptOffer(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error)
+	ClaimMeal(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error)
+	DeclineOffer(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error)
+	WithdrawOffer(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error)
+}
+
+// NoShowPolicyService defines no-show policy, penalty and appeal operations
+type NoShowPolicyService interface {
+	GetPolicies(ctx context.Context) ([]model.NoShowPolicy, error)
+	CreatePolicy(ctx context.Context, policy *model.NoShowPolicy, adminID uint) error
+	UpdatePolicy(ctx context.Context, id uint, policy *model.NoShowPolicy, adminID uint) error
+	EvaluatePolicies(ctx context.Context, now time.Time) error
+	GetNoShowHistory(ctx context.Context, userID uint, actorID uint, isAdmin bool) (*NoShowHistory, error)
+	ExcuseNoShow(ctx context.Context, requestID uint, reason string, adminID uint) (*model.MealRequest, error)
+	LiftPenalty(ctx context.Context, penaltyID uint, reason string, adminID uint) (*model.NoShowPenalty, error)
+}
+
+// TeamService defines line manager operations on their reports' meal requests
+type TeamService interface {
+	GetTeam(ctx context.Context, managerID uint) ([]model.User, error)
+	GetTeamEventStatus(ctx context.Context, managerID uint, mealEventID uint) (*TeamEventStatus, error)
+	PlaceTeamRequest(ctx context.Context, managerID uint, reportID uint, request *model.MealRequest) error
+	WithdrawTeamRequest(ctx context.Context, managerID uint, requestID uint, reason string) (*model.MealRequest, error)
+	GetPendingApprovals(ctx context.Context, managerID uint) ([]model.MealRequest, error)
+	ApproveTeamRequest(ctx context.Context, managerID uint, requestID uint) (*model.MealRequest, error)
+	GetTeamSummary(ctx context.Context, managerID uint, startDate, endDate time.Time) (*TeamSummary, error)
+	SetManager(ctx context.Context, userID uint, managerID *uint, adminID uint) (*model.User, error)
+	ImportReportingLines(ctx context.Context, lines []ReportingLine, adminID uint) (*ReportingLineImport, error)
+}
+
+// StandingOrderService defines standing order operations
+type StandingOrderService interface {
+	ListStandingOrders(ctx context.Context, userID uint) ([]model.StandingOrder, error)
+	GetStandingOrder(ctx context.Context, id uint, userID uint) (*model.StandingOrder, error)
+	CreateStandingOrder(ctx context.Context, order *model.StandingOrder, userID uint) error
+	UpdateStandingOrder(ctx context.Context, id uint, version int, order *model.StandingOrder, userID uint) error
+	DeleteStandingOrder(ctx context.Context, id uint, version int, userID uint) error
+	ListPauses(ctx context.Context, userID uint) ([]model.StandingOrderPause, error)
+	CreatePause(ctx context.Context, pause *model.StandingOrderPause, userID uint) error
+	DeletePause(ctx context.Context, id uint, userID uint) error
+	ApplyStandingOrders(ctx context.Context, mealEventID uint) (int, error)
+}
+
+// MealTypeDefaultService defines per meal type default operations
+type MealTypeDefaultService interface {
+	ListDefaults(ctx context.Context) ([]model.MealTypeDefault, error)
+	GetDefault(ctx context.Context, mealType model.MealType) (*model.MealTypeDefault, error)
+	SetDefault(ctx context.Context, mealType model.MealType, defaults *model.MealTypeDefault, userID uint) error
+	DeleteDefault(ctx context.Context, mealType model.MealType) error
+}
+
+// EstimationService defines meal quantity estimation operations
+type EstimationService interface {
+	GetEstimates(ctx context.Context, startDate, endDate time.Time, groupBy EstimateGrouping) ([]MealEstimate, error)
+}
+
+// HolidayService defines holiday calendar operations
+type HolidayService interface {
+	CreateHoliday(ctx context.Context, holiday *model.Holiday, userID uint) error
+	ImportHolidays(ctx context.Context, r io.Reader, addressID *uint, userID uint) (*HolidayImport, error)
+	ListHolidays(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error)
+	DeleteHoliday(ctx context.Context, id uint) error
+	FindAffectedEvents(ctx context.Context, id uint) ([]model.MealEvent, error)
+	CancelAffectedEvents(ctx context.Context, id uint, mealEventIDs []uint, reason string, userID uint) ([]model.MealEvent, error)
 }
 
 // MealRequestService defines the interface for meal request operations
@@ -108,12 +283,20 @@ type MealRequestService interface {
 	GetMealRequests(ctx context.Context, userID uint, isAdmin bool) ([]model.MealRequest, error)
 	GetMealRequestByID(ctx context.Context, id uint, userID uint, isAdmin bool) (*model.MealRequest, error)
 	CreateMealRequest(ctx context.Context, request *model.MealRequest, userID uint) error
-	UpdateMealRequest(ctx context.Context, id uint, request *model.MealRequest, userID uint, isAdmin bool) error
-	DeleteMealRequest(ctx context.Context, id uint, userID uint, isAdmin bool) error
+	CreateMealRequestFor(ctx context.Context, request *model.MealRequest, userID uint, actorID uint) error
+	UpdateMealRequest(ctx context.Context, id uint, version int, request *model.MealRequest, userID uint, isAdmin bool) error
+	DeleteMealRequest(ctx context.Context, id uint, version int, userID uint, isAdmin bool) error
 	AddRequestItem(ctx context.Context, requestID uint, item *model.MealRequestItem, userID uint, isAdmin bool) error
 	RemoveRequestItem(ctx context.Context, requestID uint, itemID uint, userID uint, isAdmin bool) error
 	GetRequestItems(ctx context.Context, requestID uint, userID uint, isAdmin bool) ([]model.MealRequestItem, error)
-	UpdateRequestStatus(ctx context.Context, requestID uint, status model.RequestStatus, userID uint) error
+	UpdateRequestStatus(ctx context.Context, requestID uint, version int, status model.RequestStatus, reason string, userID uint, isAdmin bool) (*model.MealRequest, error)
+	GetRequestHistory(ctx context.Context, requestID uint, userID uint, isAdmin bool) ([]model.MealRequestTransition, error)
+	AssignMealRequests(ctx context.Context, assignment *BulkRequestAssignment, adminID uint) (*BulkRequestResult, error)
+	CancelMealRequestsFor(ctx context.Context, cancellation *BulkRequestCancellation, adminID uint) (*BulkRequestResult, error)
+	CreateLateRequest(ctx context.Context, request *model.MealRequest, userID uint) error
+	GetLateRequests(ctx context.Context, mealEventID uint) (*LateRequestOverview, error)
+	SetSurplus(ctx context.Context, mealEventID uint, surplus []MenuSetSurplus, adminID uint) (*LateRequestOverview, error)
+	ReviewLateRequest(ctx context.Context, requestID uint, approve bool, note string, adminID uint) (*model.MealRequest, error)
 }
 
 // EventAddressService defines event address-related business operations
@@ -134,8 +317,8 @@ type MenuItemCommentService interface {
 	GetComments(ctx context.Context, mealEventID uint) ([]model.MenuItemComment, error)
 	GetCommentByID(ctx context.Context, id uint) (*model.MenuItemComment, error)
 	CreateComment(ctx context.Context, comment *model.MenuItemComment, userID uint) error
-	UpdateComment(ctx context.Context, id uint, comment *model.MenuItemComment, userID uint) error
-	DeleteComment(ctx context.Context, id uint, userID uint) error
+	UpdateComment(ctx context.Context, id uint, version int, comment *model.MenuItemComment, userID uint) error
+	DeleteComment(ctx context.Context, id uint, version int, userID uint) error
 	GetUserComments(ctx context.Context, userID uint) ([]model.MenuItemComment, error)
 	GetMenuItemComments(ctx context.Context, menuItemID uint) ([]model.MenuItemComment, error)
 	GetReplies(ctx context.Context, commentID uint) ([]model.MenuItemComment, error) // Added for comment replies
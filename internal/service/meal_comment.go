@@ -73,8 +73,8 @@ func (s *menuItemCommentService) CreateComment(ctx context.Context, comment *mod
 	return s.commentRepo.Create(ctx, comment)
 }
 
-// UpdateComment updates an existing comment
-func (s *menuItemCommentService) UpdateComment(ctx context.Context, id uint, comment *model.MenuItemComment, userID uint) error {
+// UpdateComment updates an existing comment if it is still at the version the client read
+func (s *menuItemCommentService) UpdateComment(ctx context.Context, id uint, version int, comment *model.MenuItemComment, userID uint) error {
 	if comment == nil {
 		return errors.NewValidationError("comment cannot be nil", nil)
 	}
@@ -89,16 +89,25 @@ func (s *menuItemCommentService) UpdateComment(ctx context.Context, id uint, com
 		return errors.NewForbiddenError("unauthorized to update this comment", nil)
 	}
 
+	if err := checkVersion("comment", existingComment.Version, version); err != nil {
+		return err
+	}
+
 	// Update fields
 	existingComment.Comment = comment.Comment
 	existingComment.Rating = comment.Rating
 	existingComment.UpdatedBy = userID
+	existingComment.Version = version + 1
 
-	return s.commentRepo.Update(ctx, existingComment)
+	if err := s.commentRepo.UpdateVersion(ctx, existingComment, version); err != nil {
+		return versionError("comment", "update", err)
+	}
+	*comment = *existingComment
+	return nil
 }
 
-// DeleteComment soft deletes a comment
-func (s *menuItemCommentService) DeleteComment(ctx context.Context, id uint, userID uint) error {
+// DeleteComment soft deletes a comment if it is still at the version the client read
+func (s *menuItemCommentService) DeleteComment(ctx context.Context, id uint, version int, userID uint) error {
 	comment, err := s.commentRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
@@ -109,8 +118,14 @@ func (s *menuItemCommentService) DeleteComment(ctx context.Context, id uint, use
 		return errors.NewForbiddenError("unauthorized to delete this comment", nil)
 	}
 
-	comment.UpdatedBy = userID
-	return s.commentRepo.Delete(ctx, comment)
+	if err := checkVersion("comment", comment.Version, version); err != nil {
+		return err
+	}
+
+	if err := s.commentRepo.DeleteVersion(ctx, comment, version); err != nil {
+		return versionError("comment", "delete", err)
+	}
+	return nil
 }
 
 // GetUserComments retrieves all comments by a user
@@ -2,21 +2,33 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/arafat-hasan/mealsync/internal/errors"
 	"github.com/arafat-hasan/mealsync/internal/model"
 	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
 )
 
 // mealEventService handles business logic for meal event operations
 type mealEventService struct {
-	mealRepo     repository.MealEventRepository
-	userRepo     repository.UserRepository
-	menuRepo     repository.MenuSetRepository
-	addressRepo  repository.EventAddressRepository
-	requestRepo  repository.MealRequestRepository
-	commentRepo  repository.MenuItemCommentRepository
-	notifService NotificationService
+	mealRepo      repository.MealEventRepository
+	userRepo      repository.UserRepository
+	menuRepo      repository.MenuSetRepository
+	addressRepo   repository.EventAddressRepository
+	requestRepo   repository.MealRequestRepository
+	guestRepo     repository.GuestRequestRepository
+	commentRepo   repository.MenuItemCommentRepository
+	defaultRepo   repository.MealTypeDefaultRepository
+	holidayRepo   repository.HolidayRepository
+	notifService  NotificationService
+	calendar      CalendarService
+	standing      StandingOrderService
+	closurePolicy ClosurePolicy
+	timeZone      string
 }
 
 // NewMealEventService creates a new instance of MealEventService
@@ -26,17 +38,31 @@ func NewMealEventService(
 	menuRepo repository.MenuSetRepository,
 	addressRepo repository.EventAddressRepository,
 	requestRepo repository.MealRequestRepository,
+	guestRepo repository.GuestRequestRepository,
 	commentRepo repository.MenuItemCommentRepository,
+	defaultRepo repository.MealTypeDefaultRepository,
+	holidayRepo repository.HolidayRepository,
 	notifService NotificationService,
+	calendar CalendarService,
+	standing StandingOrderService,
+	closurePolicy ClosurePolicy,
+	timeZone string,
 ) MealEventService {
 	return &mealEventService{
-		mealRepo:     mealRepo,
-		userRepo:     userRepo,
-		menuRepo:     menuRepo,
-		addressRepo:  addressRepo,
-		requestRepo:  requestRepo,
-		commentRepo:  commentRepo,
-		notifService: notifService,
+		mealRepo:      mealRepo,
+		userRepo:      userRepo,
+		menuRepo:      menuRepo,
+		addressRepo:   addressRepo,
+		requestRepo:   requestRepo,
+		guestRepo:     guestRepo,
+		commentRepo:   commentRepo,
+		defaultRepo:   defaultRepo,
+		holidayRepo:   holidayRepo,
+		notifService:  notifService,
+		calendar:      calendar,
+		standing:      standing,
+		closurePolicy: closurePolicy,
+		timeZone:      timeZone,
 	}
 }
 
@@ -193,7 +219,12 @@ func (s *mealEventService) FindUpcomingAndActive(ctx context.Context) ([]model.M
 func (s *mealEventService) GetMealByID(ctx context.Context, id uint, userID uint, isAdmin bool) (*model.MealEvent, error) {
 	meal, err := s.FindByID(ctx, id)
 	if err != nil {
-		return nil, repository.ErrNotFound
+		return nil, errors.NewNotFoundError("meal event not found", nil)
+	}
+
+	// Drafts are still being set up and are only visible to admins
+	if !isAdmin && meal.Status == model.MealEventStatusDraft {
+		return nil, errors.NewNotFoundError("meal event not found", nil)
 	}
 
 	return meal, nil
@@ -201,18 +232,63 @@ func (s *mealEventService) GetMealByID(ctx context.Context, id uint, userID uint
 
 // CreateMeal creates a new meal event with the creator's user ID
 func (s *mealEventService) CreateMeal(ctx context.Context, meal *model.MealEvent, userID uint) error {
+	if meal.MealType == "" {
+		meal.MealType = model.MealTypeLunch
+	}
+	if meal.MealType.IsValid() {
+		if defaults, err := s.defaultRepo.FindByMealType(ctx, meal.MealType); err == nil {
+			if err := applyMealTypeDefaults(meal, defaults, mealLocation(meal, s.timeZone), userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if fields := validateMealEvent(meal, time.Now(), true); len(fields) > 0 {
+		return errors.NewValidationError("invalid meal event", nil).WithFields(fields...)
+	}
+
+	closureFields, closureWarnings, err := checkClosures(ctx, s.holidayRepo, s.closurePolicy, meal, mealLocation(meal, s.timeZone))
+	if err != nil {
+		return errors.NewInternalError("failed to check the closure calendar", err)
+	}
+	if len(closureFields) > 0 {
+		return errors.NewValidationError("meal event falls on a closed date", nil).WithFields(closureFields...)
+	}
+
+	// New events stay hidden from employees until they are published
+	meal.Status = model.MealEventStatusDraft
+	meal.ConfirmedAt = nil
 	meal.CreatedBy = userID
 	meal.UpdatedBy = userID
-	return s.Create(ctx, meal)
+
+	warnings, err := s.overlapWarnings(ctx, meal, eventAddressIDs(meal.Addresses))
+	if err != nil {
+		return errors.NewInternalError("failed to check for overlapping meal events", err)
+	}
+
+	if err := s.Create(ctx, meal); err != nil {
+		return errors.NewInternalError("failed to create meal event", err)
+	}
+	meal.Warnings = append(warnings, closureWarnings...)
+	return nil
 }
 
-// UpdateMeal updates a meal event with permission checking
-func (s *mealEventService) UpdateMeal(ctx context.Context, id uint, meal *model.MealEvent, userID uint) error {
+// UpdateMeal updates a meal event and replaces its menu sets and addresses unless they are omitted.
+// The event must still be at the version the client read. Every change is recorded as a revision,
+// and requesters are told about material changes.
+func (s *mealEventService) UpdateMeal(ctx context.Context, id uint, version int, meal *model.MealEvent, userID uint) error {
 	existingMeal, err := s.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+	if err := checkVersion("meal event", existingMeal.Version, version); err != nil {
 		return err
 	}
 
+	if existingMeal.Status == model.MealEventStatusCancelled || existingMeal.Status == model.MealEventStatusCompleted {
+		return errors.NewValidationError("cannot edit a "+string(existingMeal.Status)+" meal event", nil)
+	}
+
 	meal.ID = id
 	meal.UpdatedBy = userID
 
@@ -220,24 +296,593 @@ func (s *mealEventService) UpdateMeal(ctx context.Context, id uint, meal *model.
 	meal.CreatedBy = existingMeal.CreatedBy
 	meal.CreatedAt = existingMeal.CreatedAt
 
-	return s.Update(ctx, meal)
+	if meal.MealType == "" {
+		meal.MealType = existingMeal.MealType
+	}
+
+	// Calendar clients only apply updates with a higher sequence
+	meal.Sequence = existingMeal.Sequence + 1
+
+	// Status only changes through TransitionStatus
+	meal.Status = existingMeal.Status
+	meal.ConfirmedAt = existingMeal.ConfirmedAt
+
+	// Editing a generated occurrence directly only affects that occurrence
+	meal.SeriesID = existingMeal.SeriesID
+	meal.SeriesOccurrence = existingMeal.SeriesOccurrence
+	meal.IsException = existingMeal.IsException || existingMeal.SeriesID != nil
+
+	// Only a new date has to fit the scheduling window; other edits stay possible after the cutoff
+	checkWindow := !meal.EventDate.Equal(existingMeal.EventDate)
+	if fields := validateMealEvent(meal, time.Now(), checkWindow); len(fields) > 0 {
+		return errors.NewValidationError("invalid meal event", nil).WithFields(fields...)
+	}
+
+	if err := s.resolveAssociations(ctx, existingMeal, meal, userID); err != nil {
+		return err
+	}
+
+	// Events that were already on a date before it closed are only flagged; the closure offers to cancel them
+	policy := s.closurePolicy
+	if !checkWindow {
+		policy = ClosurePolicyFlag
+	}
+	closureFields, closureWarnings, err := checkClosures(ctx, s.holidayRepo, policy, meal, mealLocation(existingMeal, s.timeZone))
+	if err != nil {
+		return errors.NewInternalError("failed to check the closure calendar", err)
+	}
+	if len(closureFields) > 0 {
+		return errors.NewValidationError("meal event falls on a closed date", nil).WithFields(closureFields...)
+	}
+
+	warnings, err := s.overlapWarnings(ctx, meal, eventAddressIDs(meal.Addresses))
+	if err != nil {
+		return errors.NewInternalError("failed to check for overlapping meal events", err)
+	}
+
+	var revision *model.MealEventRevision
+	changes := diffMealEvent(existingMeal, meal, mealLocation(meal, s.timeZone))
+	if len(changes) > 0 {
+		revision = &model.MealEventRevision{
+			MealEventID: id,
+			Sequence:    meal.Sequence,
+			Changes:     changes,
+			Material:    hasMaterialChanges(changes),
+			ActorID:     &userID,
+		}
+	}
+
+	if err := s.mealRepo.UpdateWithRevision(ctx, meal, version, revision); err != nil {
+		return versionError("meal event", "update", err)
+	}
+	meal.Warnings = append(warnings, closureWarnings...)
+
+	if revision != nil && revision.Material && len(existingMeal.MealRequests) > 0 {
+		if err := s.notifyRequesters(ctx, meal, changes); err != nil {
+			return errors.NewInternalError("failed to notify requesters", err)
+		}
+	}
+	return nil
 }
 
-// DeleteMeal deletes a meal event with permission checking
-func (s *mealEventService) DeleteMeal(ctx context.Context, id uint, userID uint) error {
+// DeleteMeal deletes a meal event if it is still at the version the client read
+func (s *mealEventService) DeleteMeal(ctx context.Context, id uint, version int, userID uint) error {
 	meal, err := s.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+	if err := checkVersion("meal event", meal.Version, version); err != nil {
 		return err
 	}
 
 	// Update the updatedBy field to track who triggered the deletion
 	meal.UpdatedBy = userID
 
-	// Use the Delete method which properly handles soft deletion
-	return s.Delete(ctx, meal)
+	if err := s.mealRepo.DeleteVersion(ctx, meal, version); err != nil {
+		return versionError("meal event", "delete", err)
+	}
+	return nil
 }
 
 // FindByDateRange finds meal events within a date range
 func (s *mealEventService) FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.MealEvent, error) {
 	return s.mealRepo.FindByDateRange(ctx, startDate, endDate)
 }
+
+// GetMealsByDateRange finds the meal events within a date range that the user may see.
+// An empty meal type matches every type.
+func (s *mealEventService) GetMealsByDateRange(ctx context.Context, startDate, endDate time.Time, mealType model.MealType, isAdmin bool) ([]model.MealEvent, error) {
+	meals, err := s.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]model.MealEvent, 0, len(meals))
+	for _, meal := range meals {
+		if mealType != "" && meal.MealType != mealType {
+			continue
+		}
+		// Drafts are only listed for admins
+		if !isAdmin && meal.Status == model.MealEventStatusDraft {
+			continue
+		}
+		visible = append(visible, meal)
+	}
+	return visible, nil
+}
+
+// TransitionStatus moves a meal event to a new lifecycle status, records who did it and why,
+// and notifies the affected employees
+func (s *mealEventService) TransitionStatus(ctx context.Context, id uint, status model.MealEventStatus, reason string, userID uint) (*model.MealEvent, error) {
+	if !status.IsValid() {
+		return nil, errors.NewValidationError("invalid meal event status", nil)
+	}
+
+	meal, err := s.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	if err := s.transition(ctx, meal, status, reason, &userID); err != nil {
+		return nil, err
+	}
+	return meal, nil
+}
+
+// FindTransitions retrieves the status history of a meal event
+func (s *mealEventService) FindTransitions(ctx context.Context, id uint) ([]model.MealEventTransition, error) {
+	if _, err := s.mealRepo.FindByID(ctx, id); err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	return s.mealRepo.FindTransitions(ctx, id)
+}
+
+// CloseDueEvents closes every published meal event whose cutoff has passed
+func (s *mealEventService) CloseDueEvents(ctx context.Context, now time.Time) error {
+	meals, err := s.mealRepo.FindDueForClosing(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range meals {
+		err := s.transition(ctx, &meals[i], model.MealEventStatusClosed, "cutoff time reached", nil)
+		if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishScheduled publishes a draft created by the scheduler, such as an occurrence of a series.
+// The transition is recorded with the system as actor and has the usual side effects.
+func (s *mealEventService) PublishScheduled(ctx context.Context, meal *model.MealEvent) error {
+	return s.transition(ctx, meal, model.MealEventStatusPublished, "generated by schedule", nil)
+}
+
+// transition validates and stores a status change, then applies its side effects.
+// A nil actor means the change was made by the system. The status change is committed before the
+// side effects run, so they are applied best-effort: a failure is logged and does not stop the others.
+func (s *mealEventService) transition(ctx context.Context, meal *model.MealEvent, to model.MealEventStatus, reason string, actorID *uint) error {
+	from := meal.Status
+	if !from.CanTransitionTo(to) {
+		return errors.NewValidationError(fmt.Sprintf("cannot change meal event status from %s to %s", from, to), nil)
+	}
+
+	if to == model.MealEventStatusPublished {
+		if fields := validatePublishable(meal, time.Now()); len(fields) > 0 {
+			return errors.NewValidationError("meal event is not ready to be published", nil).WithFields(fields...)
+		}
+	}
+
+	meal.Status = to
+	meal.Sequence++
+	switch to {
+	case model.MealEventStatusConfirmed:
+		now := time.Now()
+		meal.ConfirmedAt = &now
+	case model.MealEventStatusCancelled:
+		meal.IsActive = false
+	}
+	if actorID != nil {
+		meal.UpdatedBy = *actorID
+	}
+
+	transition := &model.MealEventTransition{
+		MealEventID: meal.ID,
+		FromStatus:  from,
+		ToStatus:    to,
+		Reason:      reason,
+		ActorID:     actorID,
+	}
+	if err := s.mealRepo.UpdateStatus(ctx, meal, from, transition); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return errors.NewConflictError("meal event status was changed by someone else", err)
+		}
+		return errors.NewInternalError("failed to update meal event status", err)
+	}
+
+	switch to {
+	case model.MealEventStatusPublished:
+		if _, err := s.standing.ApplyStandingOrders(ctx, meal.ID); err != nil {
+			log.Printf("meal event %d: failed to apply standing orders: %v", meal.ID, err)
+		}
+		if err := s.announceMeal(ctx, meal); err != nil {
+			log.Printf("meal event %d: failed to announce: %v", meal.ID, err)
+		}
+	case model.MealEventStatusConfirmed:
+		if err := s.confirmMealRequests(ctx, meal, actorID); err != nil {
+			log.Printf("meal event %d: failed to confirm meal requests: %v", meal.ID, err)
+		}
+	case model.MealEventStatusCompleted:
+		if err := s.completeMealRequests(ctx, meal, actorID); err != nil {
+			log.Printf("meal event %d: failed to complete meal requests: %v", meal.ID, err)
+		}
+	case model.MealEventStatusCancelled:
+		if err := s.cancelMealRequests(ctx, meal, reason, actorID); err != nil {
+			log.Printf("meal event %d: failed to cancel meal requests: %v", meal.ID, err)
+		}
+	}
+	return nil
+}
+
+// announceMeal tells every active employee that a meal event is open for requests
+func (s *mealEventService) announceMeal(ctx context.Context, meal *model.MealEvent) error {
+	users, err := s.userRepo.FindActive(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	loc := mealLocation(meal, s.timeZone)
+	message := fmt.Sprintf("%s on %s is open for requests until %s.",
+		meal.Name, formatMealTime(meal.EventDate, loc), formatMealTime(meal.CutoffTime, loc))
+	for _, user := range users {
+		if !user.NotificationEnabled {
+			continue
+		}
+		if err := s.notifService.CreateEventAnnouncementNotification(ctx, user.ID, meal.ID, message, meal.CutoffTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmMealRequests approves the pending requests of a confirmed meal event, tells every requester
+// that their meal is confirmed and emails them a calendar invitation. Requests still waiting for a
+// line manager's approval are rejected; late requests stay pending for the kitchen.
+func (s *mealEventService) confirmMealRequests(ctx context.Context, meal *model.MealEvent, actorID *uint) error {
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return err
+	}
+
+	when := formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone))
+	message := fmt.Sprintf("Your meal request for %s on %s is confirmed.", meal.Name, when)
+	for i := range requests {
+		switch {
+		case requests[i].Status == model.RequestStatusPending && requests[i].AwaitingApproval:
+			const reason = "not approved by the line manager before the meal was confirmed"
+			err := transitionRequest(ctx, s.requestRepo, &requests[i], model.RequestStatusRejected, reason, actorID)
+			if errors.Is(err, repository.ErrStatusChanged) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			rejection := fmt.Sprintf("Your meal request for %s on %s was %s.", meal.Name, when, reason)
+			if err := s.notifService.CreateMealCancellationNotification(ctx, requests[i].UserID, meal.ID, rejection); err != nil {
+				return err
+			}
+			continue
+		case isUndecidedLate(&requests[i]):
+			// The kitchen decides on late requests once it knows the surplus
+			continue
+		case requests[i].Status == model.RequestStatusPending:
+			err := transitionRequest(ctx, s.requestRepo, &requests[i], model.RequestStatusApproved, "meal event confirmed", actorID)
+			if errors.Is(err, repository.ErrStatusChanged) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		case requests[i].Status == model.RequestStatusApproved:
+		default:
+			continue
+		}
+
+		if err := s.notifService.CreateMealConfirmationNotification(ctx, requests[i].UserID, meal.ID, message); err != nil {
+			return err
+		}
+		s.calendar.SendRequestConfirmation(ctx, meal, &requests[i])
+	}
+	return nil
+}
+
+// completeMealRequests completes the approved requests of a meal event that has been served.
+// When meals were checked in at a kiosk, the approved requests left were not picked up. Late requests
+// the kitchen never decided on are rejected.
+func (s *mealEventService) completeMealRequests(ctx context.Context, meal *model.MealEvent, actorID *uint) error {
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return err
+	}
+	checkedIn, err := s.requestRepo.HasPickups(ctx, meal.ID)
+	if err != nil {
+		return err
+	}
+	to, reason := model.RequestStatusCompleted, "meal event completed"
+	if checkedIn {
+		to, reason = model.RequestStatusNoShow, "meal was not picked up"
+	}
+
+	for i := range requests {
+		if isUndecidedLate(&requests[i]) {
+			err := transitionRequest(ctx, s.requestRepo, &requests[i], model.RequestStatusRejected, "late request not approved before the meal event ended", actorID)
+			if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+				return err
+			}
+			continue
+		}
+		if requests[i].Status != model.RequestStatusApproved {
+			continue
+		}
+		err := transitionRequest(ctx, s.requestRepo, &requests[i], to, reason, actorID)
+		if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelMealRequests cancels every open request for a cancelled meal event and tells the requesters why
+func (s *mealEventService) cancelMealRequests(ctx context.Context, meal *model.MealEvent, reason string, actorID *uint) error {
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s on %s has been cancelled.", meal.Name, formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone)))
+	if reason != "" {
+		message += " Reason: " + reason
+	}
+
+	for i := range requests {
+		if !requests[i].Status.CanTransitionTo(model.RequestStatusCancelled) {
+			continue
+		}
+		err := transitionRequest(ctx, s.requestRepo, &requests[i], model.RequestStatusCancelled, reason, actorID)
+		if errors.Is(err, repository.ErrStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.notifService.CreateMealCancellationNotification(ctx, requests[i].UserID, meal.ID, message); err != nil {
+			return err
+		}
+		s.calendar.SendRequestCancellation(ctx, meal, &requests[i], reason)
+	}
+	return s.cancelGuestRequests(ctx, meal, message, actorID)
+}
+
+// cancelGuestRequests cancels the guests of a cancelled meal event and tells their hosts
+func (s *mealEventService) cancelGuestRequests(ctx context.Context, meal *model.MealEvent, message string, actorID *uint) error {
+	guests, err := s.guestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range guests {
+		if !guests[i].Status.IsActive() {
+			continue
+		}
+		from := guests[i].Status
+		guests[i].Status = model.GuestRequestStatusCancelled
+		if actorID != nil {
+			guests[i].UpdatedBy = *actorID
+		}
+		err := s.guestRepo.UpdateStatus(ctx, &guests[i], from)
+		if errors.Is(err, repository.ErrStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		guestMessage := fmt.Sprintf("%s (your guest %s)", message, guests[i].GuestName)
+		if err := s.notifService.CreateMealCancellationNotification(ctx, guests[i].HostUserID, meal.ID, guestMessage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CloneMeal copies a meal event with its menu sets and addresses to each of the given dates.
+// The event keeps its time of day and the cutoff stays the same distance before the start.
+func (s *mealEventService) CloneMeal(ctx context.Context, id uint, dates []time.Time, userID uint) ([]model.MealEvent, error) {
+	if len(dates) == 0 {
+		return nil, errors.NewValidationError("at least one target date is required", nil)
+	}
+
+	source, err := s.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	offset := source.EventDate.Sub(source.CutoffTime)
+	loc := mealLocation(source, s.timeZone)
+	start := source.EventDate.In(loc)
+	now := time.Now()
+	seen := make(map[string]bool, len(dates))
+
+	clones := make([]model.MealEvent, 0, len(dates))
+	for _, date := range dates {
+		key := date.Format(dateLayout)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		// Keep the wall clock time at the event's location, even across daylight saving changes
+		eventDate := utils.AtClock(date, start.Hour(), start.Minute(), loc)
+		clone := model.MealEvent{
+			Status:        model.MealEventStatusDraft,
+			Name:          source.Name,
+			MealType:      source.MealType,
+			Description:   source.Description,
+			EventDate:     eventDate,
+			EventDuration: source.EventDuration,
+			CutoffTime:    eventDate.Add(-offset),
+			IsActive:      true,
+			CreatedBy:     userID,
+			UpdatedBy:     userID,
+			// Surplus is counted per day, so only the late request rules are copied
+			AllowLateRequests: source.AllowLateRequests,
+			LateRequestCap:    source.LateRequestCap,
+		}
+		if err := validateSchedulingWindow(clone.EventDate, clone.CutoffTime, now); err != nil {
+			return nil, err
+		}
+
+		for _, set := range source.MenuSets {
+			clone.MenuSets = append(clone.MenuSets, model.MealEventSet{
+				MenuSetID: set.MenuSetID,
+				Label:     set.Label,
+				Note:      set.Note,
+				CreatedBy: userID,
+				UpdatedBy: userID,
+			})
+		}
+		for _, address := range source.Addresses {
+			clone.Addresses = append(clone.Addresses, model.MealEventAddress{
+				AddressID: address.AddressID,
+				Capacity:  address.Capacity,
+				CreatedBy: userID,
+				UpdatedBy: userID,
+			})
+		}
+
+		closureFields, closureWarnings, err := checkClosures(ctx, s.holidayRepo, s.closurePolicy, &clone, loc)
+		if err != nil {
+			return nil, errors.NewInternalError("failed to check the closure calendar", err)
+		}
+		if len(closureFields) > 0 {
+			return nil, errors.NewValidationError(closureFields[0].Message, nil).WithFields(closureFields...)
+		}
+		clone.Warnings = closureWarnings
+
+		clones = append(clones, clone)
+	}
+
+	// Every date is validated before anything is created, and the copies are created in one
+	// transaction, so neither a bad date nor a failed insert leaves partial copies
+	err = s.mealRepo.Transaction(ctx, func(mealRepo repository.MealEventRepository) error {
+		for i := range clones {
+			if err := mealRepo.Create(ctx, &clones[i]); err != nil {
+				return errors.NewInternalError("failed to create meal event", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clones, nil
+}
+
+// validateSchedulingWindow checks that a new event can still be requested and is within the scheduling horizon
+func validateSchedulingWindow(eventDate, cutoffTime, now time.Time) error {
+	if !cutoffTime.After(now) {
+		return errors.NewValidationError(fmt.Sprintf("the cutoff for %s has already passed", eventDate.Format(dateLayout)), nil)
+	}
+	if eventDate.After(now.AddDate(0, 0, schedulingHorizonDays)) {
+		return errors.NewValidationError(fmt.Sprintf("%s is more than %d days ahead", eventDate.Format(dateLayout), schedulingHorizonDays), nil)
+	}
+	return nil
+}
+
+// overlapWarnings describes other meal events of the same meal type held at one of the addresses while this one runs
+func (s *mealEventService) overlapWarnings(ctx context.Context, meal *model.MealEvent, addressIDs []uint) ([]string, error) {
+	if len(addressIDs) == 0 || meal.EventDuration <= 0 {
+		return nil, nil
+	}
+
+	end := meal.EventDate.Add(time.Duration(meal.EventDuration) * time.Minute)
+	overlapping, err := s.mealRepo.FindOverlapping(ctx, meal.MealType, addressIDs, meal.EventDate, end, meal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := make([]string, 0, len(overlapping))
+	for _, other := range overlapping {
+		warnings = append(warnings, fmt.Sprintf("overlaps with %s %s (#%d) on %s at the same address",
+			other.MealType, other.Name, other.ID, formatMealTime(other.EventDate, mealLocation(meal, s.timeZone))))
+	}
+	return warnings, nil
+}
+
+// eventAddressIDs returns the address IDs attached to a meal event
+func eventAddressIDs(addresses []model.MealEventAddress) []uint {
+	ids := make([]uint, 0, len(addresses))
+	for _, address := range addresses {
+		ids = append(ids, address.AddressID)
+	}
+	return ids
+}
+
+// validateMealEvent checks the rules every saved meal event must follow.
+// The scheduling window is only enforced when checkWindow is set.
+func validateMealEvent(meal *model.MealEvent, now time.Time, checkWindow bool) []errors.FieldError {
+	var fields []errors.FieldError
+	if strings.TrimSpace(meal.Name) == "" {
+		fields = append(fields, errors.FieldError{Field: "name", Message: "name is required"})
+	}
+	if !meal.MealType.IsValid() {
+		fields = append(fields, errors.FieldError{Field: "meal_type", Message: "meal type must be breakfast, lunch or snacks"})
+	}
+	if meal.EventDuration <= 0 {
+		fields = append(fields, errors.FieldError{Field: "event_duration", Message: "event duration must be a positive number of minutes"})
+	}
+
+	if meal.EventDate.IsZero() {
+		fields = append(fields, errors.FieldError{Field: "event_date", Message: "event date is required"})
+	} else if checkWindow {
+		if !meal.EventDate.After(now) {
+			fields = append(fields, errors.FieldError{Field: "event_date", Message: "event date must be in the future"})
+		} else if meal.EventDate.After(now.AddDate(0, 0, schedulingHorizonDays)) {
+			fields = append(fields, errors.FieldError{Field: "event_date", Message: fmt.Sprintf("event date must be at most %d days ahead", schedulingHorizonDays)})
+		}
+	}
+
+	if meal.CutoffTime.IsZero() {
+		fields = append(fields, errors.FieldError{Field: "cutoff_time", Message: "cutoff time is required"})
+	} else if !meal.EventDate.IsZero() && !meal.CutoffTime.Before(meal.EventDate) {
+		fields = append(fields, errors.FieldError{Field: "cutoff_time", Message: "cutoff time must be before the event date"})
+	}
+
+	if meal.LateRequestCap < 0 {
+		fields = append(fields, errors.FieldError{Field: "late_request_cap", Message: "late request cap must not be negative"})
+	}
+	for _, set := range meal.MenuSets {
+		if set.Surplus < 0 {
+			fields = append(fields, errors.FieldError{Field: "menu_sets", Message: "surplus must not be negative"})
+			break
+		}
+	}
+
+	return fields
+}
+
+// validatePublishable checks that a meal event is complete enough for employees to request it
+func validatePublishable(meal *model.MealEvent, now time.Time) []errors.FieldError {
+	fields := validateMealEvent(meal, now, false)
+	if !meal.CutoffTime.IsZero() && !meal.CutoffTime.After(now) {
+		fields = append(fields, errors.FieldError{Field: "cutoff_time", Message: "cutoff time has already passed"})
+	}
+	if len(meal.MenuSets) == 0 {
+		fields = append(fields, errors.FieldError{Field: "menu_sets", Message: "at least one menu set is required"})
+	}
+	if len(meal.Addresses) == 0 {
+		fields = append(fields, errors.FieldError{Field: "addresses", Message: "at least one address is required"})
+	}
+	return fields
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+)
+
+// fieldLabels names the fields of a meal event in change notifications, in the order they are listed
+var fieldLabels = []struct {
+	field string
+	label string
+}{
+	{"name", "Name"},
+	{"description", "Description"},
+	{"meal_type", "Meal type"},
+	{"event_date", "Time"},
+	{"event_duration", "Duration"},
+	{"cutoff_time", "Request cutoff"},
+	{"is_active", "Active"},
+	{"menu_sets", "Menu sets"},
+	{"addresses", "Locations"},
+	{"allow_late_requests", "Late requests"},
+	{"late_request_cap", "Late request cap"},
+}
+
+// materialFields are the fields whose changes affect employees who already requested the meal
+var materialFields = map[string]bool{
+	"event_date":  true,
+	"cutoff_time": true,
+	"menu_sets":   true,
+	"addresses":   true,
+}
+
+// FindRevisions retrieves the edit history of a meal event
+func (s *mealEventService) FindRevisions(ctx context.Context, id uint) ([]model.MealEventRevision, error) {
+	if _, err := s.mealRepo.FindByID(ctx, id); err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	return s.mealRepo.FindRevisions(ctx, id)
+}
+
+// resolveAssociations completes the menu sets and addresses of an edited meal event.
+// Nil lists keep the current ones; new menu sets and addresses must exist.
+func (s *mealEventService) resolveAssociations(ctx context.Context, existing, meal *model.MealEvent, userID uint) error {
+	if meal.MenuSets == nil {
+		meal.MenuSets = append([]model.MealEventSet{}, existing.MenuSets...)
+	}
+	if meal.Addresses == nil {
+		meal.Addresses = append([]model.MealEventAddress{}, existing.Addresses...)
+	}
+
+	currentSets := make(map[uint]model.MealEventSet, len(existing.MenuSets))
+	for _, set := range existing.MenuSets {
+		currentSets[set.MenuSetID] = set
+	}
+	for i := range meal.MenuSets {
+		set := &meal.MenuSets[i]
+		if current, ok := currentSets[set.MenuSetID]; ok {
+			set.MenuSet = current.MenuSet
+			set.CreatedBy = current.CreatedBy
+			set.CreatedAt = current.CreatedAt
+		} else {
+			menuSet, err := s.menuRepo.FindByID(ctx, set.MenuSetID)
+			if err != nil {
+				return errors.NewValidationError("invalid meal event", err).
+					WithFields(errors.FieldError{Field: "menu_sets", Message: fmt.Sprintf("menu set %d does not exist", set.MenuSetID)})
+			}
+			set.MenuSet = *menuSet
+			set.CreatedBy = userID
+			set.CreatedAt = time.Time{}
+		}
+		set.UpdatedBy = userID
+	}
+
+	currentAddresses := make(map[uint]model.MealEventAddress, len(existing.Addresses))
+	for _, address := range existing.Addresses {
+		currentAddresses[address.AddressID] = address
+	}
+	var newAddressIDs []uint
+	for _, address := range meal.Addresses {
+		if _, ok := currentAddresses[address.AddressID]; !ok {
+			newAddressIDs = append(newAddressIDs, address.AddressID)
+		}
+	}
+	found, err := s.mealRepo.FindAddressesByIDs(ctx, newAddressIDs)
+	if err != nil {
+		return errors.NewInternalError("failed to fetch event addresses", err)
+	}
+	newAddresses := make(map[uint]model.EventAddress, len(found))
+	for _, address := range found {
+		newAddresses[address.ID] = address
+	}
+	for i := range meal.Addresses {
+		address := &meal.Addresses[i]
+		if current, ok := currentAddresses[address.AddressID]; ok {
+			address.Address = current.Address
+			address.CreatedBy = current.CreatedBy
+		} else if eventAddress, ok := newAddresses[address.AddressID]; ok {
+			address.Address = eventAddress
+			address.CreatedBy = userID
+		} else {
+			return errors.NewValidationError("invalid meal event", nil).
+				WithFields(errors.FieldError{Field: "addresses", Message: fmt.Sprintf("event address %d does not exist", address.AddressID)})
+		}
+		address.UpdatedBy = userID
+	}
+	return nil
+}
+
+// notifyRequesters tells everyone who requested a meal event what changed. Requests whose
+// menu set or address was removed are flagged so that their requesters choose another one.
+func (s *mealEventService) notifyRequesters(ctx context.Context, meal *model.MealEvent, changes model.FieldChanges) error {
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return err
+	}
+
+	offeredSets := make(map[uint]bool, len(meal.MenuSets))
+	for _, set := range meal.MenuSets {
+		offeredSets[set.MenuSetID] = true
+	}
+	servedAddresses := make(map[uint]bool, len(meal.Addresses))
+	for _, address := range meal.Addresses {
+		servedAddresses[address.AddressID] = true
+	}
+
+	loc := mealLocation(meal, s.timeZone)
+	summary := fmt.Sprintf("%s on %s has changed. %s", meal.Name, formatMealTime(meal.EventDate, loc), describeChanges(changes))
+	for i := range requests {
+		request := &requests[i]
+		if request.Status != model.RequestStatusPending && request.Status != model.RequestStatusApproved {
+			continue
+		}
+
+		var problems []string
+		if request.MenuSetID != 0 && !offeredSets[request.MenuSetID] {
+			problems = append(problems, fmt.Sprintf("%s is no longer offered", request.MenuSet.MenuSetName))
+		}
+		if request.EventAddressID != 0 && !servedAddresses[request.EventAddressID] {
+			problems = append(problems, fmt.Sprintf("%s is no longer served", request.EventAddress.Address))
+		}
+
+		message := summary
+		if len(problems) > 0 {
+			note := strings.Join(problems, " and ")
+			if err := s.requestRepo.MarkNeedsAttention(ctx, request.ID, note); err != nil {
+				return err
+			}
+			message += " Please update your request: " + note + "."
+		} else if meal.Status == model.MealEventStatusConfirmed {
+			// Confirmed requesters already have a calendar invitation that needs the new details
+			s.calendar.SendRequestConfirmation(ctx, meal, request)
+		}
+
+		if err := s.notifService.CreateMealChangeNotification(ctx, request.UserID, meal.ID, message, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffMealEvent lists the fields that differ between two versions of a meal event.
+// Times are shown at the event's location.
+func diffMealEvent(before, after *model.MealEvent, loc *time.Location) model.FieldChanges {
+	var changes model.FieldChanges
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, model.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("name", before.Name, after.Name)
+	add("description", before.Description, after.Description)
+	add("meal_type", string(before.MealType), string(after.MealType))
+	add("event_date", formatMealTime(before.EventDate, loc), formatMealTime(after.EventDate, loc))
+	add("event_duration", fmt.Sprintf("%d minutes", before.EventDuration), fmt.Sprintf("%d minutes", after.EventDuration))
+	add("cutoff_time", formatMealTime(before.CutoffTime, loc), formatMealTime(after.CutoffTime, loc))
+	add("is_active", strconv.FormatBool(before.IsActive), strconv.FormatBool(after.IsActive))
+	add("menu_sets", describeMenuSets(before.MenuSets), describeMenuSets(after.MenuSets))
+	add("addresses", describeAddresses(before.Addresses), describeAddresses(after.Addresses))
+	add("allow_late_requests", strconv.FormatBool(before.AllowLateRequests), strconv.FormatBool(after.AllowLateRequests))
+	add("late_request_cap", strconv.Itoa(before.LateRequestCap), strconv.Itoa(after.LateRequestCap))
+	return changes
+}
+
+// hasMaterialChanges reports whether any change affects employees who already requested the meal
+func hasMaterialChanges(changes model.FieldChanges) bool {
+	for _, change := range changes {
+		if materialFields[change.Field] {
+			return true
+		}
+	}
+	return false
+}
+
+// describeChanges renders field changes as a sentence per field, e.g. "Time: A → B."
+func describeChanges(changes model.FieldChanges) string {
+	byField := make(map[string]model.FieldChange, len(changes))
+	for _, change := range changes {
+		byField[change.Field] = change
+	}
+
+	var lines []string
+	for _, field := range fieldLabels {
+		change, ok := byField[field.field]
+		if !ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s → %s.", field.label, orNone(change.From), orNone(change.To)))
+	}
+	return strings.Join(lines, " ")
+}
+
+// describeMenuSets lists menu set names with their labels, sorted by ID so that reordering is no change
+func describeMenuSets(sets []model.MealEventSet) string {
+	sorted := append([]model.MealEventSet{}, sets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MenuSetID < sorted[j].MenuSetID })
+
+	names := make([]string, 0, len(sorted))
+	for _, set := range sorted {
+		name := set.MenuSet.MenuSetName
+		if name == "" {
+			name = fmt.Sprintf("#%d", set.MenuSetID)
+		}
+		if set.Label != "" {
+			name += " (" + set.Label + ")"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// describeAddresses lists addresses, sorted by ID so that reordering is no change
+func describeAddresses(addresses []model.MealEventAddress) string {
+	sorted := append([]model.MealEventAddress{}, addresses...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].AddressID < sorted[j].AddressID })
+
+	names := make([]string, 0, len(sorted))
+	for _, address := range sorted {
+		name := address.Address.Address
+		if name == "" {
+			name = fmt.Sprintf("#%d", address.AddressID)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// orNone shows empty values in change descriptions
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
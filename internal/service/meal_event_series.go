@@ -0,0 +1,715 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+const (
+	// schedulingHorizonDays is how far ahead meal events may be set up (SRS: up to 30 days in advance)
+	schedulingHorizonDays = 30
+	dateLayout            = "2006-01-02"
+	mealDateLayout        = "Mon, 02 Jan 2006 15:04 MST"
+)
+
+// SeriesEditScope represents which occurrences of a series an edit applies to
+type SeriesEditScope string
+
+const (
+	SeriesEditThis      SeriesEditScope = "this"
+	SeriesEditFollowing SeriesEditScope = "following"
+)
+
+// SeriesChanges describes the fields changed when editing an occurrence of a series.
+// Nil fields are left unchanged. Recurrence fields only apply to "this and following" edits.
+type SeriesChanges struct {
+	Name          *string                    `json:"name"`
+	Description   *string                    `json:"description"`
+	StartTime     *string                    `json:"start_time" example:"13:00"`
+	EventDuration *int                       `json:"event_duration"`
+	CutoffOffset  *int                       `json:"cutoff_offset"`
+	Frequency     *model.RecurrenceFrequency `json:"frequency" enums:"daily,weekdays,weekly"`
+	Weekdays      *string                    `json:"weekdays" example:"mon,wed,fri"`
+	Until         *time.Time                 `json:"until"`
+	Count         *int                       `json:"count"`
+}
+
+// changesRecurrence reports whether the changes touch the recurrence rule
+func (c *SeriesChanges) changesRecurrence() bool {
+	return c.Frequency != nil || c.Weekdays != nil || c.Until != nil || c.Count != nil
+}
+
+// weekdayNames maps accepted weekday spellings to time.Weekday
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// mealEventSeriesService handles business logic for recurring meal event series
+type mealEventSeriesService struct {
+	seriesRepo  repository.MealEventSeriesRepository
+	mealRepo    repository.MealEventRepository
+	holidayRepo repository.HolidayRepository
+	mealService MealEventService
+	timeZone    string
+}
+
+// NewMealEventSeriesService creates a new instance of MealEventSeriesService
+func NewMealEventSeriesService(
+	seriesRepo repository.MealEventSeriesRepository,
+	mealRepo repository.MealEventRepository,
+	holidayRepo repository.HolidayRepository,
+	mealService MealEventService,
+	timeZone string,
+) MealEventSeriesService {
+	return &mealEventSeriesService{
+		seriesRepo:  seriesRepo,
+		mealRepo:    mealRepo,
+		holidayRepo: holidayRepo,
+		mealService: mealService,
+		timeZone:    timeZone,
+	}
+}
+
+// CreateSeries creates a new series and generates its events within the scheduling horizon
+func (s *mealEventSeriesService) CreateSeries(ctx context.Context, series *model.MealEventSeries, userID uint) error {
+	if series == nil {
+		return errors.NewValidationError("series cannot be nil", nil)
+	}
+	if series.MealType == "" {
+		series.MealType = model.MealTypeLunch
+	}
+	if err := validateSeries(series); err != nil {
+		return err
+	}
+
+	series.ID = 0
+	series.IsActive = true
+	series.GeneratedUntil = nil
+	series.StartDate = calendarDate(series.StartDate)
+	if series.Until != nil {
+		until := calendarDate(*series.Until)
+		series.Until = &until
+	}
+	series.CreatedBy = userID
+	series.UpdatedBy = userID
+	for i := range series.MenuSets {
+		series.MenuSets[i].CreatedBy = userID
+		series.MenuSets[i].UpdatedBy = userID
+	}
+	for i := range series.Addresses {
+		series.Addresses[i].CreatedBy = userID
+		series.Addresses[i].UpdatedBy = userID
+	}
+
+	if err := s.seriesRepo.Create(ctx, series); err != nil {
+		return errors.NewInternalError("failed to create meal event series", err)
+	}
+
+	// The request only names the addresses; the stored series carries their time zones
+	created, err := s.GetSeries(ctx, series.ID)
+	if err != nil {
+		return err
+	}
+	*series = *created
+
+	return s.generate(ctx, series, time.Now())
+}
+
+// GetSeries retrieves a series with its menu sets and addresses
+func (s *mealEventSeriesService) GetSeries(ctx context.Context, id uint) (*model.MealEventSeries, error) {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event series not found", err)
+	}
+	return series, nil
+}
+
+// ListSeries retrieves all series
+func (s *mealEventSeriesService) ListSeries(ctx context.Context) ([]model.MealEventSeries, error) {
+	return s.seriesRepo.FindAll(ctx)
+}
+
+// GetOccurrence retrieves a meal event generated by a series
+func (s *mealEventSeriesService) GetOccurrence(ctx context.Context, seriesID uint, mealEventID uint) (*model.MealEvent, error) {
+	return s.findOccurrence(ctx, seriesID, mealEventID)
+}
+
+// EditOccurrence edits a single occurrence of a series, detaching it from later series edits.
+// The occurrence must still be at the version the client read.
+func (s *mealEventSeriesService) EditOccurrence(ctx context.Context, seriesID uint, mealEventID uint, version int, changes *SeriesChanges, userID uint) (*model.MealEvent, error) {
+	if changes.changesRecurrence() {
+		return nil, errors.NewValidationError("the recurrence can only be changed for this and following occurrences", nil)
+	}
+
+	meal, err := s.findOccurrence(ctx, seriesID, mealEventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("meal event", meal.Version, version); err != nil {
+		return nil, err
+	}
+
+	if err := applyOccurrenceChanges(meal, changes, mealLocation(meal, s.timeZone)); err != nil {
+		return nil, err
+	}
+	meal.IsException = true
+	meal.UpdatedBy = userID
+
+	if err := saveOccurrence(ctx, s.mealRepo, meal); err != nil {
+		return nil, versionError("meal event", "update", err)
+	}
+	return meal, nil
+}
+
+// SplitSeries applies changes to an occurrence and every later one by ending the series
+// the day before the occurrence and continuing it as a new series from that day on.
+// Ending the old series, creating the new one and moving the occurrences happen in one
+// transaction; the new series' remaining occurrences are generated afterwards. The occurrence
+// must still be at the version the client read.
+func (s *mealEventSeriesService) SplitSeries(ctx context.Context, seriesID uint, mealEventID uint, version int, changes *SeriesChanges, userID uint) (*model.MealEventSeries, error) {
+	series, err := s.seriesRepo.FindByID(ctx, seriesID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event series not found", err)
+	}
+
+	meal, err := s.findOccurrence(ctx, seriesID, mealEventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion("meal event", meal.Version, version); err != nil {
+		return nil, err
+	}
+
+	loc := s.seriesLocation(series)
+	occurrence := meal.SeriesOccurrence.In(loc)
+	day := utils.StartOfDay(occurrence, loc)
+
+	next := &model.MealEventSeries{
+		Name:          series.Name,
+		Description:   series.Description,
+		MealType:      series.MealType,
+		Frequency:     series.Frequency,
+		Weekdays:      series.Weekdays,
+		StartDate:     calendarDate(day),
+		StartTime:     series.StartTime,
+		Until:         series.Until,
+		EventDuration: series.EventDuration,
+		CutoffOffset:  series.CutoffOffset,
+		IsActive:      true,
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	}
+	if series.Count != nil {
+		previous, err := expandSeries(series, loc, time.Time{}, day, nil)
+		if err != nil {
+			return nil, errors.NewInternalError("failed to expand meal event series", err)
+		}
+		remaining := *series.Count - len(previous)
+		next.Count = &remaining
+	}
+	applySeriesChanges(next, changes)
+	if err := validateSeries(next); err != nil {
+		return nil, err
+	}
+
+	for _, set := range series.MenuSets {
+		next.MenuSets = append(next.MenuSets, model.MealEventSeriesSet{
+			MenuSetID: set.MenuSetID,
+			Label:     set.Label,
+			Note:      set.Note,
+			CreatedBy: userID,
+			UpdatedBy: userID,
+		})
+	}
+	for _, address := range series.Addresses {
+		next.Addresses = append(next.Addresses, model.MealEventSeriesAddress{
+			AddressID: address.AddressID,
+			CreatedBy: userID,
+			UpdatedBy: userID,
+		})
+	}
+
+	err = s.seriesRepo.Transaction(ctx, func(seriesRepo repository.MealEventSeriesRepository, mealRepo repository.MealEventRepository) error {
+		following, err := mealRepo.FindBySeriesID(ctx, seriesID, day)
+		if err != nil {
+			return errors.NewInternalError("failed to fetch series events", err)
+		}
+
+		if err := seriesRepo.TruncateSeries(ctx, seriesID, calendarDate(day.AddDate(0, 0, -1)), userID); err != nil {
+			return errors.NewInternalError("failed to update meal event series", err)
+		}
+		if err := seriesRepo.Create(ctx, next); err != nil {
+			return errors.NewInternalError("failed to create meal event series", err)
+		}
+
+		// Move the already generated occurrences onto the new series
+		scheduled := map[string]time.Time{}
+		if len(following) > 0 {
+			last := following[len(following)-1].SeriesOccurrence.AddDate(0, 0, 1)
+			occurrences, err := expandSeries(next, loc, day, last, nil)
+			if err != nil {
+				return errors.NewInternalError("failed to expand meal event series", err)
+			}
+			for _, occurrence := range occurrences {
+				scheduled[occurrence.In(loc).Format(dateLayout)] = occurrence
+			}
+		}
+
+		for i := range following {
+			event := &following[i]
+			newOccurrence, matches := scheduled[event.SeriesOccurrence.In(loc).Format(dateLayout)]
+
+			switch {
+			case event.IsException:
+				// Occurrences edited on their own keep their edits
+			case matches:
+				applySeriesTemplate(event, next, newOccurrence)
+			case len(event.MealRequests) > 0:
+				// No longer part of the schedule, but already requested
+				event.IsException = true
+			default:
+				if err := mealRepo.Delete(ctx, event); err != nil {
+					return errors.NewInternalError("failed to remove meal event", err)
+				}
+				continue
+			}
+
+			event.SeriesID = &next.ID
+			event.UpdatedBy = userID
+			if err := saveOccurrence(ctx, mealRepo, event); err != nil {
+				return versionError("meal event", "update", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate from the stored series, which carries the time zones of its addresses
+	created, err := s.GetSeries(ctx, next.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.generate(ctx, created, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.GetSeries(ctx, next.ID)
+}
+
+// EndSeries stops a series that is still at the version the client read and removes its upcoming
+// occurrences that nobody has requested yet. Either all of it happens or, on failure, none of it.
+func (s *mealEventSeriesService) EndSeries(ctx context.Context, id uint, version int, userID uint) error {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("meal event series not found", err)
+	}
+	if err := checkVersion("meal event series", series.Version, version); err != nil {
+		return err
+	}
+
+	return s.seriesRepo.Transaction(ctx, func(seriesRepo repository.MealEventSeriesRepository, mealRepo repository.MealEventRepository) error {
+		if err := seriesRepo.Deactivate(ctx, id, version, userID); err != nil {
+			return versionError("meal event series", "end", err)
+		}
+
+		upcoming, err := mealRepo.FindBySeriesID(ctx, id, time.Now())
+		if err != nil {
+			return errors.NewInternalError("failed to fetch series events", err)
+		}
+
+		for i := range upcoming {
+			if len(upcoming[i].MealRequests) > 0 {
+				continue
+			}
+			if err := mealRepo.Delete(ctx, &upcoming[i]); err != nil {
+				return errors.NewInternalError("failed to remove meal event", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GenerateEvents materializes the occurrences of every active series within the scheduling horizon.
+// A series that fails is logged and retried on the next run; the others are generated regardless.
+func (s *mealEventSeriesService) GenerateEvents(ctx context.Context, now time.Time) error {
+	series, err := s.seriesRepo.FindActiveSeries(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range series {
+		if err := s.generate(ctx, &series[i], now); err != nil {
+			log.Printf("meal event series %d: failed to generate events: %v", series[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// generate creates the missing events of a series between its last generated date and the horizon.
+// Occurrences that were generated before and later deleted are not recreated.
+func (s *mealEventSeriesService) generate(ctx context.Context, series *model.MealEventSeries, now time.Time) error {
+	horizon := now.AddDate(0, 0, schedulingHorizonDays)
+	from := now
+	if series.GeneratedUntil != nil && series.GeneratedUntil.After(from) {
+		from = *series.GeneratedUntil
+	}
+	if !from.Before(horizon) {
+		return nil
+	}
+
+	loc := s.seriesLocation(series)
+	holidays, err := s.holidayRepo.FindByDateRange(ctx, utils.StartOfDay(from, loc), horizon)
+	if err != nil {
+		return err
+	}
+	byDay := make(map[string][]model.Holiday, len(holidays))
+	for _, holiday := range holidays {
+		day := holiday.Date.Format(dateLayout)
+		byDay[day] = append(byDay[day], holiday)
+	}
+
+	// Skip days on which the organization or every address of the series is closed
+	addressIDs := make([]uint, 0, len(series.Addresses))
+	for _, address := range series.Addresses {
+		addressIDs = append(addressIDs, address.AddressID)
+	}
+	closed := make(map[string]bool, len(byDay))
+	for day, closures := range byDay {
+		_, closed[day] = closuresFor(closures, addressIDs)
+	}
+
+	occurrences, err := expandSeries(series, loc, from, horizon, closed)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.mealRepo.FindBySeriesID(ctx, series.ID, utils.StartOfDay(from, loc))
+	if err != nil {
+		return err
+	}
+	scheduled := make(map[string]bool, len(existing))
+	for _, event := range existing {
+		scheduled[event.SeriesOccurrence.In(loc).Format(dateLayout)] = true
+	}
+
+	for _, occurrence := range occurrences {
+		if scheduled[occurrence.In(loc).Format(dateLayout)] {
+			continue
+		}
+
+		meal := newSeriesEvent(series, occurrence)
+		if !meal.CutoffTime.After(now) {
+			continue
+		}
+		if err := s.mealRepo.Create(ctx, meal); err != nil {
+			return err
+		}
+		// Publishing records the transition, applies standing orders and announces the event.
+		// An occurrence that cannot be published stays a draft for an admin to fix.
+		if err := s.mealService.PublishScheduled(ctx, meal); err != nil {
+			log.Printf("meal event series %d: failed to publish meal event %d: %v", series.ID, meal.ID, err)
+		}
+	}
+
+	return s.seriesRepo.UpdateGeneratedUntil(ctx, series.ID, horizon)
+}
+
+// seriesLocation returns the time zone a series is scheduled in: that of its first address
+// with a time zone, or the organization's
+func (s *mealEventSeriesService) seriesLocation(series *model.MealEventSeries) *time.Location {
+	names := make([]string, 0, len(series.Addresses)+1)
+	for _, address := range series.Addresses {
+		names = append(names, address.Address.TimeZone)
+	}
+	return utils.LoadLocation(append(names, s.timeZone)...)
+}
+
+// findOccurrence finds a meal event and checks that it was generated by the given series
+func (s *mealEventSeriesService) findOccurrence(ctx context.Context, seriesID uint, mealEventID uint) (*model.MealEvent, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	if meal.SeriesID == nil || *meal.SeriesID != seriesID || meal.SeriesOccurrence == nil {
+		return nil, errors.NewNotFoundError("meal event is not part of this series", nil)
+	}
+	return meal, nil
+}
+
+// saveOccurrence persists a series event without touching its associations. It fails with
+// repository.ErrVersionConflict if the event changed since it was read.
+func saveOccurrence(ctx context.Context, mealRepo repository.MealEventRepository, meal *model.MealEvent) error {
+	updated := *meal
+	updated.Sequence++
+	updated.MenuSets = nil
+	updated.Addresses = nil
+	updated.MealRequests = nil
+	updated.MenuItemComments = nil
+	if err := mealRepo.Update(ctx, &updated); err != nil {
+		return err
+	}
+	meal.Sequence = updated.Sequence
+	meal.Version = updated.Version
+	return nil
+}
+
+// newSeriesEvent builds the meal event for one occurrence of a series. It starts as a draft
+// and is published through the usual transition once created.
+func newSeriesEvent(series *model.MealEventSeries, occurrence time.Time) *model.MealEvent {
+	seriesID := series.ID
+	meal := &model.MealEvent{
+		Status:    model.MealEventStatusDraft,
+		IsActive:  true,
+		SeriesID:  &seriesID,
+		CreatedBy: series.UpdatedBy,
+		UpdatedBy: series.UpdatedBy,
+	}
+	applySeriesTemplate(meal, series, occurrence)
+
+	for _, set := range series.MenuSets {
+		meal.MenuSets = append(meal.MenuSets, model.MealEventSet{
+			MenuSetID: set.MenuSetID,
+			Label:     set.Label,
+			Note:      set.Note,
+			CreatedBy: series.UpdatedBy,
+			UpdatedBy: series.UpdatedBy,
+		})
+	}
+	for _, address := range series.Addresses {
+		meal.Addresses = append(meal.Addresses, model.MealEventAddress{
+			AddressID: address.AddressID,
+			CreatedBy: series.UpdatedBy,
+			UpdatedBy: series.UpdatedBy,
+		})
+	}
+
+	return meal
+}
+
+// applySeriesTemplate copies the series defaults onto an occurrence
+func applySeriesTemplate(meal *model.MealEvent, series *model.MealEventSeries, occurrence time.Time) {
+	meal.Name = series.Name
+	meal.Description = series.Description
+	meal.MealType = series.MealType
+	meal.EventDate = occurrence
+	meal.EventDuration = series.EventDuration
+	meal.CutoffTime = occurrence.Add(-time.Duration(series.CutoffOffset) * time.Minute)
+	meal.SeriesOccurrence = &occurrence
+}
+
+// applyOccurrenceChanges applies the non-recurrence changes to a single meal event,
+// keeping its cutoff the same distance before the start unless a new offset is given
+func applyOccurrenceChanges(meal *model.MealEvent, changes *SeriesChanges, loc *time.Location) error {
+	offset := meal.EventDate.Sub(meal.CutoffTime)
+
+	if changes.Name != nil {
+		meal.Name = *changes.Name
+	}
+	if changes.Description != nil {
+		meal.Description = *changes.Description
+	}
+	if changes.StartTime != nil {
+		hour, minute, err := parseStartTime(*changes.StartTime)
+		if err != nil {
+			return errors.NewValidationError("start time must be in HH:MM format", err)
+		}
+		meal.EventDate = utils.AtClock(meal.EventDate.In(loc), hour, minute, loc)
+	}
+	if changes.EventDuration != nil {
+		if *changes.EventDuration <= 0 {
+			return errors.NewValidationError("event duration must be positive", nil)
+		}
+		meal.EventDuration = *changes.EventDuration
+	}
+	if changes.CutoffOffset != nil {
+		if *changes.CutoffOffset < 0 {
+			return errors.NewValidationError("cutoff offset cannot be negative", nil)
+		}
+		offset = time.Duration(*changes.CutoffOffset) * time.Minute
+	}
+
+	meal.CutoffTime = meal.EventDate.Add(-offset)
+	return nil
+}
+
+// applySeriesChanges applies every non-nil change to a series.
+// Until and count are mutually exclusive, so setting one clears the other.
+func applySeriesChanges(series *model.MealEventSeries, changes *SeriesChanges) {
+	if changes.Name != nil {
+		series.Name = *changes.Name
+	}
+	if changes.Description != nil {
+		series.Description = *changes.Description
+	}
+	if changes.StartTime != nil {
+		series.StartTime = *changes.StartTime
+	}
+	if changes.EventDuration != nil {
+		series.EventDuration = *changes.EventDuration
+	}
+	if changes.CutoffOffset != nil {
+		series.CutoffOffset = *changes.CutoffOffset
+	}
+	if changes.Frequency != nil {
+		series.Frequency = *changes.Frequency
+	}
+	if changes.Weekdays != nil {
+		series.Weekdays = *changes.Weekdays
+	}
+	if changes.Until != nil {
+		until := calendarDate(*changes.Until)
+		series.Until = &until
+		series.Count = nil
+	}
+	if changes.Count != nil {
+		series.Count = changes.Count
+		series.Until = nil
+	}
+}
+
+// validateSeries checks that a series describes a usable schedule
+func validateSeries(series *model.MealEventSeries) error {
+	if strings.TrimSpace(series.Name) == "" {
+		return errors.NewValidationError("series name is required", nil)
+	}
+	if !series.MealType.IsValid() {
+		return errors.NewValidationError("meal type must be breakfast, lunch or snacks", nil)
+	}
+	if series.StartDate.IsZero() {
+		return errors.NewValidationError("start date is required", nil)
+	}
+	if _, _, err := parseStartTime(series.StartTime); err != nil {
+		return errors.NewValidationError("start time must be in HH:MM format", err)
+	}
+	if _, err := seriesWeekdays(series); err != nil {
+		return errors.NewValidationError(err.Error(), nil)
+	}
+	if series.EventDuration <= 0 {
+		return errors.NewValidationError("event duration must be positive", nil)
+	}
+	if series.CutoffOffset < 0 {
+		return errors.NewValidationError("cutoff offset cannot be negative", nil)
+	}
+	if series.Until != nil && series.Count != nil {
+		return errors.NewValidationError("until and count cannot both be set", nil)
+	}
+	if series.Count != nil && *series.Count <= 0 {
+		return errors.NewValidationError("count must be positive", nil)
+	}
+	if series.Until != nil && calendarDate(*series.Until).Before(calendarDate(series.StartDate)) {
+		return errors.NewValidationError("until cannot be before the start date", nil)
+	}
+	return nil
+}
+
+// expandSeries returns the start times of the occurrences of a series in [from, to).
+// Occurrences start at the series' wall clock time in loc, so they stay at the same local time
+// across daylight saving changes. Closed days are skipped but still count towards the series count,
+// like RRULE exclusion dates.
+func expandSeries(series *model.MealEventSeries, loc *time.Location, from, to time.Time, holidays map[string]bool) ([]time.Time, error) {
+	hour, minute, err := parseStartTime(series.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	weekdays, err := seriesWeekdays(series)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []time.Time
+	count := 0
+	for day := utils.AtClock(series.StartDate, 0, 0, loc); ; day = day.AddDate(0, 0, 1) {
+		start := utils.AtClock(day, hour, minute, loc)
+		if !start.Before(to) {
+			break
+		}
+		if series.Until != nil && day.After(utils.AtClock(*series.Until, 0, 0, loc)) {
+			break
+		}
+		if !weekdays[day.Weekday()] {
+			continue
+		}
+
+		count++
+		if series.Count != nil && count > *series.Count {
+			break
+		}
+		if start.Before(from) || holidays[day.Format(dateLayout)] {
+			continue
+		}
+		occurrences = append(occurrences, start)
+	}
+
+	return occurrences, nil
+}
+
+// seriesWeekdays returns the days of the week on which a series occurs
+func seriesWeekdays(series *model.MealEventSeries) (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+
+	switch series.Frequency {
+	case model.RecurrenceDaily:
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			days[day] = true
+		}
+	case model.RecurrenceWeekdays:
+		for day := time.Monday; day <= time.Friday; day++ {
+			days[day] = true
+		}
+	case model.RecurrenceWeekly:
+		if strings.TrimSpace(series.Weekdays) == "" {
+			days[series.StartDate.Weekday()] = true
+			break
+		}
+		return parseWeekdays(series.Weekdays)
+	default:
+		return nil, fmt.Errorf("unknown frequency %q", series.Frequency)
+	}
+
+	return days, nil
+}
+
+// parseWeekdays parses a comma-separated list of weekday names such as "mon,wed,fri"
+func parseWeekdays(value string) (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+	for _, name := range strings.Split(value, ",") {
+		day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		days[day] = true
+	}
+	return days, nil
+}
+
+// parseStartTime parses an HH:MM time of day
+func parseStartTime(value string) (int, int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// calendarDate returns the calendar date of t as midnight UTC, the form in which
+// date-only fields such as a series' start date and until are stored
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata" // the tests must not depend on the zone database of the host
+
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+func TestExpandSeriesKeepsWallClockAcrossDaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("load Europe/Berlin: %v", err)
+	}
+
+	// Weekly on Sundays from before spring forward (30 March 2025) to after fall back (26 October 2025)
+	until := time.Date(2025, time.November, 2, 0, 0, 0, 0, time.UTC)
+	series := &model.MealEventSeries{
+		Frequency: model.RecurrenceWeekly,
+		StartDate: time.Date(2025, time.March, 23, 0, 0, 0, 0, time.UTC),
+		StartTime: "12:30",
+		Until:     &until,
+	}
+
+	occurrences, err := expandSeries(series, berlin, time.Time{}, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("expandSeries: %v", err)
+	}
+	if len(occurrences) != 33 {
+		t.Fatalf("got %d occurrences, want 33", len(occurrences))
+	}
+
+	for i, occurrence := range occurrences {
+		local := occurrence.In(berlin)
+		if local.Weekday() != time.Sunday || local.Format("15:04") != "12:30" {
+			t.Errorf("occurrence %d = %s, want Sunday 12:30", i, local.Format("Mon 2006-01-02 15:04 MST"))
+		}
+		if i > 0 && local.Sub(occurrences[i-1].In(berlin)) != 7*24*time.Hour {
+			// The week containing a daylight saving change is an hour shorter or longer
+			if day := local.Format("2006-01-02"); day != "2025-03-30" && day != "2025-10-26" {
+				t.Errorf("occurrence %d is %v after the previous one", i, local.Sub(occurrences[i-1]))
+			}
+		}
+	}
+
+	tests := []struct {
+		day    string
+		offset time.Duration // from UTC
+	}{
+		{"2025-03-23", time.Hour},
+		{"2025-03-30", 2 * time.Hour},
+		{"2025-10-19", 2 * time.Hour},
+		{"2025-10-26", time.Hour},
+		{"2025-11-02", time.Hour},
+	}
+	for _, tt := range tests {
+		var found bool
+		for _, occurrence := range occurrences {
+			local := occurrence.In(berlin)
+			if local.Format("2006-01-02") != tt.day {
+				continue
+			}
+			found = true
+			if _, offset := local.Zone(); time.Duration(offset)*time.Second != tt.offset {
+				t.Errorf("%s: offset %ds, want %v", tt.day, offset, tt.offset)
+			}
+		}
+		if !found {
+			t.Errorf("no occurrence on %s", tt.day)
+		}
+	}
+}
+
+func TestExpandSeriesInDaylightSavingGap(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("load Europe/Berlin: %v", err)
+	}
+
+	count := 3
+	series := &model.MealEventSeries{
+		Frequency: model.RecurrenceDaily,
+		StartDate: time.Date(2025, time.March, 29, 0, 0, 0, 0, time.UTC),
+		StartTime: "02:30",
+		Count:     &count,
+	}
+
+	occurrences, err := expandSeries(series, berlin, time.Time{}, time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("expandSeries: %v", err)
+	}
+
+	// 02:30 does not exist on 30 March; it is read with the offset from before the change
+	want := []string{
+		"2025-03-29 02:30 CET",
+		"2025-03-30 03:30 CEST",
+		"2025-03-31 02:30 CEST",
+	}
+	if len(occurrences) != len(want) {
+		t.Fatalf("got %d occurrences, want %d", len(occurrences), len(want))
+	}
+	for i, occurrence := range occurrences {
+		if got := occurrence.In(berlin).Format("2006-01-02 15:04 MST"); got != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+// fakeSeriesRepository stores one series and, like the database, fills in the addresses it names
+type fakeSeriesRepository struct {
+	repository.MealEventSeriesRepository
+	addresses map[uint]model.EventAddress
+	stored    *model.MealEventSeries
+}
+
+func (r *fakeSeriesRepository) Create(ctx context.Context, series *model.MealEventSeries) error {
+	series.ID = 1
+	stored := *series
+	r.stored = &stored
+	return nil
+}
+
+func (r *fakeSeriesRepository) FindByID(ctx context.Context, id uint) (*model.MealEventSeries, error) {
+	series := *r.stored
+	series.Addresses = nil
+	for _, address := range r.stored.Addresses {
+		address.Address = r.addresses[address.AddressID]
+		series.Addresses = append(series.Addresses, address)
+	}
+	return &series, nil
+}
+
+func (r *fakeSeriesRepository) UpdateGeneratedUntil(ctx context.Context, seriesID uint, until time.Time) error {
+	return nil
+}
+
+// fakeSeriesMealRepository collects the events generated for a series
+type fakeSeriesMealRepository struct {
+	repository.MealEventRepository
+	created []*model.MealEvent
+}
+
+func (r *fakeSeriesMealRepository) FindBySeriesID(ctx context.Context, seriesID uint, from time.Time) ([]model.MealEvent, error) {
+	return nil, nil
+}
+
+func (r *fakeSeriesMealRepository) Create(ctx context.Context, meal *model.MealEvent) error {
+	r.created = append(r.created, meal)
+	return nil
+}
+
+type fakeHolidayRepository struct {
+	repository.HolidayRepository
+}
+
+func (r *fakeHolidayRepository) FindByDateRange(ctx context.Context, startDate, endDate time.Time) ([]model.Holiday, error) {
+	return nil, nil
+}
+
+type fakePublisher struct {
+	MealEventService
+}
+
+func (p *fakePublisher) PublishScheduled(ctx context.Context, meal *model.MealEvent) error {
+	return nil
+}
+
+func TestCreateSeriesSchedulesInTheAddressTimeZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("load Asia/Tokyo: %v", err)
+	}
+
+	seriesRepo := &fakeSeriesRepository{
+		addresses: map[uint]model.EventAddress{5: {TimeZone: "Asia/Tokyo"}},
+	}
+	mealRepo := &fakeSeriesMealRepository{}
+	service := NewMealEventSeriesService(seriesRepo, mealRepo, &fakeHolidayRepository{}, &fakePublisher{}, "UTC")
+
+	count := 3
+	series := &model.MealEventSeries{
+		Name:          "Lunch",
+		MealType:      model.MealTypeLunch,
+		Frequency:     model.RecurrenceDaily,
+		StartDate:     time.Now().AddDate(0, 0, 2),
+		StartTime:     "12:30",
+		Count:         &count,
+		EventDuration: 60,
+		Addresses:     []model.MealEventSeriesAddress{{AddressID: 5}},
+	}
+	if err := service.CreateSeries(context.Background(), series, 1); err != nil {
+		t.Fatalf("CreateSeries: %v", err)
+	}
+
+	if len(mealRepo.created) != count {
+		t.Fatalf("got %d events, want %d", len(mealRepo.created), count)
+	}
+	for i, meal := range mealRepo.created {
+		if clock := meal.EventDate.In(tokyo).Format("15:04 MST"); clock != "12:30 JST" {
+			t.Errorf("event %d starts at %s, want 12:30 JST", i, clock)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+// mealEventTemplateService handles business logic for meal event templates
+type mealEventTemplateService struct {
+	templateRepo  repository.MealEventTemplateRepository
+	mealRepo      repository.MealEventRepository
+	holidayRepo   repository.HolidayRepository
+	closurePolicy ClosurePolicy
+	timeZone      string
+}
+
+// NewMealEventTemplateService creates a new instance of MealEventTemplateService
+func NewMealEventTemplateService(
+	templateRepo repository.MealEventTemplateRepository,
+	mealRepo repository.MealEventRepository,
+	holidayRepo repository.HolidayRepository,
+	closurePolicy ClosurePolicy,
+	timeZone string,
+) MealEventTemplateService {
+	return &mealEventTemplateService{
+		templateRepo:  templateRepo,
+		mealRepo:      mealRepo,
+		holidayRepo:   holidayRepo,
+		closurePolicy: closurePolicy,
+		timeZone:      timeZone,
+	}
+}
+
+// CreateTemplate creates a new meal event template
+func (s *mealEventTemplateService) CreateTemplate(ctx context.Context, template *model.MealEventTemplate, userID uint) error {
+	if template == nil {
+		return errors.NewValidationError("template cannot be nil", nil)
+	}
+	if template.MealType == "" {
+		template.MealType = model.MealTypeLunch
+	}
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+	if _, err := s.templateRepo.FindByName(ctx, template.Name); err == nil {
+		return errors.NewConflictError("a template with this name already exists", nil)
+	}
+
+	template.ID = 0
+	template.IsActive = true
+	template.CreatedBy = userID
+	template.UpdatedBy = userID
+	stampTemplateDefaults(template, userID)
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return errors.NewInternalError("failed to create template", err)
+	}
+	return nil
+}
+
+// GetTemplate retrieves a meal event template with its menu sets and addresses
+func (s *mealEventTemplateService) GetTemplate(ctx context.Context, id uint) (*model.MealEventTemplate, error) {
+	template, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("template not found", err)
+	}
+	return template, nil
+}
+
+// ListTemplates retrieves all meal event templates
+func (s *mealEventTemplateService) ListTemplates(ctx context.Context) ([]model.MealEventTemplate, error) {
+	return s.templateRepo.FindAll(ctx)
+}
+
+// UpdateTemplate replaces the defaults stored in a meal event template if it is still at the
+// version the client read
+func (s *mealEventTemplateService) UpdateTemplate(ctx context.Context, id uint, version int, template *model.MealEventTemplate, userID uint) error {
+	existing, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("template not found", err)
+	}
+	if err := checkVersion("template", existing.Version, version); err != nil {
+		return err
+	}
+	if template.MealType == "" {
+		template.MealType = model.MealTypeLunch
+	}
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+	if other, err := s.templateRepo.FindByName(ctx, template.Name); err == nil && other.ID != id {
+		return errors.NewConflictError("a template with this name already exists", nil)
+	}
+
+	template.ID = id
+	template.CreatedBy = existing.CreatedBy
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedBy = userID
+	template.Version = version
+	stampTemplateDefaults(template, userID)
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return versionError("template", "update", err)
+	}
+	return nil
+}
+
+// DeleteTemplate deletes a meal event template if it is still at the version the client read.
+// Events created from it are not affected.
+func (s *mealEventTemplateService) DeleteTemplate(ctx context.Context, id uint, version int) error {
+	template, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("template not found", err)
+	}
+	if err := checkVersion("template", template.Version, version); err != nil {
+		return err
+	}
+	if err := s.templateRepo.DeleteVersion(ctx, template, version); err != nil {
+		return versionError("template", "delete", err)
+	}
+	return nil
+}
+
+// CreateEventFromTemplate creates a meal event on the given date using a template's defaults
+func (s *mealEventTemplateService) CreateEventFromTemplate(ctx context.Context, templateID uint, date time.Time, userID uint) (*model.MealEvent, error) {
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("template not found", err)
+	}
+	if !template.IsActive {
+		return nil, errors.NewValidationError("template is not active", nil)
+	}
+
+	loc := s.templateLocation(template)
+	meal, err := newTemplateEvent(template, date, loc, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchedulingWindow(meal.EventDate, meal.CutoffTime, time.Now()); err != nil {
+		return nil, err
+	}
+
+	closureFields, closureWarnings, err := checkClosures(ctx, s.holidayRepo, s.closurePolicy, meal, loc)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to check the closure calendar", err)
+	}
+	if len(closureFields) > 0 {
+		return nil, errors.NewValidationError("meal event falls on a closed date", nil).WithFields(closureFields...)
+	}
+
+	if err := s.mealRepo.Create(ctx, meal); err != nil {
+		return nil, errors.NewInternalError("failed to create meal event", err)
+	}
+	meal.Warnings = closureWarnings
+	return meal, nil
+}
+
+// templateLocation returns the time zone of a template's first address with one, or the organization's
+func (s *mealEventTemplateService) templateLocation(template *model.MealEventTemplate) *time.Location {
+	names := make([]string, 0, len(template.Addresses)+1)
+	for _, address := range template.Addresses {
+		names = append(names, address.Address.TimeZone)
+	}
+	return utils.LoadLocation(append(names, s.timeZone)...)
+}
+
+// newTemplateEvent builds a meal event on the given calendar date from a template's defaults,
+// starting at the template's wall clock time in loc
+func newTemplateEvent(template *model.MealEventTemplate, date time.Time, loc *time.Location, userID uint) (*model.MealEvent, error) {
+	hour, minute, err := parseStartTime(template.StartTime)
+	if err != nil {
+		return nil, errors.NewValidationError("template start time must be in HH:MM format", err)
+	}
+
+	eventDate := utils.AtClock(date, hour, minute, loc)
+	meal := &model.MealEvent{
+		Status:        model.MealEventStatusDraft,
+		Name:          template.EventName,
+		Description:   template.Description,
+		MealType:      template.MealType,
+		EventDate:     eventDate,
+		EventDuration: template.EventDuration,
+		CutoffTime:    eventDate.Add(-time.Duration(template.CutoffOffset) * time.Minute),
+		IsActive:      true,
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	}
+
+	for _, set := range template.MenuSets {
+		meal.MenuSets = append(meal.MenuSets, model.MealEventSet{
+			MenuSetID: set.MenuSetID,
+			Label:     set.Label,
+			Note:      set.Note,
+			CreatedBy: userID,
+			UpdatedBy: userID,
+		})
+	}
+	for _, address := range template.Addresses {
+		meal.Addresses = append(meal.Addresses, model.MealEventAddress{
+			AddressID: address.AddressID,
+			CreatedBy: userID,
+			UpdatedBy: userID,
+		})
+	}
+
+	return meal, nil
+}
+
+// stampTemplateDefaults records who last set the template's menu sets and addresses
+func stampTemplateDefaults(template *model.MealEventTemplate, userID uint) {
+	for i := range template.MenuSets {
+		template.MenuSets[i].CreatedBy = userID
+		template.MenuSets[i].UpdatedBy = userID
+	}
+	for i := range template.Addresses {
+		template.Addresses[i].CreatedBy = userID
+		template.Addresses[i].UpdatedBy = userID
+	}
+}
+
+// validateTemplate checks that a template describes a usable event
+func validateTemplate(template *model.MealEventTemplate) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return errors.NewValidationError("template name is required", nil)
+	}
+	if strings.TrimSpace(template.EventName) == "" {
+		return errors.NewValidationError("event name is required", nil)
+	}
+	if !template.MealType.IsValid() {
+		return errors.NewValidationError("meal type must be breakfast, lunch or snacks", nil)
+	}
+	if _, _, err := parseStartTime(template.StartTime); err != nil {
+		return errors.NewValidationError("start time must be in HH:MM format", err)
+	}
+	if template.EventDuration <= 0 {
+		return errors.NewValidationError("event duration must be positive", nil)
+	}
+	if template.CutoffOffset < 0 {
+		return errors.NewValidationError("cutoff offset cannot be negative", nil)
+	}
+	return nil
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// maxScanClockSkew is how far in the future a kiosk's clock may be when it reports a scan time
+const maxScanClockSkew = time.Minute
+
+// PickupToken is a short-lived signed token for an approved meal request, shown as a QR code
+type PickupToken struct {
+	MealRequestID uint      `json:"meal_request_id"`
+	Token         string    `json:"token"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// PickupVerificationKey lets kiosks verify pickup tokens without reaching the server
+type PickupVerificationKey struct {
+	Algorithm string `json:"algorithm" example:"Ed25519"`
+	PublicKey string `json:"public_key"` // base64 encoded
+	Format    string `json:"format" example:"v1.<base64url payload>.<base64url signature of v1.<base64url payload>>"`
+}
+
+// PickupScan is a pickup token read at a kiosk
+type PickupScan struct {
+	Token     string     `json:"token" binding:"required"`
+	AddressID uint       `json:"address_id" binding:"required"` // where the kiosk stands
+	ScannedAt *time.Time `json:"scanned_at"`                    // when an offline kiosk read the token; defaults to now
+}
+
+// PickupScanStatus is the outcome of a scan
+type PickupScanStatus string
+
+const (
+	PickupScanPickedUp        PickupScanStatus = "picked_up"
+	PickupScanAlreadyPickedUp PickupScanStatus = "already_picked_up"
+)
+
+// PickupScanResult tells the kiosk whose meal was scanned and whether to hand it out
+type PickupScanResult struct {
+	Status             PickupScanStatus `json:"status" enums:"picked_up,already_picked_up"`
+	MealRequestID      uint             `json:"meal_request_id"`
+	UserID             uint             `json:"user_id"`
+	Name               string           `json:"name"`
+	Department         string           `json:"department"`
+	MealEventID        uint             `json:"meal_event_id"`
+	MealName           string           `json:"meal_name"`
+	MenuSetName        string           `json:"menu_set_name"`
+	RequestedAddressID uint             `json:"requested_address_id"`
+	PickedUpAt         time.Time        `json:"picked_up_at"` // of the first scan when already picked up
+	WrongLocation      bool             `json:"wrong_location"`
+	Warnings           []string         `json:"warnings,omitempty"`
+}
+
+// MealPickupSummary compares the approved meals of a meal event with the meals picked up
+type MealPickupSummary struct {
+	MealEventID uint               `json:"meal_event_id"`
+	Approved    int                `json:"approved"` // approved, picked up and no-show requests
+	PickedUp    int                `json:"picked_up"`
+	NoShows     int                `json:"no_shows"`
+	Outstanding int                `json:"outstanding"` // approved and not yet picked up
+	Pickups     []model.MealPickup `json:"pickups"`
+}
+
+// NoShowStats counts picked up and unclaimed meals
+type NoShowStats struct {
+	Confirmed  int     `json:"confirmed"`
+	PickedUp   int     `json:"picked_up"`
+	NoShows    int     `json:"no_shows"`
+	NoShowRate float64 `json:"no_show_rate"` // no-shows per confirmed meal
+}
+
+// UserNoShows counts one employee's unclaimed meals
+type UserNoShows struct {
+	UserID     uint   `json:"user_id"`
+	Name       string `json:"name"`
+	EmployeeID string `json:"employee_id"`
+	Department string `json:"department"`
+	NoShowStats
+}
+
+// DepartmentNoShows counts a department's unclaimed meals
+type DepartmentNoShows struct {
+	Department string `json:"department"`
+	NoShowStats
+}
+
+// NoShowReport counts the unclaimed meals of the meal events within a date range that were
+// checked in at a kiosk, per user and per department
+type NoShowReport struct {
+	StartDate   time.Time           `json:"start_date"`
+	EndDate     time.Time           `json:"end_date"`
+	Totals      NoShowStats         `json:"totals"`
+	Users       []UserNoShows       `json:"users"`
+	Departments []DepartmentNoShows `json:"departments"`
+}
+
+// mealPickupService implements MealPickupService interface
+type mealPickupService struct {
+	mealRepo    repository.MealEventRepository
+	requestRepo repository.MealRequestRepository
+	userRepo    repository.UserRepository
+	signer      *pickupSigner
+	tokenTTL    time.Duration
+	noShowGrace time.Duration
+}
+
+// NewMealPickupService creates a new instance of MealPickupService
+func NewMealPickupService(
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	userRepo repository.UserRepository,
+	signingKey []byte,
+	tokenTTL time.Duration,
+	noShowGrace time.Duration,
+) MealPickupService {
+	return &mealPickupService{
+		mealRepo:    mealRepo,
+		requestRepo: requestRepo,
+		userRepo:    userRepo,
+		signer:      newPickupSigner(signingKey),
+		tokenTTL:    tokenTTL,
+		noShowGrace: noShowGrace,
+	}
+}
+
+// IssueToken signs a pickup token for the user's approved meal request. Tokens expire after the
+// token lifetime, and never later than the no-show grace period after the meal event ends.
+func (s *mealPickupService) IssueToken(ctx context.Context, requestID uint, userID uint) (*PickupToken, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if request.UserID != userID {
+		return nil, errors.NewForbiddenError("unauthorized to access this request", nil)
+	}
+	if request.Status != model.RequestStatusApproved {
+		return nil, errors.NewValidationError("only confirmed meal requests can be picked up", nil)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.tokenTTL)
+	if deadline := s.pickupDeadline(meal); deadline.Before(expiresAt) {
+		expiresAt = deadline
+	}
+	if !expiresAt.After(now) {
+		return nil, errors.NewValidationError("the pickup window of this meal has ended", nil)
+	}
+
+	token, err := s.signer.sign(PickupClaims{
+		MealRequestID: request.ID,
+		UserID:        request.UserID,
+		MealEventID:   request.MealEventID,
+		AddressID:     request.EventAddressID,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("failed to sign pickup token", err)
+	}
+
+	return &PickupToken{
+		MealRequestID: request.ID,
+		Token:         token,
+		ExpiresAt:     time.Unix(expiresAt.Unix(), 0),
+	}, nil
+}
+
+// GetVerificationKey returns the public key that pickup tokens are signed with
+func (s *mealPickupService) GetVerificationKey() *PickupVerificationKey {
+	return &PickupVerificationKey{
+		Algorithm: "Ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(s.signer.publicKey),
+		Format:    pickupTokenVersion + ".<base64url payload>.<base64url signature of " + pickupTokenVersion + ".<base64url payload>>",
+	}
+}
+
+// ScanPickup checks a pickup token read at a kiosk and records the pickup. A meal picked up before
+// is reported with the time of the first scan; a meal picked up away from its requested address
+// is handed out with a warning.
+func (s *mealPickupService) ScanPickup(ctx context.Context, scan *PickupScan, staffID uint) (*PickupScanResult, error) {
+	now := time.Now()
+	scannedAt := now
+	if scan.ScannedAt != nil {
+		if scan.ScannedAt.After(now.Add(maxScanClockSkew)) {
+			return nil, errors.NewValidationError("invalid scan time", nil).
+				WithFields(errors.FieldError{Field: "scanned_at", Message: "scan time must not be in the future"})
+		}
+		scannedAt = *scan.ScannedAt
+	}
+
+	claims, err := s.signer.verify(scan.Token)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid pickup token", err).
+			WithFields(errors.FieldError{Field: "token", Message: err.Error()})
+	}
+	if claims.expired(scannedAt) {
+		return nil, errors.NewValidationError("pickup token has expired", nil).
+			WithFields(errors.FieldError{Field: "token", Message: "ask for a fresh code"})
+	}
+
+	request, err := s.requestRepo.FindWithDetails(ctx, claims.MealRequestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if request.UserID != claims.UserID || request.MealEventID != claims.MealEventID {
+		return nil, errors.NewValidationError("invalid pickup token", nil).
+			WithFields(errors.FieldError{Field: "token", Message: "token does not match the meal request"})
+	}
+
+	result := &PickupScanResult{
+		Status:             PickupScanPickedUp,
+		MealRequestID:      request.ID,
+		UserID:             request.UserID,
+		Name:               request.User.Name,
+		Department:         request.User.Department,
+		MealEventID:        request.MealEventID,
+		MealName:           request.MealEvent.Name,
+		MenuSetName:        request.MenuSet.MenuSetName,
+		RequestedAddressID: request.EventAddressID,
+	}
+
+	if existing, err := s.requestRepo.FindPickup(ctx, request.ID); err == nil {
+		return s.alreadyPickedUp(result, &request.MealEvent, existing), nil
+	}
+
+	switch request.Status {
+	case model.RequestStatusApproved, model.RequestStatusNoShow, model.RequestStatusCompleted:
+	default:
+		return nil, errors.NewValidationError(fmt.Sprintf("meal request is %s, not confirmed", request.Status), nil)
+	}
+
+	pickup := &model.MealPickup{
+		MealRequestID: request.ID,
+		MealEventID:   request.MealEventID,
+		UserID:        request.UserID,
+		AddressID:     scan.AddressID,
+		PickedUpAt:    scannedAt,
+		WrongLocation: scan.AddressID != request.EventAddressID,
+		ScannedBy:     staffID,
+	}
+
+	// Completing the request and recording the pickup succeed together, so a meal scanned at two
+	// kiosks at once is handed out only by one of them
+	err = s.requestRepo.Transaction(ctx, func(repo repository.MealRequestRepository) error {
+		if request.Status != model.RequestStatusCompleted {
+			if err := transitionRequest(ctx, repo, request, model.RequestStatusCompleted, "meal picked up", &staffID); err != nil {
+				return err
+			}
+		}
+		return repo.CreatePickup(ctx, pickup)
+	})
+	if err != nil {
+		if existing, findErr := s.requestRepo.FindPickup(ctx, request.ID); findErr == nil {
+			return s.alreadyPickedUp(result, &request.MealEvent, existing), nil
+		}
+		if _, ok := err.(*errors.AppError); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalError("failed to record pickup", err)
+	}
+
+	result.PickedUpAt = pickup.PickedUpAt
+	result.WrongLocation = pickup.WrongLocation
+	if pickup.WrongLocation {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("meal was requested for %s", addressName(&request.MealEvent, request.EventAddressID)))
+	}
+	return result, nil
+}
+
+// alreadyPickedUp completes a scan result for a meal that was picked up before
+func (s *mealPickupService) alreadyPickedUp(result *PickupScanResult, meal *model.MealEvent, pickup *model.MealPickup) *PickupScanResult {
+	result.Status = PickupScanAlreadyPickedUp
+	result.PickedUpAt = pickup.PickedUpAt
+	result.WrongLocation = pickup.WrongLocation
+	result.Warnings = append(result.Warnings, fmt.Sprintf("meal was already picked up at %s on %s",
+		addressName(meal, pickup.AddressID), pickup.PickedUpAt.UTC().Format(time.RFC3339)))
+	return result
+}
+
+// GetMealPickups compares the approved meals of a meal event with the meals picked up
+func (s *mealPickupService) GetMealPickups(ctx context.Context, mealEventID uint) (*MealPickupSummary, error) {
+	if _, err := s.mealRepo.FindByID(ctx, mealEventID); err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	requests, err := s.requestRepo.FindByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	pickups, err := s.requestRepo.FindPickupsByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch pickups", err)
+	}
+
+	summary := &MealPickupSummary{
+		MealEventID: mealEventID,
+		PickedUp:    len(pickups),
+		Pickups:     pickups,
+	}
+	for _, request := range requests {
+		switch request.Status {
+		case model.RequestStatusApproved:
+			summary.Approved++
+			summary.Outstanding++
+		case model.RequestStatusCompleted:
+			summary.Approved++
+		case model.RequestStatusNoShow:
+			summary.Approved++
+			summary.NoShows++
+		}
+	}
+	return summary, nil
+}
+
+// GetNoShowReport counts the picked up and unclaimed meals within a date range per user and department
+func (s *mealPickupService) GetNoShowReport(ctx context.Context, startDate, endDate time.Time) (*NoShowReport, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.NewValidationError("end date must not be before start date", nil)
+	}
+
+	requests, err := s.requestRepo.FindCheckedInByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+
+	report := &NoShowReport{
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Users:       []UserNoShows{},
+		Departments: []DepartmentNoShows{},
+	}
+	users := make(map[uint]*UserNoShows)
+	departments := make(map[string]*DepartmentNoShows)
+	for _, request := range requests {
+		user, ok := users[request.UserID]
+		if !ok {
+			user = &UserNoShows{
+				UserID:     request.UserID,
+				Name:       request.User.Name,
+				EmployeeID: request.User.EmployeeID,
+				Department: request.User.Department,
+			}
+			users[request.UserID] = user
+		}
+		department, ok := departments[request.User.Department]
+		if !ok {
+			department = &DepartmentNoShows{Department: request.User.Department}
+			departments[request.User.Department] = department
+		}
+
+		noShow := request.Status == model.RequestStatusNoShow
+		report.Totals.count(noShow)
+		user.count(noShow)
+		department.count(noShow)
+	}
+
+	for _, user := range users {
+		report.Users = append(report.Users, *user)
+	}
+	for _, department := range departments {
+		report.Departments = append(report.Departments, *department)
+	}
+	sort.Slice(report.Users, func(i, j int) bool {
+		if report.Users[i].NoShows != report.Users[j].NoShows {
+			return report.Users[i].NoShows > report.Users[j].NoShows
+		}
+		return report.Users[i].Name < report.Users[j].Name
+	})
+	sort.Slice(report.Departments, func(i, j int) bool {
+		return report.Departments[i].Department < report.Departments[j].Department
+	})
+	return report, nil
+}
+
+// count adds a confirmed meal to the stats
+func (s *NoShowStats) count(noShow bool) {
+	s.Confirmed++
+	if noShow {
+		s.NoShows++
+	} else {
+		s.PickedUp++
+	}
+	s.NoShowRate = float64(s.NoShows) / float64(s.Confirmed)
+}
+
+// MarkNoShows marks the approved meals that were not picked up within the grace period after their
+// meal event ended. Meal events without any pickup are left alone, as nobody checked meals in there.
+func (s *mealPickupService) MarkNoShows(ctx context.Context, now time.Time) error {
+	requests, err := s.requestRepo.FindUnclaimed(ctx, now.Add(-s.noShowGrace))
+	if err != nil {
+		return err
+	}
+
+	for i := range requests {
+		err := transitionRequest(ctx, s.requestRepo, &requests[i], model.RequestStatusNoShow, "meal was not picked up", nil)
+		if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+			return err
+		}
+	}
+	return nil
+}
+
+// pickupDeadline is the last moment a meal event's meals can be picked up
+func (s *mealPickupService) pickupDeadline(meal *model.MealEvent) time.Time {
+	return meal.EventDate.Add(time.Duration(meal.EventDuration)*time.Minute + s.noShowGrace)
+}
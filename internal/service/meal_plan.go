@@ -0,0 +1,496 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// MaxMealPlanDays limits how far ahead a meal plan looks
+const MaxMealPlanDays = 31
+
+// maxMealPlanEntries limits how many meal events one batch may plan
+const maxMealPlanEntries = 200
+
+// MealPlanMode decides how a meal plan batch is applied
+type MealPlanMode string
+
+const (
+	// MealPlanPerEvent applies every valid entry, whatever happens to the others
+	MealPlanPerEvent MealPlanMode = "per_event"
+	// MealPlanAtomic applies all entries or none
+	MealPlanAtomic MealPlanMode = "atomic"
+)
+
+// PlannedMenuSet is a menu set offered by a planned meal event
+type PlannedMenuSet struct {
+	MenuSetID uint             `json:"menu_set_id"`
+	Name      string           `json:"name"`
+	Label     string           `json:"label,omitempty"`
+	Items     []model.MenuItem `json:"items"`
+}
+
+// PlannedAddress is a location where a planned meal event is served
+type PlannedAddress struct {
+	AddressID uint   `json:"address_id"`
+	Name      string `json:"name"`
+}
+
+// PlannedEvent is an upcoming meal event with the user's current selection
+type PlannedEvent struct {
+	MealEventID uint                  `json:"meal_event_id"`
+	Name        string                `json:"name"`
+	MealType    model.MealType        `json:"meal_type"`
+	EventDate   time.Time             `json:"event_date"`
+	CutoffTime  time.Time             `json:"cutoff_time"`
+	Status      model.MealEventStatus `json:"status"`
+	Open        bool                  `json:"open"` // requests can still be created or changed
+	MenuSets    []PlannedMenuSet      `json:"menu_sets"`
+	Addresses   []PlannedAddress      `json:"addresses"`
+	Request     *model.MealRequest    `json:"request"` // the user's pending or approved request, nil if none
+}
+
+// MealPlan lists the upcoming meal events of a user within a date range
+type MealPlan struct {
+	StartDate time.Time      `json:"start_date"`
+	EndDate   time.Time      `json:"end_date"`
+	Events    []PlannedEvent `json:"events"`
+}
+
+// MealPlanItem represents a menu item chosen in a meal plan entry
+type MealPlanItem struct {
+	MenuItemID uint   `json:"menu_item_id" example:"7"`
+	IsSelected *bool  `json:"is_selected"` // defaults to true
+	Quantity   int    `json:"quantity" example:"1"`
+	Notes      string `json:"notes"`
+}
+
+// MealPlanEntry chooses a menu set, items and address for one meal event, or withdraws from it
+type MealPlanEntry struct {
+	MealEventID    uint           `json:"meal_event_id" example:"12"`
+	MenuSetID      uint           `json:"menu_set_id" example:"2"`
+	EventAddressID uint           `json:"event_address_id" example:"1"`
+	Items          []MealPlanItem `json:"items"` // defaults to every item of the menu set
+	Skip           bool           `json:"skip"`  // withdraws the user's request for the event
+}
+
+// MealPlanBatch is a set of meal plan entries submitted at once
+type MealPlanBatch struct {
+	Mode    MealPlanMode    `json:"mode" enums:"per_event,atomic"` // defaults to per_event
+	Entries []MealPlanEntry `json:"entries"`
+}
+
+// MealPlanOutcome reports what happened to one meal plan entry
+type MealPlanOutcome struct {
+	MealEventID uint                `json:"meal_event_id"`
+	RequestID   uint                `json:"request_id,omitempty"`
+	Result      string              `json:"result" enums:"created,updated,cancelled,unchanged,failed,not_applied"`
+	Error       string              `json:"error,omitempty"`
+	Fields      []errors.FieldError `json:"fields,omitempty"`
+}
+
+// MealPlanResult summarizes a meal plan batch. In atomic mode nothing is applied
+// unless every entry succeeds; the other entries are then reported as not_applied.
+type MealPlanResult struct {
+	Mode      MealPlanMode      `json:"mode"`
+	Applied   bool              `json:"applied"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Outcomes  []MealPlanOutcome `json:"outcomes"`
+}
+
+// plannedChange is a validated meal plan entry ready to be applied
+type plannedChange struct {
+	entry            *MealPlanEntry
+	meal             *model.MealEvent
+	existing         *model.MealRequest
+	items            []model.MealRequestItem
+	awaitingApproval bool                // a new request waits for the line manager
+	withdrawnFrom    model.RequestStatus // status of the existing request before it was withdrawn
+}
+
+// mealPlanService lets employees plan their meals for the days ahead in one go
+type mealPlanService struct {
+	mealRepo     repository.MealEventRepository
+	requestRepo  repository.MealRequestRepository
+	guestRepo    repository.GuestRequestRepository
+	menuRepo     repository.MenuSetRepository
+	policyRepo   repository.NoShowPolicyRepository
+	userRepo     repository.UserRepository
+	notifService NotificationService
+	calendar     CalendarService
+	timeZone     string
+}
+
+// NewMealPlanService creates a new instance of MealPlanService
+func NewMealPlanService(
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	guestRepo repository.GuestRequestRepository,
+	menuRepo repository.MenuSetRepository,
+	policyRepo repository.NoShowPolicyRepository,
+	userRepo repository.UserRepository,
+	notifService NotificationService,
+	calendar CalendarService,
+	timeZone string,
+) MealPlanService {
+	return &mealPlanService{
+		mealRepo:     mealRepo,
+		requestRepo:  requestRepo,
+		guestRepo:    guestRepo,
+		menuRepo:     menuRepo,
+		policyRepo:   policyRepo,
+		userRepo:     userRepo,
+		notifService: notifService,
+		calendar:     calendar,
+		timeZone:     timeZone,
+	}
+}
+
+// GetMealPlan retrieves the upcoming meal events within a date range with the user's current selections
+func (s *mealPlanService) GetMealPlan(ctx context.Context, userID uint, startDate, endDate time.Time) (*MealPlan, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.NewValidationError("end date must not be before start date", nil)
+	}
+
+	meals, err := s.mealRepo.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal events", err)
+	}
+
+	requests, err := s.requestRepo.FindByUserIDAndEventDateRange(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	selected := make(map[uint]*model.MealRequest, len(requests))
+	for i := range requests {
+		if isOpenRequest(&requests[i]) {
+			selected[requests[i].MealEventID] = &requests[i]
+		}
+	}
+
+	plan := &MealPlan{StartDate: startDate, EndDate: endDate, Events: []PlannedEvent{}}
+	setItems := make(map[uint][]model.MenuItem)
+	now := time.Now()
+	for i := range meals {
+		meal := &meals[i]
+		if !meal.IsActive || meal.Status == model.MealEventStatusDraft || meal.Status == model.MealEventStatusCancelled {
+			continue
+		}
+
+		event := PlannedEvent{
+			MealEventID: meal.ID,
+			Name:        meal.Name,
+			MealType:    meal.MealType,
+			EventDate:   meal.EventDate,
+			CutoffTime:  meal.CutoffTime,
+			Status:      meal.Status,
+			Open:        meal.Status.AcceptsRequests() && now.Before(meal.CutoffTime),
+			MenuSets:    make([]PlannedMenuSet, 0, len(meal.MenuSets)),
+			Addresses:   make([]PlannedAddress, 0, len(meal.Addresses)),
+		}
+		for _, set := range meal.MenuSets {
+			items, ok := setItems[set.MenuSetID]
+			if !ok {
+				if items, err = s.menuRepo.FindMenuItems(ctx, set.MenuSetID); err != nil {
+					return nil, errors.NewInternalError("failed to fetch menu items", err)
+				}
+				setItems[set.MenuSetID] = items
+			}
+			event.MenuSets = append(event.MenuSets, PlannedMenuSet{
+				MenuSetID: set.MenuSetID,
+				Name:      set.MenuSet.MenuSetName,
+				Label:     set.Label,
+				Items:     items,
+			})
+		}
+		for _, address := range meal.Addresses {
+			event.Addresses = append(event.Addresses, PlannedAddress{
+				AddressID: address.AddressID,
+				Name:      addressName(meal, address.AddressID),
+			})
+		}
+
+		if request, ok := selected[meal.ID]; ok {
+			items, err := s.requestRepo.FindRequestItems(ctx, request.ID)
+			if err != nil {
+				return nil, errors.NewInternalError("failed to fetch meal request items", err)
+			}
+			request.RequestItems = items
+			event.Request = request
+		}
+		plan.Events = append(plan.Events, event)
+	}
+	return plan, nil
+}
+
+// ApplyMealPlan validates every entry of a batch against its meal event and applies the valid ones,
+// or in atomic mode all of them or none
+func (s *mealPlanService) ApplyMealPlan(ctx context.Context, batch *MealPlanBatch, userID uint) (*MealPlanResult, error) {
+	if batch == nil || len(batch.Entries) == 0 {
+		return nil, errors.NewValidationError("no meal plan entries given", nil)
+	}
+	mode := batch.Mode
+	if mode == "" {
+		mode = MealPlanPerEvent
+	}
+	if mode != MealPlanPerEvent && mode != MealPlanAtomic {
+		return nil, errors.NewValidationError("invalid meal plan mode", nil).
+			WithFields(errors.FieldError{Field: "mode", Message: "mode must be per_event or atomic"})
+	}
+	if len(batch.Entries) > maxMealPlanEntries {
+		return nil, errors.NewValidationError(fmt.Sprintf("at most %d meal events can be planned at once", maxMealPlanEntries), nil)
+	}
+
+	result := &MealPlanResult{Mode: mode, Outcomes: make([]MealPlanOutcome, len(batch.Entries))}
+	changes := make([]*plannedChange, len(batch.Entries))
+	seen := make(map[uint]bool, len(batch.Entries))
+	for i := range batch.Entries {
+		entry := &batch.Entries[i]
+		result.Outcomes[i].MealEventID = entry.MealEventID
+		if seen[entry.MealEventID] {
+			result.Outcomes[i].Result, result.Outcomes[i].Error = "failed", "meal event is planned more than once"
+			continue
+		}
+		seen[entry.MealEventID] = true
+
+		change, err := s.validateEntry(ctx, entry, userID)
+		if err != nil {
+			recordPlanFailure(&result.Outcomes[i], err)
+			continue
+		}
+		changes[i] = change
+	}
+
+	// After repeated no-shows, new requests wait for the line manager
+	restricted, err := isRestricted(ctx, s.policyRepo, userID, model.NoShowActionRequireApproval, time.Now())
+	if err != nil {
+		return nil, errors.NewInternalError("failed to check no-show restrictions", err)
+	}
+	for _, change := range changes {
+		if change != nil {
+			change.awaitingApproval = restricted
+		}
+	}
+
+	if mode == MealPlanPerEvent {
+		for i, change := range changes {
+			if change == nil {
+				continue
+			}
+			if err := s.applyChange(ctx, s.requestRepo, change, userID, &result.Outcomes[i]); err != nil {
+				recordPlanFailure(&result.Outcomes[i], err)
+			}
+		}
+		s.announceChanges(ctx, changes, result, userID)
+		result.count()
+		result.Applied = result.Succeeded > 0
+		return result, nil
+	}
+
+	for _, change := range changes {
+		if change == nil {
+			result.rejectAtomic()
+			return result, nil
+		}
+	}
+	failed := -1
+	err = s.requestRepo.Transaction(ctx, func(repo repository.MealRequestRepository) error {
+		for i, change := range changes {
+			if err := s.applyChange(ctx, repo, change, userID, &result.Outcomes[i]); err != nil {
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if failed < 0 {
+			return nil, errors.NewInternalError("failed to apply meal plan", err)
+		}
+		recordPlanFailure(&result.Outcomes[failed], err)
+		result.rejectAtomic()
+		return result, nil
+	}
+	s.announceChanges(ctx, changes, result, userID)
+	result.count()
+	result.Applied = true
+	return result, nil
+}
+
+// announceChanges tells others about the applied plan: the line manager about requests created while
+// the user is held for approval after repeated no-shows, and calendars about withdrawn meals
+func (s *mealPlanService) announceChanges(ctx context.Context, changes []*plannedChange, result *MealPlanResult, userID uint) {
+	for i, change := range changes {
+		if change == nil {
+			continue
+		}
+		switch outcome := result.Outcomes[i]; outcome.Result {
+		case "created":
+			if change.awaitingApproval {
+				requestApproval(ctx, s.userRepo, s.notifService, change.meal, userID, s.timeZone)
+			}
+		case "cancelled":
+			s.calendar.SendRequestWithdrawal(ctx, outcome.RequestID, change.withdrawnFrom, "withdrawn in the meal plan")
+		}
+	}
+}
+
+// validateEntry checks one meal plan entry against its meal event and the user's current request
+func (s *mealPlanService) validateEntry(ctx context.Context, entry *MealPlanEntry, userID uint) (*plannedChange, error) {
+	meal, err := s.mealRepo.FindByID(ctx, entry.MealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	if err := checkRequestEditable(meal, time.Now()); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	change := &plannedChange{entry: entry, meal: meal}
+	for i := range requests {
+		if requests[i].UserID == userID && isOpenRequest(&requests[i]) {
+			change.existing = &requests[i]
+		}
+	}
+	if entry.Skip {
+		return change, nil
+	}
+
+	if fields := validateRequestChoice(meal, entry.MenuSetID, entry.EventAddressID); len(fields) > 0 {
+		return nil, errors.NewValidationError("invalid meal plan entry", nil).WithFields(fields...)
+	}
+
+	if change.existing == nil || change.existing.EventAddressID != entry.EventAddressID {
+		guests, err := s.guestRepo.FindByMealEventID(ctx, meal.ID)
+		if err != nil {
+			return nil, errors.NewInternalError("failed to fetch guests", err)
+		}
+		if isFull(meal, entry.EventAddressID, requests, guests) {
+			return nil, errors.NewConflictError("no seats are left at this location", nil)
+		}
+	}
+
+	if change.items, err = s.planItems(ctx, entry, userID); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// planItems turns the items of an entry into request items, defaulting to the whole menu set
+func (s *mealPlanService) planItems(ctx context.Context, entry *MealPlanEntry, userID uint) ([]model.MealRequestItem, error) {
+	menuItems, err := s.menuRepo.FindMenuItems(ctx, entry.MenuSetID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch menu items", err)
+	}
+
+	chosen := entry.Items
+	if len(chosen) == 0 {
+		for _, menuItem := range menuItems {
+			chosen = append(chosen, MealPlanItem{MenuItemID: menuItem.ID})
+		}
+	}
+
+	items := make([]model.MealRequestItem, 0, len(chosen))
+	for _, item := range chosen {
+		items = append(items, model.MealRequestItem{
+			MenuItemID: item.MenuItemID,
+			MenuSetID:  entry.MenuSetID,
+			IsSelected: item.IsSelected == nil || *item.IsSelected,
+			Quantity:   item.Quantity,
+			Notes:      item.Notes,
+			CreatedBy:  userID,
+			UpdatedBy:  userID,
+		})
+	}
+	if fields := validateRequestItems(entry.MenuSetID, menuItemsByID(menuItems), items); len(fields) > 0 {
+		return nil, errors.NewValidationError("invalid meal plan items", nil).WithFields(fields...)
+	}
+	return items, nil
+}
+
+// applyChange stores one validated meal plan entry through the given repository
+func (s *mealPlanService) applyChange(ctx context.Context, repo repository.MealRequestRepository, change *plannedChange, userID uint, outcome *MealPlanOutcome) error {
+	request := change.existing
+	if change.entry.Skip {
+		if request == nil {
+			outcome.Result = "unchanged"
+			return nil
+		}
+		change.withdrawnFrom = request.Status
+		if err := transitionRequest(ctx, repo, request, model.RequestStatusCancelled, "withdrawn in the meal plan", &userID); err != nil {
+			return err
+		}
+		outcome.RequestID, outcome.Result = request.ID, "cancelled"
+		return nil
+	}
+
+	outcome.Result = "updated"
+	if request == nil {
+		request = &model.MealRequest{
+			UserID:           userID,
+			MealEventID:      change.meal.ID,
+			Status:           model.RequestStatusPending,
+			AwaitingApproval: change.awaitingApproval,
+			CreatedBy:        userID,
+		}
+		outcome.Result = "created"
+	}
+	request.MenuSetID = change.entry.MenuSetID
+	request.EventAddressID = change.entry.EventAddressID
+	request.NeedsAttention = false
+	request.AttentionNote = ""
+	request.Sequence++
+	request.UpdatedBy = userID
+
+	if err := repo.SaveWithItems(ctx, request, change.items); err != nil {
+		return versionError("meal request", "save", err)
+	}
+	outcome.RequestID = request.ID
+	return nil
+}
+
+// count tallies the outcomes of a meal plan batch
+func (r *MealPlanResult) count() {
+	r.Succeeded, r.Failed = 0, 0
+	for _, outcome := range r.Outcomes {
+		if outcome.Result == "failed" {
+			r.Failed++
+		} else {
+			r.Succeeded++
+		}
+	}
+}
+
+// rejectAtomic reports an atomic batch that was not applied because an entry failed
+func (r *MealPlanResult) rejectAtomic() {
+	for i := range r.Outcomes {
+		if r.Outcomes[i].Result != "failed" {
+			r.Outcomes[i] = MealPlanOutcome{MealEventID: r.Outcomes[i].MealEventID, Result: "not_applied"}
+		}
+	}
+	r.count()
+	r.Succeeded = 0
+	r.Applied = false
+}
+
+// recordPlanFailure records why a meal plan entry failed
+func recordPlanFailure(outcome *MealPlanOutcome, err error) {
+	outcome.RequestID = 0
+	outcome.Result, outcome.Error = "failed", err.Error()
+	if appErr, ok := err.(*errors.AppError); ok {
+		outcome.Error, outcome.Fields = appErr.Message, appErr.Fields
+	}
+}
+
+// isOpenRequest reports whether a meal request is pending or approved and can still be changed
+func isOpenRequest(request *model.MealRequest) bool {
+	return request.Status == model.RequestStatusPending || request.Status == model.RequestStatusApproved
+}
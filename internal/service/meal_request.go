@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/arafat-hasan/mealsync/internal/errors"
@@ -11,9 +12,15 @@ import (
 
 // mealRequestService handles business logic for meal request operations
 type mealRequestService struct {
-	requestRepo repository.MealRequestRepository
-	mealRepo    repository.MealEventRepository
-	userRepo    repository.UserRepository
+	requestRepo  repository.MealRequestRepository
+	mealRepo     repository.MealEventRepository
+	userRepo     repository.UserRepository
+	guestRepo    repository.GuestRequestRepository
+	menuRepo     repository.MenuSetRepository
+	policyRepo   repository.NoShowPolicyRepository
+	notifService NotificationService
+	calendar     CalendarService
+	timeZone     string
 }
 
 // NewMealRequestService creates a new instance of MealRequestService
@@ -21,11 +28,23 @@ func NewMealRequestService(
 	requestRepo repository.MealRequestRepository,
 	mealRepo repository.MealEventRepository,
 	userRepo repository.UserRepository,
+	guestRepo repository.GuestRequestRepository,
+	menuRepo repository.MenuSetRepository,
+	policyRepo repository.NoShowPolicyRepository,
+	notifService NotificationService,
+	calendar CalendarService,
+	timeZone string,
 ) MealRequestService {
 	return &mealRequestService{
-		requestRepo: requestRepo,
-		mealRepo:    mealRepo,
-		userRepo:    userRepo,
+		requestRepo:  requestRepo,
+		mealRepo:     mealRepo,
+		userRepo:     userRepo,
+		guestRepo:    guestRepo,
+		menuRepo:     menuRepo,
+		policyRepo:   policyRepo,
+		notifService: notifService,
+		calendar:     calendar,
+		timeZone:     timeZone,
 	}
 }
 
@@ -53,6 +72,11 @@ func (s *mealRequestService) GetMealRequestByID(ctx context.Context, id uint, us
 
 // CreateMealRequest creates a new meal request
 func (s *mealRequestService) CreateMealRequest(ctx context.Context, request *model.MealRequest, userID uint) error {
+	return s.CreateMealRequestFor(ctx, request, userID, userID)
+}
+
+// CreateMealRequestFor creates a new meal request for a user, placed by the actor
+func (s *mealRequestService) CreateMealRequestFor(ctx context.Context, request *model.MealRequest, userID uint, actorID uint) error {
 	if request == nil {
 		return errors.NewValidationError("request cannot be nil", nil)
 	}
@@ -63,13 +87,26 @@ func (s *mealRequestService) CreateMealRequest(ctx context.Context, request *mod
 		return err
 	}
 
-	if !meal.IsActive {
-		return errors.NewValidationError("meal event is not active", nil)
+	if err := checkRequestEditable(meal, time.Now()); err != nil {
+		return err
 	}
 
-	// Check if cutoff time has passed
-	if time.Now().After(meal.CutoffTime) {
-		return errors.NewValidationError("cutoff time has passed", nil)
+	// The chosen menu set, address and items must belong to the event
+	if fields := validateRequestChoice(meal, request.MenuSetID, request.EventAddressID); len(fields) > 0 {
+		return errors.NewValidationError("invalid meal request", nil).WithFields(fields...)
+	}
+	if len(request.RequestItems) > 0 {
+		setItems, err := s.setItems(ctx, request.MenuSetID)
+		if err != nil {
+			return err
+		}
+		if fields := validateRequestItems(request.MenuSetID, setItems, request.RequestItems); len(fields) > 0 {
+			return errors.NewValidationError("invalid meal request items", nil).WithFields(fields...)
+		}
+		for i := range request.RequestItems {
+			request.RequestItems[i].CreatedBy = actorID
+			request.RequestItems[i].UpdatedBy = actorID
+		}
 	}
 
 	// Check if user already has a request for this meal event
@@ -79,21 +116,61 @@ func (s *mealRequestService) CreateMealRequest(ctx context.Context, request *mod
 	}
 
 	for _, existingRequest := range existingRequests {
-		if existingRequest.UserID == userID {
+		if existingRequest.UserID == userID && existingRequest.Status.IsActive() {
 			return errors.NewValidationError("user already has a request for this meal event", nil)
 		}
 	}
 
+	// Guests take seats too
+	guests, err := s.guestRepo.FindByMealEventID(ctx, request.MealEventID)
+	if err != nil {
+		return err
+	}
+	if isFull(meal, request.EventAddressID, existingRequests, guests) {
+		return errors.NewConflictError("no seats are left at this location", nil)
+	}
+
+	// After repeated no-shows, requests wait for the line manager unless the manager placed them
+	request.AwaitingApproval = false
+	if actorID == userID {
+		restricted, err := isRestricted(ctx, s.policyRepo, userID, model.NoShowActionRequireApproval, time.Now())
+		if err != nil {
+			return errors.NewInternalError("failed to check no-show restrictions", err)
+		}
+		request.AwaitingApproval = restricted
+	}
+
 	// Set request fields
+	request.Status = model.RequestStatusPending
+	request.ConfirmedAt = nil
 	request.UserID = userID
-	request.CreatedBy = userID
-	request.UpdatedBy = userID
+	request.CreatedBy = actorID
+	request.UpdatedBy = actorID
+
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return err
+	}
+	request.Warnings = s.dietaryWarnings(ctx, userID, request.MenuSetID, request.RequestItems)
+	if request.AwaitingApproval {
+		requestApproval(ctx, s.userRepo, s.notifService, meal, userID, s.timeZone)
+	}
+	return nil
+}
 
-	return s.requestRepo.Create(ctx, request)
+// requestApproval asks the line manager of a user to approve their request held after repeated no-shows
+func requestApproval(ctx context.Context, userRepo repository.UserRepository, notifService NotificationService, meal *model.MealEvent, userID uint, timeZone string) {
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil || user.ManagerID == nil {
+		return
+	}
+	loc := mealLocation(meal, timeZone)
+	message := fmt.Sprintf("%s requested %s on %s. Please approve or withdraw the request before %s.",
+		user.Name, meal.Name, formatMealTime(meal.EventDate, loc), formatMealTime(meal.CutoffTime, loc))
+	_ = notifService.CreateAdminNotification(ctx, *user.ManagerID, message, "normal")
 }
 
-// UpdateMealRequest updates an existing meal request
-func (s *mealRequestService) UpdateMealRequest(ctx context.Context, id uint, request *model.MealRequest, userID uint, isAdmin bool) error {
+// UpdateMealRequest updates an existing meal request if it is still at the version the client read
+func (s *mealRequestService) UpdateMealRequest(ctx context.Context, id uint, version int, request *model.MealRequest, userID uint, isAdmin bool) error {
 	if request == nil {
 		return errors.NewValidationError("request cannot be nil", nil)
 	}
@@ -108,31 +185,84 @@ func (s *mealRequestService) UpdateMealRequest(ctx context.Context, id uint, req
 		return errors.NewForbiddenError("unauthorized to update this request", nil)
 	}
 
+	if err := checkVersion("meal request", existingRequest.Version, version); err != nil {
+		return err
+	}
+
+	if err := checkRequestOpen(existingRequest); err != nil {
+		return err
+	}
+
 	// Validate meal event exists and is active
 	meal, err := s.mealRepo.FindByID(ctx, existingRequest.MealEventID)
 	if err != nil {
 		return err
 	}
 
-	if !meal.IsActive {
-		return errors.NewValidationError("meal event is not active", nil)
+	if err := checkRequestEditable(meal, time.Now()); err != nil {
+		return err
 	}
 
-	// Check if cutoff time has passed
-	if time.Now().After(meal.CutoffTime) {
-		return errors.NewValidationError("cutoff time has passed", nil)
+	if fields := validateRequestChoice(meal, request.MenuSetID, request.EventAddressID); len(fields) > 0 {
+		return errors.NewValidationError("invalid meal request", nil).WithFields(fields...)
+	}
+
+	// Moving to another location needs a free seat there
+	if request.EventAddressID != existingRequest.EventAddressID {
+		requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+		if err != nil {
+			return err
+		}
+		guests, err := s.guestRepo.FindByMealEventID(ctx, meal.ID)
+		if err != nil {
+			return err
+		}
+		if isFull(meal, request.EventAddressID, requests, guests) {
+			return errors.NewConflictError("no seats are left at this location", nil)
+		}
 	}
 
+	// Items of a menu set that is no longer chosen are dropped
+	setChanged := existingRequest.MenuSetID != request.MenuSetID
+
 	// Update fields
 	existingRequest.MenuSetID = request.MenuSetID
 	existingRequest.EventAddressID = request.EventAddressID
+	existingRequest.NeedsAttention = false
+	existingRequest.AttentionNote = ""
+	existingRequest.Sequence++
 	existingRequest.UpdatedBy = userID
 
-	return s.requestRepo.Update(ctx, existingRequest)
+	items, err := s.requestRepo.FindRequestItems(ctx, existingRequest.ID)
+	if err != nil {
+		return err
+	}
+	if !setChanged {
+		if err := s.requestRepo.Update(ctx, existingRequest); err != nil {
+			return versionError("meal request", "update", err)
+		}
+		*request = *existingRequest
+		request.RequestItems = items
+		request.Warnings = s.dietaryWarnings(ctx, existingRequest.UserID, existingRequest.MenuSetID, items)
+		return nil
+	}
+	kept := make([]model.MealRequestItem, 0, len(items))
+	for _, item := range items {
+		if item.MenuSetID == existingRequest.MenuSetID {
+			kept = append(kept, item)
+		}
+	}
+	if err := s.requestRepo.SaveWithItems(ctx, existingRequest, kept); err != nil {
+		return versionError("meal request", "update", err)
+	}
+	*request = *existingRequest
+	request.Warnings = s.dietaryWarnings(ctx, existingRequest.UserID, existingRequest.MenuSetID, kept)
+	return nil
 }
 
-// DeleteMealRequest soft deletes a meal request
-func (s *mealRequestService) DeleteMealRequest(ctx context.Context, id uint, userID uint, isAdmin bool) error {
+// DeleteMealRequest cancels a meal request that is still at the version the client read.
+// The request is kept so that its history stays available.
+func (s *mealRequestService) DeleteMealRequest(ctx context.Context, id uint, version int, userID uint, isAdmin bool) error {
 	request, err := s.requestRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
@@ -143,18 +273,28 @@ func (s *mealRequestService) DeleteMealRequest(ctx context.Context, id uint, use
 		return errors.NewForbiddenError("unauthorized to delete this request", nil)
 	}
 
+	if err := checkVersion("meal request", request.Version, version); err != nil {
+		return err
+	}
+
 	// Check if cutoff time has passed
 	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
 	if err != nil {
 		return err
 	}
 
-	if time.Now().After(meal.CutoffTime) {
+	// Late requests can be withdrawn until the kitchen decides on them
+	if time.Now().After(meal.CutoffTime) && !isUndecidedLate(request) {
 		return errors.NewValidationError("cutoff time has passed", nil)
 	}
 
-	request.UpdatedBy = userID
-	return s.requestRepo.Delete(ctx, request)
+	from := request.Status
+	reason := "cancelled by the requester"
+	if err := transitionRequest(ctx, s.requestRepo, request, model.RequestStatusCancelled, reason, &userID); err != nil {
+		return err
+	}
+	s.calendar.SendRequestWithdrawal(ctx, request.ID, from, reason)
+	return nil
 }
 
 // AddRequestItem adds an item to a meal request
@@ -173,7 +313,31 @@ func (s *mealRequestService) AddRequestItem(ctx context.Context, requestID uint,
 		return errors.NewForbiddenError("unauthorized to modify this request", nil)
 	}
 
+	if err := s.checkItemsEditable(ctx, request); err != nil {
+		return err
+	}
+
+	setItems, err := s.setItems(ctx, request.MenuSetID)
+	if err != nil {
+		return err
+	}
+	if fields := validateRequestItem(request.MenuSetID, setItems, item, ""); len(fields) > 0 {
+		return errors.NewValidationError("invalid meal request item", nil).WithFields(fields...)
+	}
+
+	items, err := s.requestRepo.FindRequestItems(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range items {
+		if existing.MenuItemID == item.MenuItemID {
+			return errors.NewConflictError("menu item is already part of this request", nil).
+				WithFields(errors.FieldError{Field: "menu_item_id", Message: "menu item is already part of this request"})
+		}
+	}
+
 	// Set item fields
+	item.ID = 0
 	item.MealRequestID = requestID
 	item.CreatedBy = userID
 	item.UpdatedBy = userID
@@ -193,6 +357,10 @@ func (s *mealRequestService) RemoveRequestItem(ctx context.Context, requestID ui
 		return errors.NewForbiddenError("unauthorized to modify this request", nil)
 	}
 
+	if err := s.checkItemsEditable(ctx, request); err != nil {
+		return err
+	}
+
 	items, err := s.requestRepo.FindRequestItems(ctx, requestID)
 	if err != nil {
 		return err
@@ -229,17 +397,151 @@ func (s *mealRequestService) GetRequestItems(ctx context.Context, requestID uint
 	return s.requestRepo.FindRequestItems(ctx, requestID)
 }
 
-// UpdateRequestStatus updates the status of a meal request
-func (s *mealRequestService) UpdateRequestStatus(ctx context.Context, requestID uint, status model.RequestStatus, userID uint) error {
-	// Verify user exists and is admin
-	user, err := s.userRepo.FindByID(ctx, userID)
+// UpdateRequestStatus moves a meal request to a new status and records who did it and why.
+// Employees may only cancel their own requests before the cutoff; admins may make any allowed change.
+// The request must still be at the version the client read.
+func (s *mealRequestService) UpdateRequestStatus(ctx context.Context, requestID uint, version int, status model.RequestStatus, reason string, userID uint, isAdmin bool) (*model.MealRequest, error) {
+	if !status.IsValid() {
+		return nil, errors.NewValidationError("invalid meal request status", nil)
+	}
+
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if err := checkVersion("meal request", request.Version, version); err != nil {
+		return nil, err
+	}
+
+	if !isAdmin {
+		if request.UserID != userID {
+			return nil, errors.NewForbiddenError("unauthorized to update this request", nil)
+		}
+		if status != model.RequestStatusCancelled {
+			return nil, errors.NewForbiddenError("only administrators can approve, reject or complete meal requests", nil)
+		}
+
+		meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+		if err != nil {
+			return nil, errors.NewNotFoundError("meal event not found", err)
+		}
+		if time.Now().After(meal.CutoffTime) && !isUndecidedLate(request) {
+			return nil, errors.NewValidationError("cutoff time has passed", nil)
+		}
+	}
+
+	// Late requests are only approved while surplus is left, checked under a lock on the meal event
+	from := request.Status
+	err = s.requestRepo.Transaction(ctx, func(repo repository.MealRequestRepository) error {
+		if isUndecidedLate(request) && status == model.RequestStatusApproved {
+			meal, err := repo.LockMealEvent(ctx, request.MealEventID)
+			if err != nil {
+				return errors.NewNotFoundError("meal event not found", err)
+			}
+			if err := checkLateApproval(ctx, repo, meal, request); err != nil {
+				return err
+			}
+		}
+		return transitionRequest(ctx, repo, request, status, reason, &userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.calendar.SendRequestWithdrawal(ctx, request.ID, from, reason)
+	return request, nil
+}
+
+// GetRequestHistory retrieves the status history of a meal request
+func (s *mealRequestService) GetRequestHistory(ctx context.Context, requestID uint, userID uint, isAdmin bool) ([]model.MealRequestTransition, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
 	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+
+	if !isAdmin && request.UserID != userID {
+		return nil, errors.NewForbiddenError("unauthorized to view this request", nil)
+	}
+
+	return s.requestRepo.FindTransitions(ctx, requestID)
+}
+
+// checkItemsEditable reports why the items of a request can no longer change
+func (s *mealRequestService) checkItemsEditable(ctx context.Context, request *model.MealRequest) error {
+	if err := checkRequestOpen(request); err != nil {
 		return err
 	}
 
-	if user.Role != model.UserRoleAdmin {
-		return errors.NewForbiddenError("unauthorized to update request status", nil)
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+	return checkRequestEditable(meal, time.Now())
+}
+
+// setItems loads the items of a menu set, indexed by ID
+func (s *mealRequestService) setItems(ctx context.Context, menuSetID uint) (map[uint]model.MenuItem, error) {
+	items, err := s.menuRepo.FindMenuItems(ctx, menuSetID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch menu items", err)
 	}
+	return menuItemsByID(items), nil
+}
 
-	return s.requestRepo.UpdateRequestStatus(ctx, requestID, status)
+// dietaryWarnings describes how the chosen items, or the whole menu set when none were chosen, clash
+// with the dietary profile of the requester. Warnings are advisory, so lookup failures yield none.
+func (s *mealRequestService) dietaryWarnings(ctx context.Context, userID uint, menuSetID uint, requestItems []model.MealRequestItem) []string {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || (len(user.Allergens) == 0 && len(user.DietaryTags) == 0) {
+		return nil
+	}
+	items, err := s.menuRepo.FindMenuItems(ctx, menuSetID)
+	if err != nil {
+		return nil
+	}
+	if len(requestItems) > 0 {
+		byID := menuItemsByID(items)
+		items = items[:0]
+		for _, requestItem := range requestItems {
+			if item, ok := byID[requestItem.MenuItemID]; ok {
+				items = append(items, item)
+			}
+		}
+	}
+	return dietaryWarnings(user, items)
+}
+
+// transitionRequest validates and stores a status change of a meal request.
+// A nil actor means the change was made by the system.
+func transitionRequest(ctx context.Context, requestRepo repository.MealRequestRepository, request *model.MealRequest, to model.RequestStatus, reason string, actorID *uint) error {
+	from := request.Status
+	if !from.CanTransitionTo(to) {
+		return errors.NewValidationError(fmt.Sprintf("cannot change meal request status from %s to %s", from, to), nil)
+	}
+
+	request.Status = to
+	// Calendar clients only apply updates with a higher sequence
+	request.Sequence++
+	if to == model.RequestStatusApproved {
+		now := time.Now()
+		request.ConfirmedAt = &now
+		request.AwaitingApproval = false
+	}
+	if actorID != nil {
+		request.UpdatedBy = *actorID
+	}
+
+	transition := &model.MealRequestTransition{
+		MealRequestID: request.ID,
+		FromStatus:    from,
+		ToStatus:      to,
+		Reason:        reason,
+		ActorID:       actorID,
+	}
+	if err := requestRepo.UpdateRequestStatus(ctx, request, from, transition); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return errors.NewConflictError("meal request status was changed by someone else", err)
+		}
+		return errors.NewInternalError("failed to update meal request status", err)
+	}
+	return nil
 }
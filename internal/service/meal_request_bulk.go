@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+)
+
+// maxBulkRequestUsers limits how many employees one bulk operation may act for
+const maxBulkRequestUsers = 2000
+
+// BulkRequestTargets selects the employees an admin acts for. All given selections are combined.
+type BulkRequestTargets struct {
+	UserIDs     []uint   `json:"user_ids" example:"3,4"`
+	Department  string   `json:"department" example:"Engineering"`
+	EmployeeIDs []string `json:"employee_ids" example:"1001,1002"`
+}
+
+// BulkRequestItem represents a menu item chosen for every assigned request
+type BulkRequestItem struct {
+	MenuItemID uint   `json:"menu_item_id" example:"7"`
+	Quantity   int    `json:"quantity" example:"1"`
+	Notes      string `json:"notes"`
+}
+
+// BulkRequestAssignment creates or changes the meal requests of many employees for one meal event
+type BulkRequestAssignment struct {
+	BulkRequestTargets
+	MealEventID    uint              `json:"meal_event_id" example:"12"`
+	MenuSetID      uint              `json:"menu_set_id" example:"2"`
+	EventAddressID uint              `json:"event_address_id" example:"1"`
+	Items          []BulkRequestItem `json:"items"`
+	Reason         string            `json:"reason" example:"Team offsite lunch"`
+}
+
+// BulkRequestCancellation cancels the meal requests of many employees for one meal event
+type BulkRequestCancellation struct {
+	BulkRequestTargets
+	MealEventID uint   `json:"meal_event_id" example:"12"`
+	Reason      string `json:"reason" example:"Team is travelling"`
+}
+
+// BulkRequestOutcome reports what happened for one employee
+type BulkRequestOutcome struct {
+	UserID     uint   `json:"user_id,omitempty"`
+	EmployeeID string `json:"employee_id,omitempty"`
+	RequestID  uint   `json:"request_id,omitempty"`
+	Result     string `json:"result" enums:"created,updated,cancelled,failed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BulkRequestResult summarizes a bulk operation; one employee failing does not stop the others
+type BulkRequestResult struct {
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Outcomes  []BulkRequestOutcome `json:"outcomes"`
+}
+
+// add records the outcome for one employee
+func (r *BulkRequestResult) add(outcome BulkRequestOutcome) {
+	if outcome.Result == "failed" {
+		r.Failed++
+	} else {
+		r.Succeeded++
+	}
+	r.Outcomes = append(r.Outcomes, outcome)
+}
+
+// AssignMealRequests creates or changes the requests of the selected employees for a meal event.
+// Unlike employees, admins may do this after the cutoff.
+func (s *mealRequestService) AssignMealRequests(ctx context.Context, assignment *BulkRequestAssignment, adminID uint) (*BulkRequestResult, error) {
+	meal, err := s.bulkMealEvent(ctx, assignment.MealEventID, assignment.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	setItems, err := s.setItems(ctx, assignment.MenuSetID)
+	if err != nil {
+		return nil, err
+	}
+	if fields := validateAssignment(meal, setItems, assignment); len(fields) > 0 {
+		return nil, errors.NewValidationError("invalid meal request assignment", nil).WithFields(fields...)
+	}
+
+	result := &BulkRequestResult{Outcomes: []BulkRequestOutcome{}}
+	users, err := s.resolveTargets(ctx, &assignment.BulkRequestTargets, result)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := s.openRequestsByUser(ctx, meal.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+
+	loc := mealLocation(meal, s.timeZone)
+	message := fmt.Sprintf("An administrator assigned you %s on %s. Reason: %s",
+		meal.Name, formatMealTime(meal.EventDate, loc), assignment.Reason)
+	for _, user := range users {
+		outcome := BulkRequestOutcome{UserID: user.ID, EmployeeID: user.EmployeeID}
+
+		request, exists := open[user.ID]
+		if !exists {
+			request = &model.MealRequest{
+				UserID:      user.ID,
+				MealEventID: meal.ID,
+				Status:      model.RequestStatusPending,
+				CreatedBy:   adminID,
+			}
+		}
+		request.MenuSetID = assignment.MenuSetID
+		request.EventAddressID = assignment.EventAddressID
+		request.NeedsAttention = false
+		request.AttentionNote = ""
+		request.AdminReason = assignment.Reason
+		request.Sequence++
+		request.UpdatedBy = adminID
+
+		items := make([]model.MealRequestItem, 0, len(assignment.Items))
+		for _, item := range assignment.Items {
+			quantity := item.Quantity
+			if quantity <= 0 {
+				quantity = setItems[item.MenuItemID].DefaultQuantity()
+			}
+			items = append(items, model.MealRequestItem{
+				MenuItemID: item.MenuItemID,
+				MenuSetID:  assignment.MenuSetID,
+				IsSelected: true,
+				Quantity:   quantity,
+				Notes:      item.Notes,
+				CreatedBy:  adminID,
+				UpdatedBy:  adminID,
+			})
+		}
+
+		if err := s.requestRepo.SaveWithItems(ctx, request, items); err != nil {
+			outcome.Result, outcome.Error = "failed", "failed to save meal request"
+			result.add(outcome)
+			continue
+		}
+		outcome.RequestID = request.ID
+		outcome.Result = "updated"
+		if !exists {
+			outcome.Result = "created"
+		}
+
+		// Requests added after the event was confirmed are confirmed straight away
+		if request.Status == model.RequestStatusPending && meal.Status == model.MealEventStatusConfirmed {
+			if err := transitionRequest(ctx, s.requestRepo, request, model.RequestStatusApproved, assignment.Reason, &adminID); err != nil {
+				outcome.Error = err.Error()
+			}
+		}
+
+		if err := s.notifService.CreateMealConfirmationNotification(ctx, user.ID, meal.ID, message); err != nil {
+			outcome.Error = "request saved, but the employee could not be notified"
+		}
+		result.add(outcome)
+	}
+	return result, nil
+}
+
+// CancelMealRequestsFor cancels the open requests of the selected employees for a meal event.
+// Unlike employees, admins may do this after the cutoff.
+func (s *mealRequestService) CancelMealRequestsFor(ctx context.Context, cancellation *BulkRequestCancellation, adminID uint) (*BulkRequestResult, error) {
+	meal, err := s.bulkMealEvent(ctx, cancellation.MealEventID, cancellation.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkRequestResult{Outcomes: []BulkRequestOutcome{}}
+	users, err := s.resolveTargets(ctx, &cancellation.BulkRequestTargets, result)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := s.openRequestsByUser(ctx, meal.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+
+	loc := mealLocation(meal, s.timeZone)
+	message := fmt.Sprintf("An administrator cancelled your meal request for %s on %s. Reason: %s",
+		meal.Name, formatMealTime(meal.EventDate, loc), cancellation.Reason)
+	for _, user := range users {
+		outcome := BulkRequestOutcome{UserID: user.ID, EmployeeID: user.EmployeeID}
+
+		request, exists := open[user.ID]
+		if !exists || !request.Status.CanTransitionTo(model.RequestStatusCancelled) {
+			outcome.Result, outcome.Error = "failed", "no open meal request for this event"
+			result.add(outcome)
+			continue
+		}
+		outcome.RequestID = request.ID
+
+		from := request.Status
+		if err := transitionRequest(ctx, s.requestRepo, request, model.RequestStatusCancelled, cancellation.Reason, &adminID); err != nil {
+			outcome.Result, outcome.Error = "failed", err.Error()
+			result.add(outcome)
+			continue
+		}
+		outcome.Result = "cancelled"
+		s.calendar.SendRequestWithdrawal(ctx, request.ID, from, cancellation.Reason)
+
+		if err := s.notifService.CreateMealCancellationNotification(ctx, user.ID, meal.ID, message); err != nil {
+			outcome.Error = "request cancelled, but the employee could not be notified"
+		}
+		result.add(outcome)
+	}
+	return result, nil
+}
+
+// bulkMealEvent loads the meal event of a bulk operation and checks that its requests can still change
+func (s *mealRequestService) bulkMealEvent(ctx context.Context, mealEventID uint, reason string) (*model.MealEvent, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.NewValidationError("a reason is required", nil).
+			WithFields(errors.FieldError{Field: "reason", Message: "reason is required for the audit trail"})
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	switch meal.Status {
+	case model.MealEventStatusDraft, model.MealEventStatusCancelled, model.MealEventStatusCompleted:
+		return nil, errors.NewValidationError("requests of a "+string(meal.Status)+" meal event cannot be changed", nil)
+	}
+	return meal, nil
+}
+
+// resolveTargets finds the active employees selected by IDs, department and employee IDs, each once.
+// Selections that match nobody are recorded as failed outcomes.
+func (s *mealRequestService) resolveTargets(ctx context.Context, targets *BulkRequestTargets, result *BulkRequestResult) ([]model.User, error) {
+	seen := make(map[uint]bool)
+	var users []model.User
+	add := func(user model.User, outcome BulkRequestOutcome) {
+		if !user.IsActive {
+			outcome.Result, outcome.Error = "failed", "user is not active"
+			result.add(outcome)
+			return
+		}
+		if seen[user.ID] {
+			return
+		}
+		seen[user.ID] = true
+		users = append(users, user)
+	}
+
+	for _, userID := range targets.UserIDs {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			result.add(BulkRequestOutcome{UserID: userID, Result: "failed", Error: "user not found"})
+			continue
+		}
+		add(*user, BulkRequestOutcome{UserID: userID})
+	}
+
+	for _, employeeID := range targets.EmployeeIDs {
+		employeeID = strings.TrimSpace(employeeID)
+		number, err := strconv.Atoi(employeeID)
+		if err != nil {
+			result.add(BulkRequestOutcome{EmployeeID: employeeID, Result: "failed", Error: "invalid employee ID"})
+			continue
+		}
+		user, err := s.userRepo.FindByEmployeeID(ctx, number)
+		if err != nil {
+			result.add(BulkRequestOutcome{EmployeeID: employeeID, Result: "failed", Error: "user not found"})
+			continue
+		}
+		add(*user, BulkRequestOutcome{UserID: user.ID, EmployeeID: employeeID})
+	}
+
+	if department := strings.TrimSpace(targets.Department); department != "" {
+		members, err := s.userRepo.FindActive(ctx, map[string]interface{}{"department": department})
+		if err != nil {
+			return nil, errors.NewInternalError("failed to fetch department members", err)
+		}
+		for _, member := range members {
+			add(member, BulkRequestOutcome{UserID: member.ID})
+		}
+	}
+
+	if len(users) == 0 && len(result.Outcomes) == 0 {
+		return nil, errors.NewValidationError("no employees selected", nil).
+			WithFields(errors.FieldError{Field: "user_ids", Message: "select employees by user IDs, department or employee IDs"})
+	}
+	if len(users) > maxBulkRequestUsers {
+		return nil, errors.NewValidationError(fmt.Sprintf("at most %d employees can be selected at once", maxBulkRequestUsers), nil)
+	}
+	return users, nil
+}
+
+// openRequestsByUser maps each requester of a meal event to their pending or approved request
+func (s *mealRequestService) openRequestsByUser(ctx context.Context, mealEventID uint) (map[uint]*model.MealRequest, error) {
+	requests, err := s.requestRepo.FindByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[uint]*model.MealRequest, len(requests))
+	for i := range requests {
+		if isOpenRequest(&requests[i]) {
+			open[requests[i].UserID] = &requests[i]
+		}
+	}
+	return open, nil
+}
+
+// validateAssignment checks that the chosen menu set and address are offered by the meal event
+// and that the items belong to the menu set with quantities within their limits
+func validateAssignment(meal *model.MealEvent, setItems map[uint]model.MenuItem, assignment *BulkRequestAssignment) []errors.FieldError {
+	fields := validateRequestChoice(meal, assignment.MenuSetID, assignment.EventAddressID)
+
+	items := make([]model.MealRequestItem, 0, len(assignment.Items))
+	for i, item := range assignment.Items {
+		if item.MenuItemID == 0 {
+			fields = append(fields, errors.FieldError{Field: fmt.Sprintf("items[%d].menu_item_id", i), Message: "menu item is required"})
+		}
+		if item.Quantity < 0 {
+			fields = append(fields, errors.FieldError{Field: fmt.Sprintf("items[%d].quantity", i), Message: "quantity must be positive"})
+		}
+		items = append(items, model.MealRequestItem{MenuItemID: item.MenuItemID, Quantity: item.Quantity})
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	return validateRequestItems(assignment.MenuSetID, setItems, items)
+}
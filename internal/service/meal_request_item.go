@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+)
+
+// The rules below need no database: callers load the meal event and the items of the
+// chosen menu set, and get back the problems as field errors.
+
+// checkRequestEditable reports why the requests of a meal event can no longer be created or changed
+func checkRequestEditable(meal *model.MealEvent, now time.Time) error {
+	if !meal.IsActive {
+		return errors.NewValidationError("meal event is not active", nil)
+	}
+	if !meal.Status.AcceptsRequests() {
+		return errors.NewValidationError("meal event is not open for requests", nil)
+	}
+	if now.After(meal.CutoffTime) {
+		return errors.NewValidationError("cutoff time has passed", nil)
+	}
+	return nil
+}
+
+// checkRequestOpen reports why a meal request can no longer be changed by its requester.
+// Only pending and approved requests can be edited.
+func checkRequestOpen(request *model.MealRequest) error {
+	if !isOpenRequest(request) {
+		return errors.NewValidationError("cannot edit a "+string(request.Status)+" meal request", nil)
+	}
+	return nil
+}
+
+// validateRequestChoice checks that a menu set and an address are attached to a meal event
+func validateRequestChoice(meal *model.MealEvent, menuSetID, addressID uint) []errors.FieldError {
+	var fields []errors.FieldError
+	if !offersMenuSet(meal, menuSetID) {
+		fields = append(fields, errors.FieldError{Field: "menu_set_id", Message: "menu set is not offered by this meal event"})
+	}
+	if !servesAddress(meal, addressID) {
+		fields = append(fields, errors.FieldError{Field: "event_address_id", Message: "address is not served by this meal event"})
+	}
+	return fields
+}
+
+// validateRequestItem checks one request item against the items of the request's menu set.
+// An item without a menu set gets the request's and a missing quantity the item's default.
+// Field names are prefixed with prefix, e.g. "items[2].".
+func validateRequestItem(menuSetID uint, setItems map[uint]model.MenuItem, item *model.MealRequestItem, prefix string) []errors.FieldError {
+	if item.MenuSetID == 0 {
+		item.MenuSetID = menuSetID
+	}
+	if item.MenuSetID != menuSetID {
+		return []errors.FieldError{{Field: prefix + "menu_set_id", Message: "item must belong to the request's menu set"}}
+	}
+
+	menuItem, ok := setItems[item.MenuItemID]
+	if !ok {
+		return []errors.FieldError{{Field: prefix + "menu_item_id", Message: "menu item is not part of the menu set"}}
+	}
+	if item.Quantity == 0 {
+		item.Quantity = menuItem.DefaultQuantity()
+	}
+	if message := quantityProblem(menuItem, item.Quantity); message != "" {
+		return []errors.FieldError{{Field: prefix + "quantity", Message: message}}
+	}
+	return nil
+}
+
+// validateRequestItems checks the items of a request against the items of its menu set,
+// each menu item at most once
+func validateRequestItems(menuSetID uint, setItems map[uint]model.MenuItem, items []model.MealRequestItem) []errors.FieldError {
+	var fields []errors.FieldError
+	seen := make(map[uint]bool, len(items))
+	for i := range items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		if seen[items[i].MenuItemID] {
+			fields = append(fields, errors.FieldError{Field: prefix + "menu_item_id", Message: "menu item is chosen more than once"})
+			continue
+		}
+		seen[items[i].MenuItemID] = true
+		fields = append(fields, validateRequestItem(menuSetID, setItems, &items[i], prefix)...)
+	}
+	return fields
+}
+
+// quantityProblem explains why a number of servings is outside a menu item's limits, or returns ""
+func quantityProblem(menuItem model.MenuItem, quantity int) string {
+	if menuItem.AllowsQuantity(quantity) {
+		return ""
+	}
+	if menuItem.MaxQuantity > 0 {
+		return fmt.Sprintf("quantity of %s must be between %d and %d", menuItem.Name, menuItem.DefaultQuantity(), menuItem.MaxQuantity)
+	}
+	return fmt.Sprintf("quantity of %s must be at least %d", menuItem.Name, menuItem.DefaultQuantity())
+}
+
+// validateQuantityLimits checks the serving limits of a menu item, treating a missing minimum as 1
+func validateQuantityLimits(menuItem *model.MenuItem) []errors.FieldError {
+	var fields []errors.FieldError
+	if menuItem.MinQuantity < 0 {
+		fields = append(fields, errors.FieldError{Field: "min_quantity", Message: "minimum quantity must not be negative"})
+	}
+	if menuItem.MinQuantity == 0 {
+		menuItem.MinQuantity = 1
+	}
+	if menuItem.MaxQuantity < 0 {
+		fields = append(fields, errors.FieldError{Field: "max_quantity", Message: "maximum quantity must not be negative"})
+	}
+	if menuItem.MaxQuantity > 0 && menuItem.MaxQuantity < menuItem.MinQuantity {
+		fields = append(fields, errors.FieldError{Field: "max_quantity", Message: "maximum quantity must not be below the minimum"})
+	}
+	return fields
+}
+
+// menuItemsByID indexes the items of a menu set
+func menuItemsByID(items []model.MenuItem) map[uint]model.MenuItem {
+	byID := make(map[uint]model.MenuItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+)
+
+// fieldNames lists the fields of field errors in order
+func fieldNames(fields []errors.FieldError) []string {
+	names := []string{}
+	for _, field := range fields {
+		names = append(names, field.Field)
+	}
+	return names
+}
+
+func testSetItems() map[uint]model.MenuItem {
+	return menuItemsByID([]model.MenuItem{
+		{Base: model.Base{ID: 1}, Name: "Rice"},
+		{Base: model.Base{ID: 2}, Name: "Chicken curry", MinQuantity: 1, MaxQuantity: 2},
+		{Base: model.Base{ID: 3}, Name: "Samosa", MinQuantity: 2, MaxQuantity: 4},
+	})
+}
+
+func TestValidateRequestItem(t *testing.T) {
+	tests := []struct {
+		name         string
+		item         model.MealRequestItem
+		wantFields   []string
+		wantQuantity int
+	}{
+		{"within limits", model.MealRequestItem{MenuItemID: 2, Quantity: 2}, []string{}, 2},
+		{"no limits", model.MealRequestItem{MenuItemID: 1, Quantity: 10}, []string{}, 10},
+		{"missing quantity takes the minimum", model.MealRequestItem{MenuItemID: 3}, []string{}, 2},
+		{"below the minimum", model.MealRequestItem{MenuItemID: 3, Quantity: 1}, []string{"quantity"}, 1},
+		{"above the maximum", model.MealRequestItem{MenuItemID: 2, Quantity: 3}, []string{"quantity"}, 3},
+		{"negative", model.MealRequestItem{MenuItemID: 1, Quantity: -1}, []string{"quantity"}, -1},
+		{"not in the chosen set", model.MealRequestItem{MenuItemID: 9, Quantity: 1}, []string{"menu_item_id"}, 1},
+		{"other menu set", model.MealRequestItem{MenuItemID: 1, MenuSetID: 8, Quantity: 1}, []string{"menu_set_id"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			fields := validateRequestItem(7, testSetItems(), &item, "")
+			if got := fieldNames(fields); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+			if item.Quantity != tt.wantQuantity {
+				t.Errorf("quantity = %d, want %d", item.Quantity, tt.wantQuantity)
+			}
+		})
+	}
+}
+
+func TestValidateRequestItems(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []model.MealRequestItem
+		wantFields []string
+	}{
+		{
+			name:       "valid",
+			items:      []model.MealRequestItem{{MenuItemID: 1}, {MenuItemID: 2, Quantity: 2}},
+			wantFields: []string{},
+		},
+		{
+			name:       "duplicate item",
+			items:      []model.MealRequestItem{{MenuItemID: 1}, {MenuItemID: 2}, {MenuItemID: 1}},
+			wantFields: []string{"items[2].menu_item_id"},
+		},
+		{
+			name:       "item not in the chosen set",
+			items:      []model.MealRequestItem{{MenuItemID: 1}, {MenuItemID: 5}},
+			wantFields: []string{"items[1].menu_item_id"},
+		},
+		{
+			name:       "every problem is reported",
+			items:      []model.MealRequestItem{{MenuItemID: 3, Quantity: 5}, {MenuItemID: 3}, {MenuItemID: 2, Quantity: 3}},
+			wantFields: []string{"items[0].quantity", "items[1].menu_item_id", "items[2].quantity"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validateRequestItems(7, testSetItems(), tt.items)
+			if got := fieldNames(fields); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestQuantityProblem(t *testing.T) {
+	items := testSetItems()
+	tests := []struct {
+		name     string
+		item     model.MenuItem
+		quantity int
+		want     string
+	}{
+		{"at the minimum", items[3], 2, ""},
+		{"at the maximum", items[3], 4, ""},
+		{"below the minimum", items[3], 1, "quantity of Samosa must be between 2 and 4"},
+		{"above the maximum", items[2], 3, "quantity of Chicken curry must be between 1 and 2"},
+		{"no maximum", items[1], 0, "quantity of Rice must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quantityProblem(tt.item, tt.quantity); got != tt.want {
+				t.Errorf("quantityProblem = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateQuantityLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       model.MenuItem
+		wantFields []string
+		wantMin    int
+	}{
+		{"no limits", model.MenuItem{}, []string{}, 1},
+		{"minimum and maximum", model.MenuItem{MinQuantity: 2, MaxQuantity: 3}, []string{}, 2},
+		{"maximum equals minimum", model.MenuItem{MinQuantity: 2, MaxQuantity: 2}, []string{}, 2},
+		{"negative minimum", model.MenuItem{MinQuantity: -1}, []string{"min_quantity"}, -1},
+		{"negative maximum", model.MenuItem{MaxQuantity: -1}, []string{"max_quantity"}, 1},
+		{"maximum below minimum", model.MenuItem{MinQuantity: 3, MaxQuantity: 2}, []string{"max_quantity"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			if got := fieldNames(validateQuantityLimits(&item)); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+			if item.MinQuantity != tt.wantMin {
+				t.Errorf("min quantity = %d, want %d", item.MinQuantity, tt.wantMin)
+			}
+		})
+	}
+}
+
+func TestValidateRequestChoice(t *testing.T) {
+	meal := &model.MealEvent{
+		MenuSets:  []model.MealEventSet{{MenuSetID: 7}},
+		Addresses: []model.MealEventAddress{{AddressID: 3}},
+	}
+	tests := []struct {
+		name       string
+		menuSetID  uint
+		addressID  uint
+		wantFields []string
+	}{
+		{"offered set and served address", 7, 3, []string{}},
+		{"set not offered", 8, 3, []string{"menu_set_id"}},
+		{"address not served", 7, 4, []string{"event_address_id"}},
+		{"neither", 8, 4, []string{"menu_set_id", "event_address_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldNames(validateRequestChoice(meal, tt.menuSetID, tt.addressID))
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestCheckRequestEditable(t *testing.T) {
+	now := time.Date(2025, time.May, 5, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		meal    model.MealEvent
+		wantErr string
+	}{
+		{
+			name: "before cutoff",
+			meal: model.MealEvent{IsActive: true, Status: model.MealEventStatusPublished, CutoffTime: now.Add(time.Hour)},
+		},
+		{
+			name: "at cutoff",
+			meal: model.MealEvent{IsActive: true, Status: model.MealEventStatusPublished, CutoffTime: now},
+		},
+		{
+			name:    "after cutoff",
+			meal:    model.MealEvent{IsActive: true, Status: model.MealEventStatusPublished, CutoffTime: now.Add(-time.Minute)},
+			wantErr: "cutoff time has passed",
+		},
+		{
+			name:    "closed event",
+			meal:    model.MealEvent{IsActive: true, Status: model.MealEventStatusClosed, CutoffTime: now.Add(time.Hour)},
+			wantErr: "meal event is not open for requests",
+		},
+		{
+			name:    "draft event",
+			meal:    model.MealEvent{IsActive: true, Status: model.MealEventStatusDraft, CutoffTime: now.Add(time.Hour)},
+			wantErr: "meal event is not open for requests",
+		},
+		{
+			name:    "inactive event",
+			meal:    model.MealEvent{Status: model.MealEventStatusPublished, CutoffTime: now.Add(time.Hour)},
+			wantErr: "meal event is not active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationError(t, checkRequestEditable(&tt.meal, now), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckRequestOpen(t *testing.T) {
+	tests := []struct {
+		status  model.RequestStatus
+		wantErr string
+	}{
+		{model.RequestStatusPending, ""},
+		{model.RequestStatusApproved, ""},
+		{model.RequestStatusRejected, "cannot edit a rejected meal request"},
+		{model.RequestStatusCompleted, "cannot edit a completed meal request"},
+		{model.RequestStatusCancelled, "cannot edit a cancelled meal request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assertValidationError(t, checkRequestOpen(&model.MealRequest{Status: tt.status}), tt.wantErr)
+		})
+	}
+}
+
+// assertValidationError checks that err is nil when want is empty, and otherwise a validation error with message want
+func assertValidationError(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		t.Fatalf("error = %v, want a validation error %q", err, want)
+	}
+	if appErr.Type != errors.ErrorTypeValidation || appErr.Message != want {
+		t.Errorf("error = %s %q, want %s %q", appErr.Type, appErr.Message, errors.ErrorTypeValidation, want)
+	}
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// MenuSetSurplus sets the spare portions of a menu set in a meal event
+type MenuSetSurplus struct {
+	MenuSetID uint `json:"menu_set_id" binding:"required"`
+	Surplus   int  `json:"surplus" binding:"min=0" example:"5"`
+}
+
+// LateSetSurplus compares the spare portions of a menu set with its late requests
+type LateSetSurplus struct {
+	MenuSetID   uint   `json:"menu_set_id"`
+	MenuSetName string `json:"menu_set_name"`
+	Label       string `json:"label,omitempty"`
+	Surplus     int    `json:"surplus"`
+	Approved    int    `json:"approved"` // late requests approved for the set
+	Pending     int    `json:"pending"`  // late requests waiting for a decision
+	Remaining   int    `json:"remaining"`
+}
+
+// LateRequestOverview shows the kitchen the late requests of a meal event next to the surplus left
+type LateRequestOverview struct {
+	MealEventID       uint                `json:"meal_event_id"`
+	AllowLateRequests bool                `json:"allow_late_requests"`
+	LateRequestCap    int                 `json:"late_request_cap"` // 0 is unlimited
+	Approved          int                 `json:"approved"`
+	MenuSets          []LateSetSurplus    `json:"menu_sets"`
+	Requests          []model.MealRequest `json:"requests"` // late requests, pending first
+}
+
+// CreateLateRequest submits a request after the cutoff of a meal event that accepts late requests.
+// It stays pending until the kitchen approves or rejects it based on the surplus left.
+func (s *mealRequestService) CreateLateRequest(ctx context.Context, request *model.MealRequest, userID uint) error {
+	if request == nil {
+		return errors.NewValidationError("request cannot be nil", nil)
+	}
+	if request.LateReason == "" {
+		return errors.NewValidationError("invalid late request", nil).
+			WithFields(errors.FieldError{Field: "reason", Message: "a justification is required"})
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+	if err := checkLateRequestOpen(meal, time.Now()); err != nil {
+		return err
+	}
+	if fields := validateRequestChoice(meal, request.MenuSetID, request.EventAddressID); len(fields) > 0 {
+		return errors.NewValidationError("invalid meal request", nil).WithFields(fields...)
+	}
+
+	existingRequests, err := s.requestRepo.FindByMealEventID(ctx, request.MealEventID)
+	if err != nil {
+		return err
+	}
+	for _, existingRequest := range existingRequests {
+		if existingRequest.UserID == userID && existingRequest.Status.IsActive() {
+			return errors.NewValidationError("user already has a request for this meal event", nil)
+		}
+	}
+	if meal.LateRequestCap > 0 && countApprovedLate(existingRequests, 0) >= meal.LateRequestCap {
+		return errors.NewConflictError("no more late requests are accepted for this meal event", nil)
+	}
+
+	guests, err := s.guestRepo.FindByMealEventID(ctx, request.MealEventID)
+	if err != nil {
+		return err
+	}
+	if isFull(meal, request.EventAddressID, existingRequests, guests) {
+		return errors.NewConflictError("no seats are left at this location", nil)
+	}
+
+	request.Status = model.RequestStatusPending
+	request.IsLate = true
+	request.AwaitingApproval = false
+	request.ConfirmedAt = nil
+	request.RequestItems = nil
+	request.UserID = userID
+	request.CreatedBy = userID
+	request.UpdatedBy = userID
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return err
+	}
+	request.Warnings = s.dietaryWarnings(ctx, userID, request.MenuSetID, nil)
+
+	s.notifyLateRequest(ctx, meal, request)
+	return nil
+}
+
+// GetLateRequests lists the late requests of a meal event with the surplus left per menu set
+func (s *mealRequestService) GetLateRequests(ctx context.Context, mealEventID uint) (*LateRequestOverview, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	requests, err := s.requestRepo.FindByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+
+	overview := &LateRequestOverview{
+		MealEventID:       meal.ID,
+		AllowLateRequests: meal.AllowLateRequests,
+		LateRequestCap:    meal.LateRequestCap,
+		Approved:          countApprovedLate(requests, 0),
+		MenuSets:          make([]LateSetSurplus, 0, len(meal.MenuSets)),
+		Requests:          []model.MealRequest{},
+	}
+	for _, set := range meal.MenuSets {
+		summary := LateSetSurplus{
+			MenuSetID:   set.MenuSetID,
+			MenuSetName: set.MenuSet.MenuSetName,
+			Label:       set.Label,
+			Surplus:     set.Surplus,
+			Approved:    countApprovedLate(requests, set.MenuSetID),
+		}
+		for _, request := range requests {
+			if isUndecidedLate(&request) && request.MenuSetID == set.MenuSetID {
+				summary.Pending++
+			}
+		}
+		summary.Remaining = max(set.Surplus-summary.Approved, 0)
+		overview.MenuSets = append(overview.MenuSets, summary)
+	}
+
+	for _, request := range requests {
+		if isUndecidedLate(&request) {
+			overview.Requests = append(overview.Requests, request)
+		}
+	}
+	for _, request := range requests {
+		if request.IsLate && !isUndecidedLate(&request) {
+			overview.Requests = append(overview.Requests, request)
+		}
+	}
+	return overview, nil
+}
+
+// SetSurplus records the spare portions per menu set that late requests can be served from
+func (s *mealRequestService) SetSurplus(ctx context.Context, mealEventID uint, surplus []MenuSetSurplus, adminID uint) (*LateRequestOverview, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	var fields []errors.FieldError
+	for i, entry := range surplus {
+		if !offersMenuSet(meal, entry.MenuSetID) {
+			fields = append(fields, errors.FieldError{Field: fmt.Sprintf("[%d].menu_set_id", i), Message: "menu set is not offered by this meal event"})
+		}
+		if entry.Surplus < 0 {
+			fields = append(fields, errors.FieldError{Field: fmt.Sprintf("[%d].surplus", i), Message: "surplus must not be negative"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, errors.NewValidationError("invalid surplus", nil).WithFields(fields...)
+	}
+
+	// Either every menu set gets its new surplus or none does
+	err = s.mealRepo.Transaction(ctx, func(mealRepo repository.MealEventRepository) error {
+		for _, entry := range surplus {
+			if err := mealRepo.UpdateSurplus(ctx, mealEventID, entry.MenuSetID, entry.Surplus, adminID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("failed to update surplus", err)
+	}
+	return s.GetLateRequests(ctx, mealEventID)
+}
+
+// ReviewLateRequest approves or rejects a late request and tells the requester. Approval needs
+// surplus left for the request's menu set and room under the meal event's late request cap.
+// The check and the status change happen in one transaction that locks the meal event, so
+// concurrent approvals cannot together exceed the surplus or the cap.
+func (s *mealRequestService) ReviewLateRequest(ctx context.Context, requestID uint, approve bool, note string, adminID uint) (*model.MealRequest, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if !isUndecidedLate(request) {
+		return nil, errors.NewValidationError("meal request is not a late request awaiting review", nil)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	to, reason := model.RequestStatusRejected, "late request rejected"
+	if approve {
+		to, reason = model.RequestStatusApproved, "late request approved"
+	}
+	if note != "" {
+		reason += ": " + note
+	}
+	err = s.requestRepo.Transaction(ctx, func(repo repository.MealRequestRepository) error {
+		if approve {
+			locked, err := repo.LockMealEvent(ctx, request.MealEventID)
+			if err != nil {
+				return errors.NewNotFoundError("meal event not found", err)
+			}
+			if err := checkLateApproval(ctx, repo, locked, request); err != nil {
+				return err
+			}
+		}
+		return transitionRequest(ctx, repo, request, to, reason, &adminID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your late meal request for %s on %s was %s.",
+		meal.Name, formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone)), request.Status)
+	if note != "" {
+		message += " Note: " + note
+	}
+	if approve {
+		_ = s.notifService.CreateMealConfirmationNotification(ctx, request.UserID, meal.ID, message)
+	} else {
+		_ = s.notifService.CreateMealCancellationNotification(ctx, request.UserID, meal.ID, message)
+	}
+	return request, nil
+}
+
+// checkLateApproval reports why a late request cannot be served. The meal event must be locked
+// by the transaction of requestRepo, so that the approved requests it counts cannot change.
+func checkLateApproval(ctx context.Context, requestRepo repository.MealRequestRepository, meal *model.MealEvent, request *model.MealRequest) error {
+	if !time.Now().Before(mealEnd(meal)) {
+		return errors.NewValidationError("meal event has ended", nil)
+	}
+
+	requests, err := requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	if meal.LateRequestCap > 0 && countApprovedLate(requests, 0) >= meal.LateRequestCap {
+		return errors.NewConflictError(fmt.Sprintf("the cap of %d late requests has been reached", meal.LateRequestCap), nil)
+	}
+	for _, set := range meal.MenuSets {
+		if set.MenuSetID == request.MenuSetID && countApprovedLate(requests, set.MenuSetID) < set.Surplus {
+			return nil
+		}
+	}
+	return errors.NewConflictError("no surplus is left for this menu set", nil)
+}
+
+// notifyLateRequest asks the kitchen admins to review a late request
+func (s *mealRequestService) notifyLateRequest(ctx context.Context, meal *model.MealEvent, request *model.MealRequest) {
+	user, err := s.userRepo.FindByID(ctx, request.UserID)
+	if err != nil {
+		return
+	}
+	admins, err := s.userRepo.FindActive(ctx, map[string]interface{}{"role": model.UserRoleAdmin})
+	if err != nil {
+		return
+	}
+	message := fmt.Sprintf("%s asked for %s on %s after the cutoff: %s Please approve or reject the request.",
+		user.Name, meal.Name, formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone)), request.LateReason)
+	for _, admin := range admins {
+		_ = s.notifService.CreateAdminNotification(ctx, admin.ID, message, "high")
+	}
+}
+
+// checkLateRequestOpen reports why a meal event does not take late requests
+func checkLateRequestOpen(meal *model.MealEvent, now time.Time) error {
+	if !meal.IsActive {
+		return errors.NewValidationError("meal event is not active", nil)
+	}
+	if !meal.AllowLateRequests {
+		return errors.NewValidationError("meal event does not accept late requests", nil)
+	}
+	if !now.After(meal.CutoffTime) {
+		return errors.NewValidationError("cutoff time has not passed; submit a regular request instead", nil)
+	}
+	switch meal.Status {
+	case model.MealEventStatusPublished, model.MealEventStatusClosed, model.MealEventStatusConfirmed:
+	default:
+		return errors.NewValidationError("meal event is not open for requests", nil)
+	}
+	if !now.Before(mealEnd(meal)) {
+		return errors.NewValidationError("meal event has ended", nil)
+	}
+	return nil
+}
+
+// isUndecidedLate reports whether a request is a late request the kitchen has not decided on yet
+func isUndecidedLate(request *model.MealRequest) bool {
+	return request.IsLate && request.Status == model.RequestStatusPending
+}
+
+// countApprovedLate counts the late requests that were approved, for one menu set or, given 0, for all
+func countApprovedLate(requests []model.MealRequest, menuSetID uint) int {
+	count := 0
+	for _, request := range requests {
+		if !request.IsLate || isUndecidedLate(&request) || !request.Status.IsActive() {
+			continue
+		}
+		if menuSetID == 0 || request.MenuSetID == menuSetID {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// MealTransferOffer offers a confirmed meal to a colleague, or to the surplus pool
+type MealTransferOffer struct {
+	ToUserID *uint  `json:"to_user_id" example:"12"` // leave empty to release the meal to the surplus pool
+	Note     string `json:"note" example:"Out of office this afternoon"`
+}
+
+// mealTransferService implements MealTransferService interface
+type mealTransferService struct {
+	transferRepo repository.MealTransferRepository
+	requestRepo  repository.MealRequestRepository
+	mealRepo     repository.MealEventRepository
+	userRepo     repository.UserRepository
+	notifService NotificationService
+	calendar     CalendarService
+	timeZone     string
+}
+
+// NewMealTransferService creates a new instance of MealTransferService
+func NewMealTransferService(
+	transferRepo repository.MealTransferRepository,
+	requestRepo repository.MealRequestRepository,
+	mealRepo repository.MealEventRepository,
+	userRepo repository.UserRepository,
+	notifService NotificationService,
+	calendar CalendarService,
+	timeZone string,
+) MealTransferService {
+	return &mealTransferService{
+		transferRepo: transferRepo,
+		requestRepo:  requestRepo,
+		mealRepo:     mealRepo,
+		userRepo:     userRepo,
+		notifService: notifService,
+		calendar:     calendar,
+		timeZone:     timeZone,
+	}
+}
+
+// OfferMeal offers the user's confirmed meal to a colleague, or to the surplus pool when no
+// colleague is named. Meals can only change hands between the cutoff and the end of the meal
+// event; before the cutoff the request can simply be withdrawn.
+func (s *mealTransferService) OfferMeal(ctx context.Context, requestID uint, userID uint, offer *MealTransferOffer) (*model.MealTransfer, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if request.UserID != userID {
+		return nil, errors.NewForbiddenError("unauthorized to offer this meal", nil)
+	}
+	if request.Status != model.RequestStatusApproved {
+		return nil, errors.NewValidationError("only confirmed meal requests can be offered", nil)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	if err := checkTransferWindow(meal, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.transferRepo.FindOpenByRequestID(ctx, request.ID); err == nil {
+		return nil, errors.NewConflictError("meal is already on offer; withdraw the offer first", nil)
+	}
+
+	transfer := &model.MealTransfer{
+		MealRequestID: request.ID,
+		MealEventID:   request.MealEventID,
+		FromUserID:    userID,
+		ToUserID:      offer.ToUserID,
+		Pool:          offer.ToUserID == nil,
+		Status:        model.MealTransferStatusOffered,
+		Note:          offer.Note,
+	}
+
+	if offer.ToUserID != nil {
+		if *offer.ToUserID == userID {
+			return nil, errors.NewValidationError("invalid meal transfer", nil).
+				WithFields(errors.FieldError{Field: "to_user_id", Message: "cannot offer a meal to yourself"})
+		}
+		if err := s.checkRecipient(ctx, *offer.ToUserID, meal.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		if _, openErr := s.transferRepo.FindOpenByRequestID(ctx, request.ID); openErr == nil {
+			return nil, errors.NewConflictError("meal is already on offer; withdraw the offer first", nil)
+		}
+		return nil, errors.NewInternalError("failed to offer meal", err)
+	}
+
+	if offer.ToUserID != nil {
+		giver := s.userName(ctx, userID)
+		message := fmt.Sprintf("%s offered you their meal for %s on %s.", giver, meal.Name, s.mealTime(meal))
+		if offer.Note != "" {
+			message += " " + offer.Note
+		}
+		_ = s.notifService.CreateMealConfirmationNotification(ctx, *offer.ToUserID, meal.ID, message)
+	}
+
+	return s.transferRepo.FindByID(ctx, transfer.ID)
+}
+
+// GetMyTransfers lists the transfers a user offered or was offered, latest first
+func (s *mealTransferService) GetMyTransfers(ctx context.Context, userID uint) ([]model.MealTransfer, error) {
+	transfers, err := s.transferRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal transfers", err)
+	}
+	return transfers, nil
+}
+
+// GetPool lists the meals in the surplus pool that can still be claimed
+func (s *mealTransferService) GetPool(ctx context.Context) ([]model.MealTransfer, error) {
+	transfers, err := s.transferRepo.FindOpenPool(ctx, time.Now())
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch surplus meals", err)
+	}
+	return transfers, nil
+}
+
+// GetRequestTransfers lists the transfers of a meal request, oldest first. Admins, the current
+// owner and everyone who took part in a transfer may see them.
+func (s *mealTransferService) GetRequestTransfers(ctx context.Context, requestID uint, userID uint, isAdmin bool) ([]model.MealTransfer, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+
+	transfers, err := s.transferRepo.FindByRequestID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal transfers", err)
+	}
+
+	if isAdmin || request.UserID == userID {
+		return transfers, nil
+	}
+	for _, transfer := range transfers {
+		if transfer.FromUserID == userID || (transfer.ToUserID != nil && *transfer.ToUserID == userID) {
+			return transfers, nil
+		}
+	}
+	return nil, errors.NewForbiddenError("unauthorized to access this request", nil)
+}
+
+// AcceptOffer hands a meal offered to the user over to them
+func (s *mealTransferService) AcceptOffer(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error) {
+	transfer, err := s.findOpen(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Pool || transfer.ToUserID == nil || *transfer.ToUserID != userID {
+		return nil, errors.NewForbiddenError("this meal was not offered to you", nil)
+	}
+	return s.complete(ctx, transfer, userID, model.MealTransferStatusAccepted)
+}
+
+// ClaimMeal hands a meal from the surplus pool over to the user. The first claim wins.
+func (s *mealTransferService) ClaimMeal(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error) {
+	transfer, err := s.findOpen(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if !transfer.Pool {
+		return nil, errors.NewForbiddenError("this meal is not in the surplus pool", nil)
+	}
+	if transfer.FromUserID == userID {
+		return nil, errors.NewValidationError("cannot claim your own meal; withdraw the offer instead", nil)
+	}
+	return s.complete(ctx, transfer, userID, model.MealTransferStatusClaimed)
+}
+
+// DeclineOffer turns down a meal offered to the user
+func (s *mealTransferService) DeclineOffer(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error) {
+	transfer, err := s.findOpen(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Pool || transfer.ToUserID == nil || *transfer.ToUserID != userID {
+		return nil, errors.NewForbiddenError("this meal was not offered to you", nil)
+	}
+
+	if err := s.close(ctx, transfer, model.MealTransferStatusDeclined); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%s declined your meal for %s on %s. The meal is still yours.",
+		s.userName(ctx, userID), transfer.MealEvent.Name, s.mealTime(&transfer.MealEvent))
+	_ = s.notifService.CreateMealConfirmationNotification(ctx, transfer.FromUserID, transfer.MealEventID, message)
+
+	return transfer, nil
+}
+
+// WithdrawOffer takes back an offer the user made
+func (s *mealTransferService) WithdrawOffer(ctx context.Context, transferID uint, userID uint) (*model.MealTransfer, error) {
+	transfer, err := s.findOpen(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.FromUserID != userID {
+		return nil, errors.NewForbiddenError("unauthorized to withdraw this offer", nil)
+	}
+
+	if err := s.close(ctx, transfer, model.MealTransferStatusWithdrawn); err != nil {
+		return nil, err
+	}
+
+	if transfer.ToUserID != nil {
+		message := fmt.Sprintf("%s withdrew their offer of a meal for %s on %s.",
+			s.userName(ctx, userID), transfer.MealEvent.Name, s.mealTime(&transfer.MealEvent))
+		_ = s.notifService.CreateMealConfirmationNotification(ctx, *transfer.ToUserID, transfer.MealEventID, message)
+	}
+
+	return transfer, nil
+}
+
+// complete makes the recipient the owner of the offered meal request and tells both parties,
+// moving the calendar invitation along with it.
+// The meal request keeps its menu set, address and status, so estimates do not change.
+func (s *mealTransferService) complete(ctx context.Context, transfer *model.MealTransfer, toUserID uint, status model.MealTransferStatus) (*model.MealTransfer, error) {
+	if err := checkTransferWindow(&transfer.MealEvent, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkRecipient(ctx, toUserID, transfer.MealEventID); err != nil {
+		return nil, err
+	}
+
+	transfer.Status = status
+	if err := s.transferRepo.CompleteTransfer(ctx, transfer, toUserID); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return nil, errors.NewConflictError("meal is no longer on offer", err)
+		}
+		return nil, errors.NewInternalError("failed to transfer meal", err)
+	}
+
+	meal := &transfer.MealEvent
+	giver := s.userName(ctx, transfer.FromUserID)
+	recipient := s.userName(ctx, toUserID)
+	_ = s.notifService.CreateMealConfirmationNotification(ctx, toUserID, meal.ID,
+		fmt.Sprintf("The meal for %s on %s from %s is now yours.", meal.Name, s.mealTime(meal), giver))
+	_ = s.notifService.CreateMealConfirmationNotification(ctx, transfer.FromUserID, meal.ID,
+		fmt.Sprintf("%s took over your meal for %s on %s.", recipient, meal.Name, s.mealTime(meal)))
+	s.calendar.SendRequestTransfer(ctx, transfer.MealRequestID, transfer.FromUser)
+
+	return s.transferRepo.FindByID(ctx, transfer.ID)
+}
+
+// close ends an open offer without a new owner
+func (s *mealTransferService) close(ctx context.Context, transfer *model.MealTransfer, status model.MealTransferStatus) error {
+	now := time.Now()
+	transfer.Status = status
+	transfer.RespondedAt = &now
+	if err := s.transferRepo.UpdateStatus(ctx, transfer, model.MealTransferStatusOffered); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return errors.NewConflictError("meal is no longer on offer", err)
+		}
+		return errors.NewInternalError("failed to update meal transfer", err)
+	}
+	return nil
+}
+
+// findOpen finds a transfer that is still on offer
+func (s *mealTransferService) findOpen(ctx context.Context, transferID uint) (*model.MealTransfer, error) {
+	transfer, err := s.transferRepo.FindByID(ctx, transferID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal transfer not found", err)
+	}
+	if transfer.Status != model.MealTransferStatusOffered {
+		return nil, errors.NewConflictError("meal is no longer on offer", nil)
+	}
+	return transfer, nil
+}
+
+// checkRecipient makes sure a user can take over a meal of a meal event
+func (s *mealTransferService) checkRecipient(ctx context.Context, userID uint, mealEventID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.NewNotFoundError("user not found", err)
+	}
+	if !user.IsActive {
+		return errors.NewValidationError("invalid meal transfer", nil).
+			WithFields(errors.FieldError{Field: "to_user_id", Message: "user is not active"})
+	}
+
+	requests, err := s.requestRepo.FindByMealEventID(ctx, mealEventID)
+	if err != nil {
+		return errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	for _, request := range requests {
+		if request.UserID == userID && request.Status.IsActive() {
+			return errors.NewConflictError("user already has a request for this meal event", nil)
+		}
+	}
+	return nil
+}
+
+// checkTransferWindow allows meals to change hands from the cutoff until the meal event ends
+func checkTransferWindow(meal *model.MealEvent, now time.Time) error {
+	if !now.After(meal.CutoffTime) {
+		return errors.NewValidationError("meals can be offered once the cutoff has passed; until then withdraw the request instead", nil)
+	}
+	if !now.Before(mealEnd(meal)) {
+		return errors.NewValidationError("meal event has ended", nil)
+	}
+	return nil
+}
+
+// userName returns a user's name for notifications
+func (s *mealTransferService) userName(ctx context.Context, userID uint) string {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "A colleague"
+	}
+	return user.Name
+}
+
+// mealTime formats the start of a meal event for notifications
+func (s *mealTransferService) mealTime(meal *model.MealEvent) string {
+	return formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone))
+}
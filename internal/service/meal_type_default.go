@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+// mealTypeDefaultService handles business logic for per meal type defaults
+type mealTypeDefaultService struct {
+	defaultRepo repository.MealTypeDefaultRepository
+}
+
+// NewMealTypeDefaultService creates a new instance of MealTypeDefaultService
+func NewMealTypeDefaultService(defaultRepo repository.MealTypeDefaultRepository) MealTypeDefaultService {
+	return &mealTypeDefaultService{
+		defaultRepo: defaultRepo,
+	}
+}
+
+// ListDefaults retrieves the defaults of every configured meal type
+func (s *mealTypeDefaultService) ListDefaults(ctx context.Context) ([]model.MealTypeDefault, error) {
+	return s.defaultRepo.FindAll(ctx)
+}
+
+// GetDefault retrieves the defaults of a meal type
+func (s *mealTypeDefaultService) GetDefault(ctx context.Context, mealType model.MealType) (*model.MealTypeDefault, error) {
+	if !mealType.IsValid() {
+		return nil, errors.NewValidationError("invalid meal type", nil)
+	}
+
+	defaults, err := s.defaultRepo.FindByMealType(ctx, mealType)
+	if err != nil {
+		return nil, errors.NewNotFoundError("no defaults configured for this meal type", err)
+	}
+	return defaults, nil
+}
+
+// SetDefault creates or replaces the defaults of a meal type
+func (s *mealTypeDefaultService) SetDefault(ctx context.Context, mealType model.MealType, defaults *model.MealTypeDefault, userID uint) error {
+	if !mealType.IsValid() {
+		return errors.NewValidationError("invalid meal type", nil)
+	}
+	defaults.MealType = mealType
+	if err := validateMealTypeDefault(defaults); err != nil {
+		return err
+	}
+
+	for i := range defaults.MenuSets {
+		defaults.MenuSets[i].CreatedBy = userID
+		defaults.MenuSets[i].UpdatedBy = userID
+	}
+	for i := range defaults.Addresses {
+		defaults.Addresses[i].CreatedBy = userID
+		defaults.Addresses[i].UpdatedBy = userID
+	}
+	defaults.UpdatedBy = userID
+
+	existing, err := s.defaultRepo.FindByMealType(ctx, mealType)
+	if err != nil {
+		defaults.ID = 0
+		defaults.CreatedBy = userID
+		if err := s.defaultRepo.Create(ctx, defaults); err != nil {
+			return errors.NewInternalError("failed to save meal type defaults", err)
+		}
+		return nil
+	}
+
+	defaults.ID = existing.ID
+	defaults.CreatedBy = existing.CreatedBy
+	defaults.CreatedAt = existing.CreatedAt
+	if err := s.defaultRepo.Update(ctx, defaults); err != nil {
+		return errors.NewInternalError("failed to save meal type defaults", err)
+	}
+	return nil
+}
+
+// DeleteDefault removes the defaults of a meal type
+func (s *mealTypeDefaultService) DeleteDefault(ctx context.Context, mealType model.MealType) error {
+	defaults, err := s.GetDefault(ctx, mealType)
+	if err != nil {
+		return err
+	}
+	return s.defaultRepo.Delete(ctx, defaults)
+}
+
+// applyMealTypeDefaults fills in whatever a new meal event leaves out from its meal type's defaults.
+// An event date at midnight in loc is moved to the type's usual start time there.
+func applyMealTypeDefaults(meal *model.MealEvent, defaults *model.MealTypeDefault, loc *time.Location, userID uint) error {
+	if !meal.EventDate.IsZero() {
+		date := meal.EventDate.In(loc)
+		if date.Equal(utils.StartOfDay(date, loc)) {
+			hour, minute, err := parseStartTime(defaults.StartTime)
+			if err != nil {
+				return errors.NewInternalError("meal type default start time is invalid", err)
+			}
+			meal.EventDate = utils.AtClock(date, hour, minute, loc)
+		}
+		if meal.CutoffTime.IsZero() {
+			meal.CutoffTime = meal.EventDate.Add(-time.Duration(defaults.CutoffOffset) * time.Minute)
+		}
+	}
+	if meal.EventDuration == 0 {
+		meal.EventDuration = defaults.EventDuration
+	}
+
+	if len(meal.MenuSets) == 0 {
+		for _, set := range defaults.MenuSets {
+			meal.MenuSets = append(meal.MenuSets, model.MealEventSet{
+				MenuSetID: set.MenuSetID,
+				Label:     set.Label,
+				Note:      set.Note,
+				CreatedBy: userID,
+				UpdatedBy: userID,
+			})
+		}
+	}
+	if len(meal.Addresses) == 0 {
+		for _, address := range defaults.Addresses {
+			meal.Addresses = append(meal.Addresses, model.MealEventAddress{
+				AddressID: address.AddressID,
+				CreatedBy: userID,
+				UpdatedBy: userID,
+			})
+		}
+	}
+	return nil
+}
+
+// validateMealTypeDefault checks that meal type defaults describe a usable event
+func validateMealTypeDefault(defaults *model.MealTypeDefault) error {
+	if _, _, err := parseStartTime(defaults.StartTime); err != nil {
+		return errors.NewValidationError("start time must be in HH:MM format", err)
+	}
+	if defaults.EventDuration <= 0 {
+		return errors.NewValidationError("event duration must be positive", nil)
+	}
+	if defaults.CutoffOffset < 0 {
+		return errors.NewValidationError("cutoff offset cannot be negative", nil)
+	}
+	return nil
+}
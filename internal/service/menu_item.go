@@ -25,11 +25,15 @@ func NewMenuItemService(
 	}
 }
 
-// GetMenuItems retrieves all menu items
-func (s *menuItemService) GetMenuItems(ctx context.Context) ([]model.MenuItem, error) {
-	return s.menuItemRepo.FindActive(ctx, map[string]interface{}{
+// GetMenuItems retrieves the active menu items that match the dietary filter
+func (s *menuItemService) GetMenuItems(ctx context.Context, filter MenuItemFilter) ([]model.MenuItem, error) {
+	items, err := s.menuItemRepo.FindActive(ctx, map[string]interface{}{
 		"is_active": true,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return filterMenuItems(items, filter), nil
 }
 
 // GetMenuItemByID retrieves a specific menu item by ID
@@ -51,6 +55,13 @@ func (s *menuItemService) CreateMenuItem(ctx context.Context, menuItem *model.Me
 		return errors.NewValidationError("description is required", nil)
 	}
 
+	if fields := validateQuantityLimits(menuItem); len(fields) > 0 {
+		return errors.NewValidationError("invalid quantity limits", nil).WithFields(fields...)
+	}
+	if fields := normalizeDietaryInfo(menuItem); len(fields) > 0 {
+		return errors.NewValidationError("invalid dietary information", nil).WithFields(fields...)
+	}
+
 	// Set created by
 	menuItem.CreatedBy = userID
 	menuItem.UpdatedBy = userID
@@ -58,35 +69,60 @@ func (s *menuItemService) CreateMenuItem(ctx context.Context, menuItem *model.Me
 	return s.menuItemRepo.Create(ctx, menuItem)
 }
 
-// UpdateMenuItem updates an existing menu item
-func (s *menuItemService) UpdateMenuItem(ctx context.Context, id uint, menuItem *model.MenuItem, userID uint) error {
+// UpdateMenuItem updates a menu item if it is still at the version the client read
+func (s *menuItemService) UpdateMenuItem(ctx context.Context, id uint, version int, menuItem *model.MenuItem, userID uint) error {
 	if menuItem == nil {
 		return errors.NewValidationError("menu item cannot be nil", nil)
 	}
 
 	existingMenuItem, err := s.menuItemRepo.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("menu item not found", err)
+	}
+	if err := checkVersion("menu item", existingMenuItem.Version, version); err != nil {
 		return err
 	}
 
+	if fields := validateQuantityLimits(menuItem); len(fields) > 0 {
+		return errors.NewValidationError("invalid quantity limits", nil).WithFields(fields...)
+	}
+	if fields := normalizeDietaryInfo(menuItem); len(fields) > 0 {
+		return errors.NewValidationError("invalid dietary information", nil).WithFields(fields...)
+	}
+
 	// Update fields
 	existingMenuItem.Name = menuItem.Name
 	existingMenuItem.Description = menuItem.Description
 	existingMenuItem.ImageURL = menuItem.ImageURL
+	existingMenuItem.MinQuantity = menuItem.MinQuantity
+	existingMenuItem.MaxQuantity = menuItem.MaxQuantity
+	existingMenuItem.Allergens = menuItem.Allergens
+	existingMenuItem.DietaryTags = menuItem.DietaryTags
 	existingMenuItem.UpdatedBy = userID
+	existingMenuItem.Version = version + 1
 
-	return s.menuItemRepo.Update(ctx, existingMenuItem)
+	if err := s.menuItemRepo.UpdateVersion(ctx, existingMenuItem, version); err != nil {
+		return versionError("menu item", "update", err)
+	}
+	*menuItem = *existingMenuItem
+	return nil
 }
 
-// DeleteMenuItem soft deletes a menu item
-func (s *menuItemService) DeleteMenuItem(ctx context.Context, id uint, userID uint) error {
+// DeleteMenuItem soft deletes a menu item if it is still at the version the client read
+func (s *menuItemService) DeleteMenuItem(ctx context.Context, id uint, version int, userID uint) error {
 	menuItem, err := s.menuItemRepo.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("menu item not found", err)
+	}
+	if err := checkVersion("menu item", menuItem.Version, version); err != nil {
 		return err
 	}
 
 	menuItem.UpdatedBy = userID
-	return s.menuItemRepo.Delete(ctx, menuItem)
+	if err := s.menuItemRepo.DeleteVersion(ctx, menuItem, version); err != nil {
+		return versionError("menu item", "delete", err)
+	}
+	return nil
 }
 
 // GetMenuItemsByCategory retrieves menu items by category
@@ -28,16 +28,26 @@ func NewMenuSetService(
 	}
 }
 
-// GetMenuSets retrieves all menu sets
+// GetMenuSets retrieves all menu sets with the dietary tags and allergens of their items
 func (s *menuSetService) GetMenuSets(ctx context.Context) ([]model.MenuSet, error) {
-	return s.menuRepo.FindActive(ctx, map[string]interface{}{
-		"is_active": true,
-	})
+	menuSets, err := s.menuRepo.FindActiveWithItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range menuSets {
+		menuSets[i].DeriveDietaryInfo()
+	}
+	return menuSets, nil
 }
 
-// GetMenuSetByID retrieves a specific menu set by ID
+// GetMenuSetByID retrieves a specific menu set by ID with the dietary tags and allergens of its items
 func (s *menuSetService) GetMenuSetByID(ctx context.Context, id uint) (*model.MenuSet, error) {
-	return s.menuRepo.FindByID(ctx, id)
+	menuSet, err := s.menuRepo.FindWithItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	menuSet.DeriveDietaryInfo()
+	return menuSet, nil
 }
 
 // CreateMenuSet creates a new menu set
@@ -57,14 +67,17 @@ func (s *menuSetService) CreateMenuSet(ctx context.Context, menuSet *model.MenuS
 	return s.menuRepo.Create(ctx, menuSet)
 }
 
-// UpdateMenuSet updates an existing menu set
-func (s *menuSetService) UpdateMenuSet(ctx context.Context, id uint, menuSet *model.MenuSet, userID uint) error {
+// UpdateMenuSet updates a menu set if it is still at the version the client read
+func (s *menuSetService) UpdateMenuSet(ctx context.Context, id uint, version int, menuSet *model.MenuSet, userID uint) error {
 	if menuSet == nil {
 		return errors.NewValidationError("menu set cannot be nil", nil)
 	}
 
-	existingMenuSet, err := s.menuRepo.FindByID(ctx, id)
+	existingMenuSet, err := s.menuRepo.FindWithItems(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("menu set not found", err)
+	}
+	if err := checkVersion("menu set", existingMenuSet.Version, version); err != nil {
 		return err
 	}
 
@@ -72,19 +85,31 @@ func (s *menuSetService) UpdateMenuSet(ctx context.Context, id uint, menuSet *mo
 	existingMenuSet.MenuSetName = menuSet.MenuSetName
 	existingMenuSet.MenuSetDescription = menuSet.MenuSetDescription
 	existingMenuSet.UpdatedBy = userID
+	existingMenuSet.Version = version + 1
 
-	return s.menuRepo.Update(ctx, existingMenuSet)
+	if err := s.menuRepo.UpdateVersion(ctx, existingMenuSet, version); err != nil {
+		return versionError("menu set", "update", err)
+	}
+	existingMenuSet.DeriveDietaryInfo()
+	*menuSet = *existingMenuSet
+	return nil
 }
 
-// DeleteMenuSet soft deletes a menu set
-func (s *menuSetService) DeleteMenuSet(ctx context.Context, id uint, userID uint) error {
+// DeleteMenuSet soft deletes a menu set if it is still at the version the client read
+func (s *menuSetService) DeleteMenuSet(ctx context.Context, id uint, version int, userID uint) error {
 	menuSet, err := s.menuRepo.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("menu set not found", err)
+	}
+	if err := checkVersion("menu set", menuSet.Version, version); err != nil {
 		return err
 	}
 
 	menuSet.UpdatedBy = userID
-	return s.menuRepo.Delete(ctx, menuSet)
+	if err := s.menuRepo.DeleteVersion(ctx, menuSet, version); err != nil {
+		return versionError("menu set", "delete", err)
+	}
+	return nil
 }
 
 // GetMenuItems retrieves all menu items
@@ -108,6 +133,9 @@ func (s *menuSetService) CreateMenuItem(ctx context.Context, menuItem *model.Men
 	if menuItem.Name == "" {
 		return errors.NewValidationError("menu item name is required", nil)
 	}
+	if fields := normalizeDietaryInfo(menuItem); len(fields) > 0 {
+		return errors.NewValidationError("invalid dietary information", nil).WithFields(fields...)
+	}
 
 	// Set created by
 	menuItem.CreatedBy = userID
@@ -116,35 +144,54 @@ func (s *menuSetService) CreateMenuItem(ctx context.Context, menuItem *model.Men
 	return s.menuItemRepo.Create(ctx, menuItem)
 }
 
-// UpdateMenuItem updates an existing menu item
-func (s *menuSetService) UpdateMenuItem(ctx context.Context, id uint, menuItem *model.MenuItem, userID uint) error {
+// UpdateMenuItem updates a menu item if it is still at the version the client read
+func (s *menuSetService) UpdateMenuItem(ctx context.Context, id uint, version int, menuItem *model.MenuItem, userID uint) error {
 	if menuItem == nil {
 		return errors.NewValidationError("menu item cannot be nil", nil)
 	}
 
 	existingMenuItem, err := s.menuItemRepo.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("menu item not found", err)
+	}
+	if err := checkVersion("menu item", existingMenuItem.Version, version); err != nil {
 		return err
 	}
+	if fields := normalizeDietaryInfo(menuItem); len(fields) > 0 {
+		return errors.NewValidationError("invalid dietary information", nil).WithFields(fields...)
+	}
 
 	// Update fields
 	existingMenuItem.Name = menuItem.Name
 	existingMenuItem.Description = menuItem.Description
 	existingMenuItem.ImageURL = menuItem.ImageURL
+	existingMenuItem.Allergens = menuItem.Allergens
+	existingMenuItem.DietaryTags = menuItem.DietaryTags
 	existingMenuItem.UpdatedBy = userID
+	existingMenuItem.Version = version + 1
 
-	return s.menuItemRepo.Update(ctx, existingMenuItem)
+	if err := s.menuItemRepo.UpdateVersion(ctx, existingMenuItem, version); err != nil {
+		return versionError("menu item", "update", err)
+	}
+	*menuItem = *existingMenuItem
+	return nil
 }
 
-// DeleteMenuItem soft deletes a menu item
-func (s *menuSetService) DeleteMenuItem(ctx context.Context, id uint, userID uint) error {
+// DeleteMenuItem soft deletes a menu item if it is still at the version the client read
+func (s *menuSetService) DeleteMenuItem(ctx context.Context, id uint, version int, userID uint) error {
 	menuItem, err := s.menuItemRepo.FindByID(ctx, id)
 	if err != nil {
+		return errors.NewNotFoundError("menu item not found", err)
+	}
+	if err := checkVersion("menu item", menuItem.Version, version); err != nil {
 		return err
 	}
 
 	menuItem.UpdatedBy = userID
-	return s.menuItemRepo.Delete(ctx, menuItem)
+	if err := s.menuItemRepo.DeleteVersion(ctx, menuItem, version); err != nil {
+		return versionError("menu item", "delete", err)
+	}
+	return nil
 }
 
 // AddItemToMenuSet adds a menu item to a menu set
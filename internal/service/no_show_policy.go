@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+// maxNoShowWindowDays bounds how far back a no-show policy may look
+const maxNoShowWindowDays = 365
+
+// NoShowRecord is one meal a user did not pick up
+type NoShowRecord struct {
+	MealRequestID uint      `json:"meal_request_id"`
+	MealEventID   uint      `json:"meal_event_id"`
+	MealName      string    `json:"meal_name"`
+	EventDate     time.Time `json:"event_date"`
+	MenuSetName   string    `json:"menu_set_name"`
+	Excused       bool      `json:"excused"`
+	Note          string    `json:"note,omitempty"` // why an admin excused it
+}
+
+// NoShowHistory lists a user's no-shows and the penalties they led to
+type NoShowHistory struct {
+	UserID       uint                  `json:"user_id"`
+	Name         string                `json:"name"`
+	Department   string                `json:"department"`
+	NoShows      int                   `json:"no_shows"` // unexcused
+	Excused      int                   `json:"excused"`
+	Restrictions []model.NoShowAction  `json:"restrictions"` // in effect now
+	Records      []NoShowRecord        `json:"records"`
+	Penalties    []model.NoShowPenalty `json:"penalties"`
+}
+
+// noShowPolicyService implements NoShowPolicyService interface
+type noShowPolicyService struct {
+	policyRepo   repository.NoShowPolicyRepository
+	requestRepo  repository.MealRequestRepository
+	userRepo     repository.UserRepository
+	notifService NotificationService
+	timeZone     string
+}
+
+// NewNoShowPolicyService creates a new instance of NoShowPolicyService
+func NewNoShowPolicyService(
+	policyRepo repository.NoShowPolicyRepository,
+	requestRepo repository.MealRequestRepository,
+	userRepo repository.UserRepository,
+	notifService NotificationService,
+	timeZone string,
+) NoShowPolicyService {
+	return &noShowPolicyService{
+		policyRepo:   policyRepo,
+		requestRepo:  requestRepo,
+		userRepo:     userRepo,
+		notifService: notifService,
+		timeZone:     timeZone,
+	}
+}
+
+// GetPolicies retrieves every no-show policy, the mildest first
+func (s *noShowPolicyService) GetPolicies(ctx context.Context) ([]model.NoShowPolicy, error) {
+	policies, err := s.policyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch no-show policies", err)
+	}
+	sortPolicies(policies)
+	return policies, nil
+}
+
+// CreatePolicy creates a no-show policy
+func (s *noShowPolicyService) CreatePolicy(ctx context.Context, policy *model.NoShowPolicy, adminID uint) error {
+	if err := validateNoShowPolicy(policy); err != nil {
+		return err
+	}
+
+	policy.ID = 0
+	policy.IsActive = true
+	policy.CreatedBy = adminID
+	policy.UpdatedBy = adminID
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		return errors.NewInternalError("failed to create no-show policy", err)
+	}
+	return nil
+}
+
+// UpdatePolicy replaces the rule of a no-show policy or switches it off. Penalties it already
+// triggered stay in effect.
+func (s *noShowPolicyService) UpdatePolicy(ctx context.Context, id uint, policy *model.NoShowPolicy, adminID uint) error {
+	existing, err := s.policyRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("no-show policy not found", err)
+	}
+	if err := validateNoShowPolicy(policy); err != nil {
+		return err
+	}
+
+	policy.ID = existing.ID
+	policy.CreatedBy = existing.CreatedBy
+	policy.CreatedAt = existing.CreatedAt
+	policy.UpdatedBy = adminID
+	if err := s.policyRepo.Update(ctx, policy); err != nil {
+		return errors.NewInternalError("failed to update no-show policy", err)
+	}
+	return nil
+}
+
+// EvaluatePolicies triggers the active no-show policies for users with enough unexcused no-shows.
+// A policy triggers for a user at most once per window, and a penalty is announced to the user
+// and, for approvals, to their line manager.
+func (s *noShowPolicyService) EvaluatePolicies(ctx context.Context, now time.Time) error {
+	policies, err := s.policyRepo.FindActive(ctx, nil)
+	if err != nil {
+		return err
+	}
+	sortPolicies(policies)
+
+	for i := range policies {
+		policy := &policies[i]
+		since := now.AddDate(0, 0, -policy.WindowDays)
+		counts, err := s.policyRepo.CountNoShowsSince(ctx, since)
+		if err != nil {
+			return err
+		}
+
+		for userID, noShows := range counts {
+			if noShows < policy.Threshold {
+				continue
+			}
+			triggered, err := s.policyRepo.HasPenaltySince(ctx, policy.ID, userID, since)
+			if err != nil {
+				return err
+			}
+			if triggered {
+				continue
+			}
+
+			penalty := &model.NoShowPenalty{
+				UserID:   userID,
+				PolicyID: policy.ID,
+				Action:   policy.Action,
+				NoShows:  noShows,
+				StartsAt: now,
+			}
+			if policy.Action.IsRestriction() {
+				endsAt := now.AddDate(0, 0, policy.DurationDays)
+				penalty.EndsAt = &endsAt
+			}
+			if err := s.policyRepo.CreatePenalty(ctx, penalty); err != nil {
+				return err
+			}
+			if err := s.announcePenalty(ctx, policy, penalty); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// announcePenalty tells a user about a triggered policy, and their line manager if they have to approve
+func (s *noShowPolicyService) announcePenalty(ctx context.Context, policy *model.NoShowPolicy, penalty *model.NoShowPenalty) error {
+	message := fmt.Sprintf("You did not pick up %d confirmed meals in the last %d days. Please withdraw meals you will not eat before the cutoff.",
+		penalty.NoShows, policy.WindowDays)
+	if penalty.EndsAt != nil {
+		until := formatMealTime(*penalty.EndsAt, utils.LoadLocation(s.timeZone))
+		switch penalty.Action {
+		case model.NoShowActionLimitStandingOrders:
+			message += fmt.Sprintf(" Your standing orders are paused until %s.", until)
+		case model.NoShowActionRequireApproval:
+			message += fmt.Sprintf(" Until %s, your line manager has to approve your new meal requests.", until)
+		}
+	}
+	if err := s.notifService.CreateAdminNotification(ctx, penalty.UserID, message, "high"); err != nil {
+		return err
+	}
+
+	if penalty.Action != model.NoShowActionRequireApproval {
+		return nil
+	}
+	user, err := s.userRepo.FindByID(ctx, penalty.UserID)
+	if err != nil || user.ManagerID == nil {
+		return nil
+	}
+	message = fmt.Sprintf("%s did not pick up %d confirmed meals. Until %s, their new meal requests need your approval.",
+		user.Name, penalty.NoShows, formatMealTime(*penalty.EndsAt, utils.LoadLocation(s.timeZone)))
+	return s.notifService.CreateAdminNotification(ctx, *user.ManagerID, message, "normal")
+}
+
+// GetNoShowHistory lists a user's no-shows and penalties. Users see their own history,
+// line managers that of their reports, and admins everyone's.
+func (s *noShowPolicyService) GetNoShowHistory(ctx context.Context, userID uint, actorID uint, isAdmin bool) (*NoShowHistory, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+	if !isAdmin && actorID != userID && (user.ManagerID == nil || *user.ManagerID != actorID) {
+		return nil, errors.NewForbiddenError("unauthorized to view this user's no-shows", nil)
+	}
+
+	requests, err := s.policyRepo.FindNoShowsByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch no-shows", err)
+	}
+	penalties, err := s.policyRepo.FindPenaltiesByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch no-show penalties", err)
+	}
+
+	history := &NoShowHistory{
+		UserID:       user.ID,
+		Name:         user.Name,
+		Department:   user.Department,
+		Restrictions: []model.NoShowAction{},
+		Records:      make([]NoShowRecord, 0, len(requests)),
+		Penalties:    penalties,
+	}
+	for _, request := range requests {
+		record := NoShowRecord{
+			MealRequestID: request.ID,
+			MealEventID:   request.MealEventID,
+			MealName:      request.MealEvent.Name,
+			EventDate:     request.MealEvent.EventDate,
+			MenuSetName:   request.MenuSet.MenuSetName,
+			Excused:       request.NoShowExcused,
+		}
+		if request.NoShowExcused {
+			record.Note = request.AdminReason
+			history.Excused++
+		} else {
+			history.NoShows++
+		}
+		history.Records = append(history.Records, record)
+	}
+
+	now := time.Now()
+	active := make(map[model.NoShowAction]bool)
+	for i := range penalties {
+		if penalties[i].IsActiveAt(now) && !active[penalties[i].Action] {
+			active[penalties[i].Action] = true
+			history.Restrictions = append(history.Restrictions, penalties[i].Action)
+		}
+	}
+	return history, nil
+}
+
+// ExcuseNoShow stops a no-show from counting towards no-show policies, e.g. after a sick day
+// was reported late. Penalties it contributed to are lifted separately.
+func (s *noShowPolicyService) ExcuseNoShow(ctx context.Context, requestID uint, reason string, adminID uint) (*model.MealRequest, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if request.Status != model.RequestStatusNoShow {
+		return nil, errors.NewValidationError("meal request is not a no-show", nil)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if err := s.requestRepo.ExcuseNoShow(ctx, request.ID, reason, adminID); err != nil {
+		return nil, errors.NewInternalError("failed to excuse no-show", err)
+	}
+	request.NoShowExcused = true
+	request.AdminReason = reason
+	request.UpdatedBy = adminID
+	return request, nil
+}
+
+// LiftPenalty ends a no-show penalty early after an appeal and tells the user
+func (s *noShowPolicyService) LiftPenalty(ctx context.Context, penaltyID uint, reason string, adminID uint) (*model.NoShowPenalty, error) {
+	penalty, err := s.policyRepo.FindPenaltyByID(ctx, penaltyID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("no-show penalty not found", err)
+	}
+	if penalty.LiftedAt != nil {
+		return nil, errors.NewConflictError("no-show penalty was already lifted", nil)
+	}
+
+	now := time.Now()
+	penalty.LiftedAt = &now
+	penalty.LiftedBy = &adminID
+	penalty.LiftReason = strings.TrimSpace(reason)
+	if err := s.policyRepo.UpdatePenalty(ctx, penalty); err != nil {
+		return nil, errors.NewInternalError("failed to lift no-show penalty", err)
+	}
+
+	if penalty.Action.IsRestriction() {
+		message := "Your no-show restriction was lifted."
+		if penalty.LiftReason != "" {
+			message += " Reason: " + penalty.LiftReason
+		}
+		if err := s.notifService.CreateAdminNotification(ctx, penalty.UserID, message, "normal"); err != nil {
+			return nil, err
+		}
+	}
+	return penalty, nil
+}
+
+// validateNoShowPolicy checks the rule of a no-show policy
+func validateNoShowPolicy(policy *model.NoShowPolicy) error {
+	var fields []errors.FieldError
+
+	policy.Name = strings.TrimSpace(policy.Name)
+	if policy.Name == "" {
+		fields = append(fields, errors.FieldError{Field: "name", Message: "name is required"})
+	}
+	if policy.Threshold < 1 {
+		fields = append(fields, errors.FieldError{Field: "threshold", Message: "threshold must be at least 1"})
+	}
+	if policy.WindowDays < 1 || policy.WindowDays > maxNoShowWindowDays {
+		fields = append(fields, errors.FieldError{Field: "window_days", Message: fmt.Sprintf("window must be 1 to %d days", maxNoShowWindowDays)})
+	}
+	switch {
+	case !policy.Action.IsValid():
+		fields = append(fields, errors.FieldError{Field: "action", Message: "action must be warn, limit_standing_orders or require_approval"})
+	case policy.Action.IsRestriction() && policy.DurationDays < 1:
+		fields = append(fields, errors.FieldError{Field: "duration_days", Message: "a restriction must last at least 1 day"})
+	case !policy.Action.IsRestriction():
+		policy.DurationDays = 0
+	}
+
+	if len(fields) > 0 {
+		return errors.NewValidationError("invalid no-show policy", nil).WithFields(fields...)
+	}
+	return nil
+}
+
+// sortPolicies orders policies by threshold, so that warnings precede the restrictions they announce
+func sortPolicies(policies []model.NoShowPolicy) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].Threshold != policies[j].Threshold {
+			return policies[i].Threshold < policies[j].Threshold
+		}
+		return policies[i].ID < policies[j].ID
+	})
+}
+
+// isRestricted reports whether a no-show penalty with the given action is in effect for a user
+func isRestricted(ctx context.Context, policyRepo repository.NoShowPolicyRepository, userID uint, action model.NoShowAction, at time.Time) (bool, error) {
+	userIDs, err := policyRepo.FindRestrictedUserIDs(ctx, action, at)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range userIDs {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -10,23 +10,66 @@ import (
 	"github.com/arafat-hasan/mealsync/internal/repository"
 )
 
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+// NotificationPage represents one page of a user's notification inbox
+type NotificationPage struct {
+	Items      []model.Notification `json:"items"`
+	NextCursor *uint                `json:"next_cursor"` // nil on the last page
+}
+
 // notificationService handles business logic for notification-related operations
 type notificationService struct {
 	notificationRepo repository.NotificationRepository
 	userRepo         repository.UserRepository
+	retentionDays    int
 }
 
 // NewNotificationService creates a new instance of NotificationService
-func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository) NotificationService {
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, retentionDays int) NotificationService {
 	return &notificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
+		retentionDays:    retentionDays,
 	}
 }
 
-// GetNotifications retrieves notifications for a user
-func (s *notificationService) GetNotifications(ctx context.Context, userID uint) ([]model.Notification, error) {
-	return s.notificationRepo.FindByUserID(ctx, userID)
+// GetNotifications retrieves one page of a user's notifications, newest first. The page size
+// defaults to 20 and is capped at 100.
+func (s *notificationService) GetNotifications(ctx context.Context, userID uint, filter model.NotificationFilter) (*NotificationPage, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.NewValidationError("from must be before to", nil)
+	}
+
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = defaultNotificationPageSize
+	}
+	if pageSize > maxNotificationPageSize {
+		pageSize = maxNotificationPageSize
+	}
+	// Fetch one extra row to learn whether another page exists
+	filter.Limit = pageSize + 1
+
+	notifications, err := s.notificationRepo.FindPageByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch notifications", err)
+	}
+
+	page := &NotificationPage{Items: notifications}
+	if len(notifications) > pageSize {
+		page.Items = notifications[:pageSize]
+		nextCursor := page.Items[pageSize-1].ID
+		page.NextCursor = &nextCursor
+	}
+	if page.Items == nil {
+		page.Items = []model.Notification{}
+	}
+
+	return page, nil
 }
 
 // CreateNotification creates a new notification
@@ -94,6 +137,55 @@ func (s *notificationService) DeleteNotification(ctx context.Context, notificati
 	return s.notificationRepo.Delete(ctx, notification)
 }
 
+// MarkAllNotificationsAsRead marks every unread notification of a user as read
+func (s *notificationService) MarkAllNotificationsAsRead(ctx context.Context, userID uint) (int64, error) {
+	count, err := s.notificationRepo.MarkAllAsRead(ctx, userID)
+	if err != nil {
+		return 0, errors.NewInternalError("failed to mark notifications as read", err)
+	}
+	return count, nil
+}
+
+// DeleteNotifications deletes several notifications of a user at once.
+// IDs that do not belong to the user are ignored.
+func (s *notificationService) DeleteNotifications(ctx context.Context, notificationIDs []uint, userID uint) (int64, error) {
+	if len(notificationIDs) == 0 {
+		return 0, errors.NewValidationError("at least one notification ID is required", nil)
+	}
+
+	count, err := s.notificationRepo.DeleteByIDs(ctx, userID, notificationIDs)
+	if err != nil {
+		return 0, errors.NewInternalError("failed to delete notifications", err)
+	}
+	return count, nil
+}
+
+// ArchiveNotifications moves notifications of a user into or out of the archive.
+// IDs that do not belong to the user are ignored.
+func (s *notificationService) ArchiveNotifications(ctx context.Context, notificationIDs []uint, userID uint, archived bool) (int64, error) {
+	if len(notificationIDs) == 0 {
+		return 0, errors.NewValidationError("at least one notification ID is required", nil)
+	}
+
+	count, err := s.notificationRepo.SetArchived(ctx, userID, notificationIDs, archived)
+	if err != nil {
+		return 0, errors.NewInternalError("failed to archive notifications", err)
+	}
+	return count, nil
+}
+
+// PurgeReadNotifications permanently deletes read notifications older than the retention period.
+// A retention period of zero or less keeps notifications forever.
+func (s *notificationService) PurgeReadNotifications(ctx context.Context, now time.Time) error {
+	if s.retentionDays <= 0 {
+		return nil
+	}
+
+	cutoff := now.AddDate(0, 0, -s.retentionDays)
+	_, err := s.notificationRepo.PurgeReadBefore(ctx, cutoff)
+	return err
+}
+
 // GetUnreadNotificationCount retrieves the count of unread notifications for a user
 func (s *notificationService) GetUnreadNotificationCount(ctx context.Context, userID uint) (int64, error) {
 	return s.notificationRepo.CountUnreadByUserID(ctx, userID)
@@ -163,6 +255,31 @@ func (s *notificationService) CreateMealReminderNotification(ctx context.Context
 	return s.notificationRepo.Create(ctx, notification)
 }
 
+// CreateEventAnnouncementNotification creates a notification announcing a newly published meal event
+func (s *notificationService) CreateEventAnnouncementNotification(ctx context.Context, userID uint, mealEventID uint, message string, deadline time.Time) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"meal_event_id": mealEventID,
+		"message":       message,
+		"deadline":      deadline.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	notification := &model.Notification{
+		UserID:    userID,
+		Type:      model.NotificationTypeEventInfo,
+		Payload:   payload,
+		Message:   message,
+		Read:      false,
+		Delivered: false,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+
+	return s.notificationRepo.Create(ctx, notification)
+}
+
 // CreateMealCancellationNotification creates a notification for meal cancellation
 func (s *notificationService) CreateMealCancellationNotification(ctx context.Context, userID uint, mealEventID uint, message string) error {
 	payload, err := json.Marshal(map[string]interface{}{
@@ -187,6 +304,31 @@ func (s *notificationService) CreateMealCancellationNotification(ctx context.Con
 	return s.notificationRepo.Create(ctx, notification)
 }
 
+// CreateMealChangeNotification creates a notification describing changes to a requested meal event
+func (s *notificationService) CreateMealChangeNotification(ctx context.Context, userID uint, mealEventID uint, message string, changes model.FieldChanges) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"meal_event_id": mealEventID,
+		"message":       message,
+		"changes":       changes,
+	})
+	if err != nil {
+		return err
+	}
+
+	notification := &model.Notification{
+		UserID:    userID,
+		Type:      model.NotificationTypeEventChange,
+		Payload:   payload,
+		Message:   message,
+		Read:      false,
+		Delivered: false,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+
+	return s.notificationRepo.Create(ctx, notification)
+}
+
 // CreateAdminNotification creates an admin notification
 func (s *notificationService) CreateAdminNotification(ctx context.Context, userID uint, message string, importance string) error {
 	payload, err := json.Marshal(map[string]interface{}{
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// standingOrderService handles standing orders and the requests they create
+type standingOrderService struct {
+	orderRepo    repository.StandingOrderRepository
+	mealRepo     repository.MealEventRepository
+	menuRepo     repository.MenuSetRepository
+	itemRepo     repository.MenuItemRepository
+	requestRepo  repository.MealRequestRepository
+	guestRepo    repository.GuestRequestRepository
+	userRepo     repository.UserRepository
+	policyRepo   repository.NoShowPolicyRepository
+	notifService NotificationService
+	timeZone     string
+}
+
+// NewStandingOrderService creates a new instance of StandingOrderService
+func NewStandingOrderService(
+	orderRepo repository.StandingOrderRepository,
+	mealRepo repository.MealEventRepository,
+	menuRepo repository.MenuSetRepository,
+	itemRepo repository.MenuItemRepository,
+	requestRepo repository.MealRequestRepository,
+	guestRepo repository.GuestRequestRepository,
+	userRepo repository.UserRepository,
+	policyRepo repository.NoShowPolicyRepository,
+	notifService NotificationService,
+	timeZone string,
+) StandingOrderService {
+	return &standingOrderService{
+		orderRepo:    orderRepo,
+		mealRepo:     mealRepo,
+		menuRepo:     menuRepo,
+		itemRepo:     itemRepo,
+		requestRepo:  requestRepo,
+		guestRepo:    guestRepo,
+		userRepo:     userRepo,
+		policyRepo:   policyRepo,
+		notifService: notifService,
+		timeZone:     timeZone,
+	}
+}
+
+// ListStandingOrders retrieves the standing orders of a user
+func (s *standingOrderService) ListStandingOrders(ctx context.Context, userID uint) ([]model.StandingOrder, error) {
+	return s.orderRepo.FindByUserID(ctx, userID)
+}
+
+// GetStandingOrder retrieves one of the user's standing orders
+func (s *standingOrderService) GetStandingOrder(ctx context.Context, id uint, userID uint) (*model.StandingOrder, error) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("standing order not found", err)
+	}
+	if order.UserID != userID {
+		return nil, errors.NewForbiddenError("unauthorized to access this standing order", nil)
+	}
+	return order, nil
+}
+
+// CreateStandingOrder creates a standing order for the user
+func (s *standingOrderService) CreateStandingOrder(ctx context.Context, order *model.StandingOrder, userID uint) error {
+	if err := s.validateStandingOrder(ctx, order); err != nil {
+		return err
+	}
+
+	order.ID = 0
+	order.UserID = userID
+	order.IsActive = true
+	order.CreatedBy = userID
+	order.UpdatedBy = userID
+	if err := s.orderRepo.SaveWithItems(ctx, order); err != nil {
+		return errors.NewInternalError("failed to save standing order", err)
+	}
+	return nil
+}
+
+// UpdateStandingOrder replaces one of the user's standing orders if it is still at the version the
+// client read. Requests it already created are kept.
+func (s *standingOrderService) UpdateStandingOrder(ctx context.Context, id uint, version int, order *model.StandingOrder, userID uint) error {
+	existing, err := s.GetStandingOrder(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("standing order", existing.Version, version); err != nil {
+		return err
+	}
+	if err := s.validateStandingOrder(ctx, order); err != nil {
+		return err
+	}
+
+	order.ID = existing.ID
+	order.UserID = existing.UserID
+	order.CreatedBy = existing.CreatedBy
+	order.CreatedAt = existing.CreatedAt
+	order.UpdatedBy = userID
+	order.Version = version
+	if err := s.orderRepo.SaveWithItems(ctx, order); err != nil {
+		return versionError("standing order", "save", err)
+	}
+	return nil
+}
+
+// DeleteStandingOrder removes one of the user's standing orders if it is still at the version the
+// client read. Requests it already created are kept.
+func (s *standingOrderService) DeleteStandingOrder(ctx context.Context, id uint, version int, userID uint) error {
+	order, err := s.GetStandingOrder(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion("standing order", order.Version, version); err != nil {
+		return err
+	}
+	if err := s.orderRepo.DeleteVersion(ctx, order, version); err != nil {
+		return versionError("standing order", "delete", err)
+	}
+	return nil
+}
+
+// ListPauses retrieves the user's standing order pauses
+func (s *standingOrderService) ListPauses(ctx context.Context, userID uint) ([]model.StandingOrderPause, error) {
+	return s.orderRepo.FindPausesByUserID(ctx, userID)
+}
+
+// CreatePause suspends the user's standing orders from the start to the end date, both included
+func (s *standingOrderService) CreatePause(ctx context.Context, pause *model.StandingOrderPause, userID uint) error {
+	if pause.StartDate.IsZero() || pause.EndDate.IsZero() {
+		return errors.NewValidationError("start and end date are required", nil)
+	}
+	pause.StartDate = calendarDate(pause.StartDate)
+	pause.EndDate = calendarDate(pause.EndDate)
+	if pause.EndDate.Before(pause.StartDate) {
+		return errors.NewValidationError("end date must not be before start date", nil).
+			WithFields(errors.FieldError{Field: "end_date", Message: "must not be before start_date"})
+	}
+
+	pause.ID = 0
+	pause.UserID = userID
+	if err := s.orderRepo.CreatePause(ctx, pause); err != nil {
+		return errors.NewInternalError("failed to save pause", err)
+	}
+	return nil
+}
+
+// DeletePause ends one of the user's pauses early or removes a planned one
+func (s *standingOrderService) DeletePause(ctx context.Context, id uint, userID uint) error {
+	pause, err := s.orderRepo.FindPauseByID(ctx, id)
+	if err != nil {
+		return errors.NewNotFoundError("pause not found", err)
+	}
+	if pause.UserID != userID {
+		return errors.NewForbiddenError("unauthorized to delete this pause", nil)
+	}
+	return s.orderRepo.DeletePause(ctx, pause)
+}
+
+// ApplyStandingOrders creates requests for a newly published meal event from the matching standing orders
+// and tells each user what was requested for them. Users with a request already, a pause on the event's
+// date, a no-show restriction, no seat left at their location or no matching order are skipped; of
+// several matching orders, the oldest that fits is used. It returns how many requests were created.
+func (s *standingOrderService) ApplyStandingOrders(ctx context.Context, mealEventID uint) (int, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return 0, err
+	}
+	if !meal.IsActive || !meal.Status.AcceptsRequests() || time.Now().After(meal.CutoffTime) {
+		return 0, nil
+	}
+
+	orders, err := s.orderRepo.FindActiveByMealType(ctx, meal.MealType)
+	if err != nil || len(orders) == 0 {
+		return 0, err
+	}
+
+	loc := mealLocation(meal, s.timeZone)
+	local := meal.EventDate.In(loc)
+	pauses, err := s.orderRepo.FindPausesOn(ctx, calendarDate(local))
+	if err != nil {
+		return 0, err
+	}
+	paused := make(map[uint]bool, len(pauses))
+	for _, pause := range pauses {
+		paused[pause.UserID] = true
+	}
+
+	// Standing orders of users with repeated no-shows are paused for a while
+	restrictedIDs, err := s.policyRepo.FindRestrictedUserIDs(ctx, model.NoShowActionLimitStandingOrders, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	restricted := make(map[uint]bool, len(restrictedIDs))
+	for _, userID := range restrictedIDs {
+		restricted[userID] = true
+	}
+
+	// Requests of users held for approval after repeated no-shows wait for their line manager
+	heldIDs, err := s.policyRepo.FindRestrictedUserIDs(ctx, model.NoShowActionRequireApproval, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	held := make(map[uint]bool, len(heldIDs))
+	for _, userID := range heldIDs {
+		held[userID] = true
+	}
+
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return 0, err
+	}
+	requested := make(map[uint]bool, len(requests))
+	for _, request := range requests {
+		requested[request.UserID] = requested[request.UserID] || request.Status.IsActive()
+	}
+
+	// Guests take seats too
+	guests, err := s.guestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	skipped := make(map[uint]string)
+	for i := range orders {
+		order := &orders[i]
+		if requested[order.UserID] || paused[order.UserID] || !orderMatchesDay(order, local.Weekday()) {
+			continue
+		}
+
+		user, err := s.userRepo.FindByID(ctx, order.UserID)
+		if err != nil || !user.IsActive {
+			continue
+		}
+
+		set, problem := chooseStandingOrderSet(meal, order)
+		if problem == "" && !servesAddress(meal, order.EventAddressID) {
+			problem = "your location is not served"
+		}
+		if problem == "" && isFull(meal, order.EventAddressID, requests, guests) {
+			problem = "no seats are left at your location"
+		}
+		if restricted[order.UserID] {
+			problem = "your standing orders are paused after missed meal pickups"
+		}
+		if problem != "" {
+			if _, ok := skipped[order.UserID]; !ok {
+				skipped[order.UserID] = problem
+			}
+			continue
+		}
+
+		items, err := s.standingOrderItems(ctx, order, set.MenuSetID)
+		if err != nil {
+			log.Printf("standing orders: failed to fetch items of menu set %d: %v", set.MenuSetID, err)
+			continue
+		}
+
+		orderID := order.ID
+		request := &model.MealRequest{
+			UserID:           order.UserID,
+			MealEventID:      meal.ID,
+			MenuSetID:        set.MenuSetID,
+			EventAddressID:   order.EventAddressID,
+			Status:           model.RequestStatusPending,
+			AwaitingApproval: held[order.UserID],
+			StandingOrderID:  &orderID,
+			CreatedBy:        order.UserID,
+			UpdatedBy:        order.UserID,
+		}
+		if err := s.requestRepo.SaveWithItems(ctx, request, items); err != nil {
+			log.Printf("standing orders: failed to request meal event %d for user %d: %v", meal.ID, order.UserID, err)
+			continue
+		}
+		requested[order.UserID] = true
+		requests = append(requests, *request)
+		created++
+		if request.AwaitingApproval {
+			requestApproval(ctx, s.userRepo, s.notifService, meal, order.UserID, s.timeZone)
+		}
+
+		message := fmt.Sprintf("Your standing order requested %s at %s for %s on %s. You can change or withdraw it until %s.",
+			set.MenuSet.MenuSetName, addressName(meal, order.EventAddressID), meal.Name,
+			formatMealTime(meal.EventDate, loc), formatMealTime(meal.CutoffTime, loc))
+		if request.AwaitingApproval {
+			message += " It waits for your line manager's approval after missed meal pickups."
+		}
+		if err := s.notifService.CreateEventAnnouncementNotification(ctx, order.UserID, meal.ID, message, meal.CutoffTime); err != nil {
+			log.Printf("standing orders: failed to notify user %d about meal event %d: %v", order.UserID, meal.ID, err)
+		}
+	}
+
+	for userID, problem := range skipped {
+		if requested[userID] {
+			continue
+		}
+		message := fmt.Sprintf("Your standing order could not request %s on %s because %s. Please request it yourself until %s.",
+			meal.Name, formatMealTime(meal.EventDate, loc), problem, formatMealTime(meal.CutoffTime, loc))
+		if err := s.notifService.CreateEventAnnouncementNotification(ctx, userID, meal.ID, message, meal.CutoffTime); err != nil {
+			log.Printf("standing orders: failed to notify user %d about meal event %d: %v", userID, meal.ID, err)
+		}
+	}
+	return created, nil
+}
+
+// validateStandingOrder checks a standing order and fills in its default fallback
+func (s *standingOrderService) validateStandingOrder(ctx context.Context, order *model.StandingOrder) error {
+	var fields []errors.FieldError
+
+	if !order.MealType.IsValid() {
+		fields = append(fields, errors.FieldError{Field: "meal_type", Message: "invalid meal type"})
+	}
+	if strings.TrimSpace(order.Weekdays) != "" {
+		if _, err := parseWeekdays(order.Weekdays); err != nil {
+			fields = append(fields, errors.FieldError{Field: "weekdays", Message: err.Error()})
+		}
+	}
+	if order.Fallback == "" {
+		order.Fallback = model.StandingOrderFallbackSkip
+	}
+	if !order.Fallback.IsValid() {
+		fields = append(fields, errors.FieldError{Field: "fallback", Message: "fallback must be skip or any_set"})
+	}
+
+	addresses, err := s.mealRepo.FindAddressesByIDs(ctx, []uint{order.EventAddressID})
+	if err != nil {
+		return errors.NewInternalError("failed to fetch event addresses", err)
+	}
+	if len(addresses) == 0 {
+		fields = append(fields, errors.FieldError{Field: "event_address_id", Message: "event address does not exist"})
+	}
+	if order.MenuSetID != nil {
+		if _, err := s.menuRepo.FindByID(ctx, *order.MenuSetID); err != nil {
+			fields = append(fields, errors.FieldError{Field: "menu_set_id", Message: "menu set does not exist"})
+		}
+	}
+
+	seen := make(map[uint]bool, len(order.Items))
+	for i, item := range order.Items {
+		field := fmt.Sprintf("items[%d].menu_item_id", i)
+		if seen[item.MenuItemID] {
+			fields = append(fields, errors.FieldError{Field: field, Message: "menu item is listed twice"})
+			continue
+		}
+		seen[item.MenuItemID] = true
+		if _, err := s.itemRepo.FindByID(ctx, item.MenuItemID); err != nil {
+			fields = append(fields, errors.FieldError{Field: field, Message: "menu item does not exist"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return errors.NewValidationError("invalid standing order", nil).WithFields(fields...)
+	}
+	return nil
+}
+
+// standingOrderItems lists the items of a menu set for a request, applying the order's deselections and notes
+func (s *standingOrderService) standingOrderItems(ctx context.Context, order *model.StandingOrder, menuSetID uint) ([]model.MealRequestItem, error) {
+	menuItems, err := s.menuRepo.FindMenuItems(ctx, menuSetID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[uint]model.StandingOrderItem, len(order.Items))
+	for _, item := range order.Items {
+		overrides[item.MenuItemID] = item
+	}
+
+	items := make([]model.MealRequestItem, 0, len(menuItems))
+	for _, menuItem := range menuItems {
+		item := model.MealRequestItem{
+			MenuItemID: menuItem.ID,
+			MenuSetID:  menuSetID,
+			IsSelected: true,
+			Quantity:   menuItem.DefaultQuantity(),
+			CreatedBy:  order.UserID,
+			UpdatedBy:  order.UserID,
+		}
+		if override, ok := overrides[menuItem.ID]; ok {
+			item.IsSelected = override.IsSelected
+			item.Notes = override.Notes
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// orderMatchesDay reports whether a standing order applies to events on a day of the week
+func orderMatchesDay(order *model.StandingOrder, day time.Weekday) bool {
+	if strings.TrimSpace(order.Weekdays) == "" {
+		return true
+	}
+	days, err := parseWeekdays(order.Weekdays)
+	return err == nil && days[day]
+}
+
+// chooseStandingOrderSet picks the menu set a standing order requests from a meal event,
+// or explains why it cannot request any
+func chooseStandingOrderSet(meal *model.MealEvent, order *model.StandingOrder) (*model.MealEventSet, string) {
+	if len(meal.MenuSets) == 0 {
+		return nil, "no menu set is offered"
+	}
+	if order.MenuSetID == nil {
+		return &meal.MenuSets[0], ""
+	}
+	for i := range meal.MenuSets {
+		if meal.MenuSets[i].MenuSetID == *order.MenuSetID {
+			return &meal.MenuSets[i], ""
+		}
+	}
+	if order.Fallback == model.StandingOrderFallbackAnySet {
+		return &meal.MenuSets[0], ""
+	}
+	return nil, "your preferred menu set is not offered"
+}
+
+// servesAddress reports whether a meal event is served at an address
+func servesAddress(meal *model.MealEvent, addressID uint) bool {
+	for _, address := range meal.Addresses {
+		if address.AddressID == addressID {
+			return true
+		}
+	}
+	return false
+}
+
+// addressName returns the name of one of a meal event's addresses
+func addressName(meal *model.MealEvent, addressID uint) string {
+	for _, address := range meal.Addresses {
+		if address.AddressID == addressID && address.Address.Address != "" {
+			return address.Address.Address
+		}
+	}
+	return fmt.Sprintf("address #%d", addressID)
+}
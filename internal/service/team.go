@@ -0,0 +1,531 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// maxReportingDepth bounds the walk up a reporting chain when looking for cycles
+const maxReportingDepth = 64
+
+// TeamMemberStatus shows whether one report requested a meal event
+type TeamMemberStatus struct {
+	UserID     uint               `json:"user_id"`
+	Name       string             `json:"name"`
+	EmployeeID string             `json:"employee_id"`
+	Department string             `json:"department"`
+	Request    *model.MealRequest `json:"request"` // the latest request, nil if none was made
+}
+
+// TeamEventStatus shows the meal requests of a manager's reports for one meal event
+type TeamEventStatus struct {
+	MealEventID  uint               `json:"meal_event_id"`
+	EventName    string             `json:"event_name"`
+	EventDate    time.Time          `json:"event_date"`
+	CutoffTime   time.Time          `json:"cutoff_time"`
+	Requested    int                `json:"requested"`
+	NotRequested int                `json:"not_requested"`
+	Members      []TeamMemberStatus `json:"members"`
+}
+
+// TeamParticipation counts how often one report requested meals within a date range
+type TeamParticipation struct {
+	UserID     uint    `json:"user_id"`
+	Name       string  `json:"name"`
+	EmployeeID string  `json:"employee_id"`
+	Requested  int     `json:"requested"`
+	Cancelled  int     `json:"cancelled"`
+	Rate       float64 `json:"rate"` // requested meals per held meal event
+}
+
+// TeamSummary summarizes the meal participation of a manager's reports within a date range
+type TeamSummary struct {
+	ManagerID uint                `json:"manager_id"`
+	StartDate time.Time           `json:"start_date"`
+	EndDate   time.Time           `json:"end_date"`
+	Events    int                 `json:"events"` // meal events that were not drafts or cancelled
+	Requested int                 `json:"requested"`
+	Cancelled int                 `json:"cancelled"`
+	Rate      float64             `json:"rate"`
+	Members   []TeamParticipation `json:"members"`
+}
+
+// ReportingLine assigns an employee to a line manager; an empty manager clears it
+type ReportingLine struct {
+	EmployeeID        string `json:"employee_id" example:"1001"`
+	ManagerEmployeeID string `json:"manager_employee_id" example:"1000"`
+}
+
+// ReportingLineOutcome reports what happened to one imported reporting line
+type ReportingLineOutcome struct {
+	EmployeeID        string `json:"employee_id"`
+	ManagerEmployeeID string `json:"manager_employee_id,omitempty"`
+	Result            string `json:"result" enums:"assigned,cleared,failed"`
+	Error             string `json:"error,omitempty"`
+}
+
+// ReportingLineImport summarizes a reporting line import; one line failing does not stop the others
+type ReportingLineImport struct {
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Outcomes  []ReportingLineOutcome `json:"outcomes"`
+}
+
+// teamService lets line managers order and withdraw meals for their reports
+type teamService struct {
+	userRepo       repository.UserRepository
+	mealRepo       repository.MealEventRepository
+	requestRepo    repository.MealRequestRepository
+	requestService MealRequestService
+	notifService   NotificationService
+	calendar       CalendarService
+	timeZone       string
+}
+
+// NewTeamService creates a new instance of TeamService
+func NewTeamService(
+	userRepo repository.UserRepository,
+	mealRepo repository.MealEventRepository,
+	requestRepo repository.MealRequestRepository,
+	requestService MealRequestService,
+	notifService NotificationService,
+	calendar CalendarService,
+	timeZone string,
+) TeamService {
+	return &teamService{
+		userRepo:       userRepo,
+		mealRepo:       mealRepo,
+		requestRepo:    requestRepo,
+		requestService: requestService,
+		notifService:   notifService,
+		calendar:       calendar,
+		timeZone:       timeZone,
+	}
+}
+
+// GetTeam retrieves the employees who report directly to a manager
+func (s *teamService) GetTeam(ctx context.Context, managerID uint) ([]model.User, error) {
+	reports, err := s.userRepo.FindReports(ctx, managerID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch team", err)
+	}
+	return reports, nil
+}
+
+// GetTeamEventStatus shows which of a manager's reports requested a meal event
+func (s *teamService) GetTeamEventStatus(ctx context.Context, managerID uint, mealEventID uint) (*TeamEventStatus, error) {
+	meal, err := s.mealRepo.FindByID(ctx, mealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	reports, err := s.GetTeam(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.requestRepo.FindByMealEventID(ctx, meal.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	// An active request wins over older cancelled ones
+	latest := make(map[uint]*model.MealRequest, len(requests))
+	for i := range requests {
+		request := &requests[i]
+		current, exists := latest[request.UserID]
+		if !exists || request.Status.IsActive() || (!current.Status.IsActive() && request.ID > current.ID) {
+			latest[request.UserID] = request
+		}
+	}
+
+	status := &TeamEventStatus{
+		MealEventID: meal.ID,
+		EventName:   meal.Name,
+		EventDate:   meal.EventDate,
+		CutoffTime:  meal.CutoffTime,
+		Members:     make([]TeamMemberStatus, 0, len(reports)),
+	}
+	for _, report := range reports {
+		member := TeamMemberStatus{
+			UserID:     report.ID,
+			Name:       report.Name,
+			EmployeeID: report.EmployeeID,
+			Department: report.Department,
+			Request:    latest[report.ID],
+		}
+		if member.Request != nil && member.Request.Status.IsActive() {
+			status.Requested++
+		} else {
+			status.NotRequested++
+		}
+		status.Members = append(status.Members, member)
+	}
+	return status, nil
+}
+
+// PlaceTeamRequest requests a meal for a report, e.g. one who is in meetings or offsite.
+// The request belongs to the report and records the manager as its creator.
+func (s *teamService) PlaceTeamRequest(ctx context.Context, managerID uint, reportID uint, request *model.MealRequest) error {
+	report, err := s.findReport(ctx, managerID, reportID)
+	if err != nil {
+		return err
+	}
+	if !report.IsActive {
+		return errors.NewValidationError("employee is not active", nil)
+	}
+	if request == nil {
+		return errors.NewValidationError("request cannot be nil", nil)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return errors.NewNotFoundError("meal event not found", err)
+	}
+
+	if err := s.requestService.CreateMealRequestFor(ctx, request, report.ID, managerID); err != nil {
+		return err
+	}
+
+	// The request stands even if the report cannot be told about it
+	loc := mealLocation(meal, s.timeZone)
+	message := fmt.Sprintf("%s requested %s on %s for you. You can change or withdraw it until %s.",
+		s.managerName(ctx, managerID), meal.Name, formatMealTime(meal.EventDate, loc), formatMealTime(meal.CutoffTime, loc))
+	if err := s.notifService.CreateMealConfirmationNotification(ctx, report.ID, meal.ID, message); err != nil {
+		log.Printf("team: failed to notify user %d about meal request %d: %v", report.ID, request.ID, err)
+	}
+	return nil
+}
+
+// WithdrawTeamRequest cancels a report's meal request before the cutoff
+func (s *teamService) WithdrawTeamRequest(ctx context.Context, managerID uint, requestID uint, reason string) (*model.MealRequest, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if _, err := s.findReport(ctx, managerID, request.UserID); err != nil {
+		return nil, err
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+	if time.Now().After(meal.CutoffTime) {
+		return nil, errors.NewValidationError("cutoff time has passed", nil)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "withdrawn by the line manager"
+	}
+	from := request.Status
+	if err := transitionRequest(ctx, s.requestRepo, request, model.RequestStatusCancelled, reason, &managerID); err != nil {
+		return nil, err
+	}
+	s.calendar.SendRequestWithdrawal(ctx, request.ID, from, reason)
+
+	loc := mealLocation(meal, s.timeZone)
+	message := fmt.Sprintf("%s withdrew your meal request for %s on %s. Reason: %s",
+		s.managerName(ctx, managerID), meal.Name, formatMealTime(meal.EventDate, loc), reason)
+	if err := s.notifService.CreateMealCancellationNotification(ctx, request.UserID, meal.ID, message); err != nil {
+		log.Printf("team: failed to notify user %d about meal request %d: %v", request.UserID, request.ID, err)
+	}
+	return request, nil
+}
+
+// GetPendingApprovals lists the requests of a manager's reports that wait for the manager's approval
+// after repeated no-shows
+func (s *teamService) GetPendingApprovals(ctx context.Context, managerID uint) ([]model.MealRequest, error) {
+	reports, err := s.GetTeam(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, 0, len(reports))
+	for _, report := range reports {
+		userIDs = append(userIDs, report.ID)
+	}
+
+	requests, err := s.requestRepo.FindAwaitingApproval(ctx, userIDs)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+	return requests, nil
+}
+
+// ApproveTeamRequest releases a report's request that waits for the manager's approval. Requests of
+// meal events that are confirmed already are approved right away; the others are confirmed with their event.
+func (s *teamService) ApproveTeamRequest(ctx context.Context, managerID uint, requestID uint) (*model.MealRequest, error) {
+	request, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal request not found", err)
+	}
+	if _, err := s.findReport(ctx, managerID, request.UserID); err != nil {
+		return nil, err
+	}
+	if request.Status != model.RequestStatusPending || !request.AwaitingApproval {
+		return nil, errors.NewValidationError("meal request does not wait for approval", nil)
+	}
+
+	meal, err := s.mealRepo.FindByID(ctx, request.MealEventID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("meal event not found", err)
+	}
+
+	if meal.Status == model.MealEventStatusConfirmed {
+		if err := transitionRequest(ctx, s.requestRepo, request, model.RequestStatusApproved, "approved by the line manager", &managerID); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := s.requestRepo.ClearAwaitingApproval(ctx, request.ID, managerID); err != nil {
+			if errors.Is(err, repository.ErrStatusChanged) {
+				return nil, errors.NewConflictError("meal request was changed by someone else", err)
+			}
+			return nil, errors.NewInternalError("failed to approve meal request", err)
+		}
+		request.AwaitingApproval = false
+		request.UpdatedBy = managerID
+	}
+
+	message := fmt.Sprintf("%s approved your meal request for %s on %s.",
+		s.managerName(ctx, managerID), meal.Name, formatMealTime(meal.EventDate, mealLocation(meal, s.timeZone)))
+	if err := s.notifService.CreateMealConfirmationNotification(ctx, request.UserID, meal.ID, message); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetTeamSummary counts the meals a manager's reports requested for the events within a date range
+func (s *teamService) GetTeamSummary(ctx context.Context, managerID uint, startDate, endDate time.Time) (*TeamSummary, error) {
+	if endDate.Before(startDate) {
+		return nil, errors.NewValidationError("end date must not be before start date", nil)
+	}
+
+	reports, err := s.GetTeam(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	meals, err := s.mealRepo.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal events", err)
+	}
+	held := make(map[uint]bool, len(meals))
+	for _, meal := range meals {
+		if meal.Status != model.MealEventStatusDraft && meal.Status != model.MealEventStatusCancelled {
+			held[meal.ID] = true
+		}
+	}
+
+	requests, err := s.requestRepo.FindByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch meal requests", err)
+	}
+
+	summary := &TeamSummary{
+		ManagerID: managerID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Events:    len(held),
+		Members:   make([]TeamParticipation, 0, len(reports)),
+	}
+	members := make(map[uint]int, len(reports))
+	for i, report := range reports {
+		members[report.ID] = i
+		summary.Members = append(summary.Members, TeamParticipation{
+			UserID:     report.ID,
+			Name:       report.Name,
+			EmployeeID: report.EmployeeID,
+		})
+	}
+
+	for _, request := range requests {
+		i, ok := members[request.UserID]
+		if !ok || !held[request.MealEventID] {
+			continue
+		}
+		switch {
+		case request.Status.IsActive():
+			summary.Members[i].Requested++
+			summary.Requested++
+		case request.Status == model.RequestStatusCancelled:
+			summary.Members[i].Cancelled++
+			summary.Cancelled++
+		}
+	}
+
+	if summary.Events > 0 {
+		for i := range summary.Members {
+			summary.Members[i].Rate = float64(summary.Members[i].Requested) / float64(summary.Events)
+		}
+		if len(reports) > 0 {
+			summary.Rate = float64(summary.Requested) / float64(summary.Events*len(reports))
+		}
+	}
+	return summary, nil
+}
+
+// SetManager sets or clears the line manager of an employee
+func (s *teamService) SetManager(ctx context.Context, userID uint, managerID *uint, adminID uint) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+
+	var manager *model.User
+	if managerID != nil {
+		manager, err = s.userRepo.FindByID(ctx, *managerID)
+		if err != nil {
+			return nil, errors.NewNotFoundError("manager not found", err)
+		}
+	}
+
+	if err := s.assignManager(ctx, user, manager, adminID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ImportReportingLines assigns employees to their line managers, both given by employee ID
+func (s *teamService) ImportReportingLines(ctx context.Context, lines []ReportingLine, adminID uint) (*ReportingLineImport, error) {
+	if len(lines) == 0 {
+		return nil, errors.NewValidationError("no reporting lines given", nil)
+	}
+	if len(lines) > maxBulkRequestUsers {
+		return nil, errors.NewValidationError(fmt.Sprintf("at most %d reporting lines can be imported at once", maxBulkRequestUsers), nil)
+	}
+
+	result := &ReportingLineImport{Outcomes: make([]ReportingLineOutcome, 0, len(lines))}
+	for _, line := range lines {
+		outcome := ReportingLineOutcome{
+			EmployeeID:        strings.TrimSpace(line.EmployeeID),
+			ManagerEmployeeID: strings.TrimSpace(line.ManagerEmployeeID),
+		}
+
+		err := s.importReportingLine(ctx, outcome.EmployeeID, outcome.ManagerEmployeeID, adminID)
+		switch {
+		case err != nil:
+			outcome.Result, outcome.Error = "failed", err.Error()
+			if appErr, ok := err.(*errors.AppError); ok {
+				outcome.Error = appErr.Message
+			}
+			result.Failed++
+		case outcome.ManagerEmployeeID == "":
+			outcome.Result = "cleared"
+			result.Succeeded++
+		default:
+			outcome.Result = "assigned"
+			result.Succeeded++
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+	return result, nil
+}
+
+// importReportingLine applies one imported reporting line
+func (s *teamService) importReportingLine(ctx context.Context, employeeID, managerEmployeeID string, adminID uint) error {
+	user, err := s.findByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+
+	var manager *model.User
+	if managerEmployeeID != "" {
+		if manager, err = s.findByEmployeeID(ctx, managerEmployeeID); err != nil {
+			return err
+		}
+	}
+	return s.assignManager(ctx, user, manager, adminID)
+}
+
+// assignManager stores a reporting line after checking it does not form a cycle.
+// Employees who gain a report become managers; admins keep their role.
+func (s *teamService) assignManager(ctx context.Context, user *model.User, manager *model.User, adminID uint) error {
+	var managerID *uint
+	if manager != nil {
+		if manager.ID == user.ID {
+			return errors.NewValidationError("an employee cannot manage themselves", nil)
+		}
+		if !manager.IsActive {
+			return errors.NewValidationError("manager is not active", nil)
+		}
+		if err := s.checkReportingCycle(ctx, user.ID, manager); err != nil {
+			return err
+		}
+		managerID = &manager.ID
+	}
+
+	if err := s.userRepo.UpdateManager(ctx, user.ID, managerID, adminID); err != nil {
+		return errors.NewInternalError("failed to update reporting line", err)
+	}
+	user.ManagerID = managerID
+	user.UpdatedBy = adminID
+
+	if manager != nil && manager.Role != model.UserRoleAdmin && manager.Role != model.UserRoleManager {
+		if err := s.userRepo.UpdateRole(ctx, manager.ID, model.UserRoleManager, adminID); err != nil {
+			return errors.NewInternalError("failed to make the employee a manager", err)
+		}
+		manager.Role = model.UserRoleManager
+	}
+	return nil
+}
+
+// checkReportingCycle rejects a manager who already reports to the employee, directly or indirectly
+func (s *teamService) checkReportingCycle(ctx context.Context, userID uint, manager *model.User) error {
+	current := manager
+	for depth := 0; current.ManagerID != nil; depth++ {
+		if *current.ManagerID == userID {
+			return errors.NewValidationError("the manager already reports to this employee", nil)
+		}
+		if depth >= maxReportingDepth {
+			return errors.NewValidationError("reporting chain is too deep", nil)
+		}
+		next, err := s.userRepo.FindByID(ctx, *current.ManagerID)
+		if err != nil {
+			return nil
+		}
+		current = next
+	}
+	return nil
+}
+
+// findReport finds an employee who reports directly to the manager
+func (s *teamService) findReport(ctx context.Context, managerID uint, userID uint) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+	if user.ManagerID == nil || *user.ManagerID != managerID {
+		return nil, errors.NewForbiddenError("employee does not report to you", nil)
+	}
+	return user, nil
+}
+
+// findByEmployeeID finds a user by the employee ID used in imports
+func (s *teamService) findByEmployeeID(ctx context.Context, employeeID string) (*model.User, error) {
+	number, err := strconv.Atoi(employeeID)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid employee ID "+strconv.Quote(employeeID), nil)
+	}
+	user, err := s.userRepo.FindByEmployeeID(ctx, number)
+	if err != nil {
+		return nil, errors.NewNotFoundError("employee "+employeeID+" not found", err)
+	}
+	return user, nil
+}
+
+// managerName names the manager in notifications to their reports
+func (s *teamService) managerName(ctx context.Context, managerID uint) string {
+	manager, err := s.userRepo.FindByID(ctx, managerID)
+	if err != nil || manager.Name == "" {
+		return "Your manager"
+	}
+	return manager.Name
+}
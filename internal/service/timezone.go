@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/model"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+	"github.com/arafat-hasan/mealsync/internal/utils"
+)
+
+// TimeZoneSettings describes the time zone a user's dates and times are shown in
+type TimeZoneSettings struct {
+	TimeZone             string `json:"time_zone" example:"Europe/Berlin"`
+	OrganizationTimeZone string `json:"organization_time_zone" example:"Asia/Dhaka"`
+	Effective            string `json:"effective" example:"Europe/Berlin"`
+}
+
+// timeZoneService resolves the time zones of users against the organization default
+type timeZoneService struct {
+	userRepo repository.UserRepository
+	timeZone string
+}
+
+// NewTimeZoneService creates a new instance of TimeZoneService
+func NewTimeZoneService(userRepo repository.UserRepository, timeZone string) TimeZoneService {
+	return &timeZoneService{
+		userRepo: userRepo,
+		timeZone: timeZone,
+	}
+}
+
+// OrganizationLocation returns the organization's default time zone
+func (s *timeZoneService) OrganizationLocation() *time.Location {
+	return utils.LoadLocation(s.timeZone)
+}
+
+// UserLocation returns the time zone a user prefers, falling back to the organization's
+func (s *timeZoneService) UserLocation(ctx context.Context, userID uint) (*time.Location, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+	return utils.LoadLocation(user.TimeZone, s.timeZone), nil
+}
+
+// GetTimeZoneSettings retrieves a user's time zone preference and the zone actually used
+func (s *timeZoneService) GetTimeZoneSettings(ctx context.Context, userID uint) (*TimeZoneSettings, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+
+	return &TimeZoneSettings{
+		TimeZone:             user.TimeZone,
+		OrganizationTimeZone: s.timeZone,
+		Effective:            utils.LoadLocation(user.TimeZone, s.timeZone).String(),
+	}, nil
+}
+
+// UpdateUserTimeZone sets a user's preferred time zone. An empty name falls back to the organization's.
+func (s *timeZoneService) UpdateUserTimeZone(ctx context.Context, userID uint, timeZone string) (*TimeZoneSettings, error) {
+	if timeZone != "" && !utils.IsValidTimeZone(timeZone) {
+		return nil, errors.NewValidationError("time zone must be an IANA name such as Asia/Dhaka", nil)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("user not found", err)
+	}
+
+	user.TimeZone = timeZone
+	user.UpdatedBy = userID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.NewInternalError("failed to update time zone", err)
+	}
+
+	return s.GetTimeZoneSettings(ctx, userID)
+}
+
+// mealLocation returns the time zone a meal event is held in: that of its first address
+// with a time zone, or the organization's
+func mealLocation(meal *model.MealEvent, orgTimeZone string) *time.Location {
+	names := make([]string, 0, len(meal.Addresses)+1)
+	for _, address := range meal.Addresses {
+		names = append(names, address.Address.TimeZone)
+	}
+	return utils.LoadLocation(append(names, orgTimeZone)...)
+}
+
+// formatMealTime formats an instant as wall clock time in loc, including the zone abbreviation
+func formatMealTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(mealDateLayout)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/arafat-hasan/mealsync/internal/errors"
+	"github.com/arafat-hasan/mealsync/internal/repository"
+)
+
+// checkVersion fails when a record changed since the client read the version it sent back
+func checkVersion(resource string, current int, expected int) error {
+	if current != expected {
+		return staleVersionError(resource)
+	}
+	return nil
+}
+
+// versionError reports losing a race with another writer like a stale version, and any other
+// failure as an internal error
+func versionError(resource string, action string, err error) error {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return staleVersionError(resource)
+	}
+	return errors.NewInternalError("failed to "+action+" "+resource, err)
+}
+
+// staleVersionError tells the client to reload a record someone else changed
+func staleVersionError(resource string) error {
+	return errors.NewPreconditionFailedError(resource+" was changed by someone else; reload it and try again", nil)
+}
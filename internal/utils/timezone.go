@@ -0,0 +1,72 @@
+package utils
+
+import "time"
+
+// LoadLocation returns the location of the first name that is a valid IANA time zone.
+// Empty and unknown names are skipped; UTC is returned when none is valid.
+func LoadLocation(names ...string) *time.Location {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// IsValidTimeZone reports whether name is a known IANA time zone such as "Asia/Dhaka"
+func IsValidTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// ParseDate parses a YYYY-MM-DD calendar date as midnight in loc
+func ParseDate(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// StartOfDay returns the first instant of the day t falls on in loc. In zones whose clocks
+// jump over midnight, that is the instant the clocks jump to.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	return AtClock(t.In(loc), 0, 0, loc)
+}
+
+// EndOfDay returns the last instant of the day t falls on in loc.
+// Days on which daylight saving time starts or ends are 23 or 25 hours long,
+// so the end is derived from the next midnight rather than by adding 24 hours.
+func EndOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return AtClock(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC), 0, 0, loc).Add(-time.Nanosecond)
+}
+
+// AtClock returns the instant at which wall clocks in loc show hour:minute on the calendar date of day.
+// The calendar date is read from day as is, without converting it to loc first.
+// Daylight saving changes are resolved as RFC 5545 does: a wall time skipped when the clocks go
+// forward is read with the offset from before the change, so 02:30 becomes 03:30, and a wall time
+// that occurs twice when the clocks go back resolves to its first occurrence.
+func AtClock(day time.Time, hour, minute int, loc *time.Location) time.Time {
+	wall := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.UTC)
+	guess := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	_, before := guess.Add(-12 * time.Hour).Zone()
+	_, after := guess.Add(12 * time.Hour).Zone()
+
+	// The offset from before the change yields the earlier of two valid instants
+	for _, offset := range []int{before, after} {
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if sameWallClock(candidate, wall) {
+			return candidate
+		}
+	}
+	return wall.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// sameWallClock reports whether t shows the date and time of day of wall, which is given in UTC
+func sameWallClock(t time.Time, wall time.Time) bool {
+	return t.Year() == wall.Year() && t.Month() == wall.Month() && t.Day() == wall.Day() &&
+		t.Hour() == wall.Hour() && t.Minute() == wall.Minute()
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the tests must not depend on the zone database of the host
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestDayLengthAcrossDaylightSaving(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name   string
+		day    time.Time
+		length time.Duration
+	}{
+		{"regular day", time.Date(2025, time.March, 29, 12, 0, 0, 0, berlin), 24 * time.Hour},
+		{"spring forward", time.Date(2025, time.March, 30, 12, 0, 0, 0, berlin), 23 * time.Hour},
+		{"fall back", time.Date(2025, time.October, 26, 12, 0, 0, 0, berlin), 25 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := StartOfDay(tt.day, berlin)
+			end := EndOfDay(tt.day, berlin)
+
+			if start.Hour() != 0 || start.Minute() != 0 || start.Day() != tt.day.Day() {
+				t.Errorf("StartOfDay = %v, want midnight of %v", start, tt.day)
+			}
+			if got := end.Add(time.Nanosecond).Sub(start); got != tt.length {
+				t.Errorf("day length = %v, want %v", got, tt.length)
+			}
+			if next := end.Add(time.Nanosecond); next.Day() == tt.day.Day() || next.Hour() != 0 {
+				t.Errorf("EndOfDay + 1ns = %v, want the next midnight", next)
+			}
+		})
+	}
+}
+
+func TestAtClockAcrossDaylightSaving(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name   string
+		day    time.Time
+		hour   int
+		minute int
+		want   time.Time // in UTC
+		clock  string    // wall clock in Berlin
+	}{
+		{
+			name: "winter time",
+			day:  time.Date(2025, time.March, 29, 0, 0, 0, 0, time.UTC),
+			hour: 2, minute: 30,
+			want:  time.Date(2025, time.March, 29, 1, 30, 0, 0, time.UTC),
+			clock: "02:30 CET",
+		},
+		{
+			name: "skipped when clocks go forward",
+			day:  time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC),
+			hour: 2, minute: 30,
+			want:  time.Date(2025, time.March, 30, 1, 30, 0, 0, time.UTC),
+			clock: "03:30 CEST",
+		},
+		{
+			name: "after clocks went forward",
+			day:  time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC),
+			hour: 12, minute: 0,
+			want:  time.Date(2025, time.March, 30, 10, 0, 0, 0, time.UTC),
+			clock: "12:00 CEST",
+		},
+		{
+			name: "repeated when clocks go back",
+			day:  time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC),
+			hour: 2, minute: 30,
+			want:  time.Date(2025, time.October, 26, 0, 30, 0, 0, time.UTC),
+			clock: "02:30 CEST",
+		},
+		{
+			name: "after clocks went back",
+			day:  time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC),
+			hour: 12, minute: 0,
+			want:  time.Date(2025, time.October, 26, 11, 0, 0, 0, time.UTC),
+			clock: "12:00 CET",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AtClock(tt.day, tt.hour, tt.minute, berlin)
+			if !got.Equal(tt.want) {
+				t.Errorf("AtClock = %v, want %v", got.UTC(), tt.want)
+			}
+			if clock := got.In(berlin).Format("15:04 MST"); clock != tt.clock {
+				t.Errorf("wall clock = %s, want %s", clock, tt.clock)
+			}
+		})
+	}
+}